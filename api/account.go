@@ -3,12 +3,14 @@ package api
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
 	db "github.com/sangketkit01/simple-bank/db/sqlc"
 	"github.com/sangketkit01/simple-bank/token"
+	"github.com/sangketkit01/simple-bank/util"
 )
 
 
@@ -70,19 +72,25 @@ func (server *Server) getAccount(ctx *gin.Context){
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if account.Owner != authPayload.Username{
+	if account.Owner != authPayload.Username && !util.CanReadAnyAccount(authPayload.Role){
 		err := errors.New("account doesn't belong to authenticate user")
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
 	}
 	ctx.JSON(http.StatusOK, account)
 }
 
 
 type listAccountRequest struct{
-	PageID int32 `form:"page_id" binding:"required,min=1"`
+	PageToken string `form:"page_token"`
 	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
 }
 
+type listAccountResponse struct{
+	Accounts []db.Account `json:"accounts"`
+	NextPageToken string `json:"next_page_token,omitempty"`
+}
+
 func (server *Server) listAccount(ctx *gin.Context){
 	var req listAccountRequest
 	if err := ctx.ShouldBindQuery(&req) ; err != nil{
@@ -93,13 +101,32 @@ func (server *Server) listAccount(ctx *gin.Context){
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	arg := db.ListAccountsParams{
 		Owner: authPayload.Username,
-		Limit: req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+		// fetch one extra row to know whether there is a next page
+		PageSize: req.PageSize + 1,
+	}
+
+	scope := fmt.Sprintf("accounts:%s", authPayload.Username)
+	if req.PageToken != ""{
+		cursor, err := server.cursorSigner.Decode(scope, req.PageToken)
+		if err != nil{
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.CursorID = sql.NullInt64{Int64: cursor.ID, Valid: true}
+		arg.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
 	}
+
 	accounts, err := server.store.ListAccounts(ctx, arg)
 	if err != nil{
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	ctx.JSON(http.StatusOK, accounts)
+
+	rsp := listAccountResponse{Accounts: accounts}
+	if len(accounts) > int(req.PageSize){
+		rsp.Accounts = accounts[:req.PageSize]
+		last := rsp.Accounts[len(rsp.Accounts)-1]
+		rsp.NextPageToken = server.cursorSigner.Encode(scope, util.Cursor{ID: last.ID, CreatedAt: last.CreatedAt})
+	}
+	ctx.JSON(http.StatusOK, rsp)
 }
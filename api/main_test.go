@@ -1,14 +1,19 @@
 package api
 
 import (
+	"context"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
 	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/token"
 	"github.com/sangketkit01/simple-bank/util"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func newTestServer(t *testing.T, store db.Store)*Server{
@@ -20,10 +25,38 @@ func newTestServer(t *testing.T, store db.Store)*Server{
 	server, err := NewServer(config, store)
 	require.NoError(t, err)
 
+	// the sessions of tokens created by the tests are not in the mock store
+	server.revocationChecker = newTestRevocationChecker()
+	require.NoError(t, server.setupRouter())
+
 	return server
 	
 }
 
+// newTestRevocationChecker returns a checker that treats every session as active
+func newTestRevocationChecker() token.RevocationChecker {
+	return token.NewCachedRevocationChecker(func(ctx context.Context, sessionID uuid.UUID) (token.SessionState, error) {
+		return token.SessionState{}, nil
+	}, time.Minute, time.Minute)
+}
+
+// newTestAPIKeyVerifier returns a verifier that rejects every API key
+func newTestAPIKeyVerifier() *token.APIKeyVerifier {
+	return token.NewAPIKeyVerifier(func(ctx context.Context, hashedKey string) (token.APIKeyState, error) {
+		return token.APIKeyState{Revoked: true}, nil
+	})
+}
+
+// expectVerifiedEmail lets every user pass verifiedEmailMiddleware
+func expectVerifiedEmail(store *mockdb.MockStore) {
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, username string) (db.User, error) {
+			return db.User{Username: username, IsEmailVerified: true}, nil
+		})
+}
+
 func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
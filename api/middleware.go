@@ -1,22 +1,28 @@
 package api
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
 	"github.com/sangketkit01/simple-bank/token"
+	"github.com/sangketkit01/simple-bank/util"
 )
 
 const(
 	authorizationHeaderKey = "authorization"
 	authorizationTypeBearer = "bearer"
+	authorizationTypeAPIKey = "apikey"
 	authorizationPayloadKey = "authorization_payload"
 )
 
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc{
+// authMiddleware authenticates the request with either a bearer access token or an API key.
+// A request made with an API key is limited to the scopes of the key, see scopeMiddleware.
+func authMiddleware(tokenMaker token.Maker, revocationChecker token.RevocationChecker, apiKeyVerifier *token.APIKeyVerifier) gin.HandlerFunc{
 	return func(ctx *gin.Context){
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(authorizationHeader) == 0{
@@ -33,6 +39,22 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc{
 		}
 
 		authorizationType := strings.ToLower(fields[0])
+		if authorizationType == authorizationTypeAPIKey{
+			payload, err := apiKeyVerifier.VerifyAPIKey(ctx, fields[1])
+			if err != nil{
+				if errors.Is(err, token.ErrInvalidAPIKey) || errors.Is(err, token.ErrExpiredAPIKey){
+					ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+					return
+				}
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+
+			ctx.Set(authorizationPayloadKey, payload)
+			ctx.Next()
+			return
+		}
+
 		if authorizationType != authorizationTypeBearer{
 			err := fmt.Errorf("unsupported authorization type %s",authorizationType)
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
@@ -40,13 +62,78 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc{
 		}
 
 		accessToken := fields[1]
-		payload, err := tokenMaker.VerifyToken(accessToken)
+		payload, err := tokenMaker.VerifyToken(accessToken, token.TokenTypeAccessToken)
 		if err != nil{
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
 			return
 		}
 
+		revoked, err := revocationChecker.IsRevoked(ctx, payload)
+		if err != nil{
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if revoked{
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(token.ErrRevokedToken))
+			return
+		}
+
 		ctx.Set(authorizationPayloadKey, payload)
 		ctx.Next()
 	}
-}
\ No newline at end of file
+}
+// roleMiddleware rejects requests whose token role is not one of the accessible roles.
+// It must run after authMiddleware.
+func roleMiddleware(accessibleRoles ...string) gin.HandlerFunc{
+	return func(ctx *gin.Context){
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if !util.HasRole(authPayload.Role, accessibleRoles){
+			err := fmt.Errorf("role %s is not allowed to access this resource", authPayload.Role)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// scopeMiddleware rejects requests made with an API key that was not granted the scope.
+// Requests made with an access token are not limited by scopes. It must run after authMiddleware.
+func scopeMiddleware(scope string) gin.HandlerFunc{
+	return func(ctx *gin.Context){
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if !authPayload.AllowsScope(scope){
+			err := fmt.Errorf("api key is missing the %s scope", scope)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// verifiedEmailMiddleware rejects requests of users who have not verified their email address yet.
+// It must run after authMiddleware.
+func verifiedEmailMiddleware(store db.Store) gin.HandlerFunc{
+	return func(ctx *gin.Context){
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		user, err := store.GetUser(ctx, authPayload.Username)
+		if err != nil{
+			if errors.Is(err, sql.ErrNoRows){
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(errors.New("user not found")))
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if !user.IsEmailVerified{
+			err := errors.New("email address must be verified first")
+			// the same answer as the FailedPrecondition of the gRPC API
+			ctx.AbortWithStatusJSON(http.StatusPreconditionFailed, errorResponse(err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
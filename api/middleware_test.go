@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -8,7 +9,9 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/sangketkit01/simple-bank/token"
+	"github.com/sangketkit01/simple-bank/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -18,9 +21,10 @@ func addAuthorization(
 	tokerMaker token.Maker,
 	authorizationType string,
 	username string,
+	role string,
 	duration time.Duration,
 ){
-	token, paylaod, err := tokerMaker.CreateToken(username, duration)
+	token, paylaod, err := tokerMaker.CreateToken(username, role, uuid.New(), duration, token.TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, paylaod)
 
@@ -37,7 +41,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "OK",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -54,7 +58,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "WrongAuthorizationType",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "unsupported", "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, "unsupported", "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -63,7 +67,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "InvalidAuthorizationFormat",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "", "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, "", "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -72,7 +76,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "ExpiredToken",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", -time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, -time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -88,7 +92,7 @@ func TestAuthMiddleware(t *testing.T) {
 			authPath := "/auth"
 			server.router.GET(
 				authPath,
-				authMiddleware(server.tokenMaker),
+				authMiddleware(server.tokenMaker, server.revocationChecker, server.apiKeyVerifier),
 				func (ctx *gin.Context)  {
 					ctx.JSON(http.StatusOK, gin.H{})
 				},
@@ -103,4 +107,192 @@ func TestAuthMiddleware(t *testing.T) {
 			tc.checkResponse(t, recorder)
 		})
 	}
-}
\ No newline at end of file
+}
+func TestAuthMiddlewareRevokedSession(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	authPath := "/auth"
+	server.router.GET(
+		authPath,
+		authMiddleware(server.tokenMaker, server.revocationChecker, server.apiKeyVerifier),
+		func(ctx *gin.Context) {
+			ctx.JSON(http.StatusOK, gin.H{})
+		},
+	)
+
+	accessToken, payload, err := server.tokenMaker.CreateToken("user", util.DepositorRole, uuid.New(), time.Minute, token.TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	err = server.revocationChecker.Revoke(context.Background(), payload.SessionID)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, authPath, nil)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestRoleMiddleware(t *testing.T) {
+	testCases := []struct {
+		name         string
+		role         string
+		expectedCode int
+	}{
+		{
+			name:         "Depositor",
+			role:         util.DepositorRole,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Banker",
+			role:         util.BankerRole,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Admin",
+			role:         util.AdminRole,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "UnknownRole",
+			role:         "",
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+
+			authPath := "/auth"
+			server.router.GET(
+				authPath,
+				authMiddleware(server.tokenMaker, server.revocationChecker, server.apiKeyVerifier),
+				roleMiddleware(util.DepositorRole, util.BankerRole),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, authPath, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "user", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
+
+func TestAuthMiddlewareAPIKey(t *testing.T) {
+	key, err := util.GenerateAPIKey()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		state         token.APIKeyState
+		lookupErr     error
+		authorization string
+		scope         string
+		expectedCode  int
+	}{
+		{
+			name:          "OK",
+			state:         token.APIKeyState{ID: 1, Username: "user", Role: util.DepositorRole, Scopes: []string{util.AccountsReadScope}, ExpiredAt: time.Now().Add(time.Hour)},
+			authorization: "ApiKey " + key,
+			scope:         util.AccountsReadScope,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			name:          "MissingScope",
+			state:         token.APIKeyState{ID: 1, Username: "user", Role: util.DepositorRole, Scopes: []string{util.AccountsReadScope}, ExpiredAt: time.Now().Add(time.Hour)},
+			authorization: "ApiKey " + key,
+			scope:         util.TransfersWriteScope,
+			expectedCode:  http.StatusForbidden,
+		},
+		{
+			name:          "RevokedKey",
+			state:         token.APIKeyState{Revoked: true},
+			authorization: "ApiKey " + key,
+			scope:         util.AccountsReadScope,
+			expectedCode:  http.StatusUnauthorized,
+		},
+		{
+			name:          "ExpiredKey",
+			state:         token.APIKeyState{ID: 1, Username: "user", Role: util.DepositorRole, Scopes: []string{util.AccountsReadScope}, ExpiredAt: time.Now().Add(-time.Minute)},
+			authorization: "ApiKey " + key,
+			scope:         util.AccountsReadScope,
+			expectedCode:  http.StatusUnauthorized,
+		},
+		{
+			name:          "MalformedKey",
+			authorization: "ApiKey not-a-key",
+			scope:         util.AccountsReadScope,
+			expectedCode:  http.StatusUnauthorized,
+		},
+		{
+			name:          "LookupError",
+			lookupErr:     fmt.Errorf("connection refused"),
+			authorization: "ApiKey " + key,
+			scope:         util.AccountsReadScope,
+			expectedCode:  http.StatusInternalServerError,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+			server.apiKeyVerifier = token.NewAPIKeyVerifier(func(ctx context.Context, hashedKey string) (token.APIKeyState, error) {
+				require.Equal(t, util.HashAPIKey(key), hashedKey)
+				return tc.state, tc.lookupErr
+			})
+
+			authPath := "/auth"
+			server.router.GET(
+				authPath,
+				authMiddleware(server.tokenMaker, server.revocationChecker, server.apiKeyVerifier),
+				scopeMiddleware(tc.scope),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, authPath, nil)
+			require.NoError(t, err)
+			request.Header.Set(authorizationHeaderKey, tc.authorization)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
+
+func TestScopeMiddlewareAccessToken(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	authPath := "/auth"
+	server.router.GET(
+		authPath,
+		authMiddleware(server.tokenMaker, server.revocationChecker, server.apiKeyVerifier),
+		scopeMiddleware(util.TransfersWriteScope),
+		func(ctx *gin.Context) {
+			ctx.JSON(http.StatusOK, gin.H{})
+		},
+	)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, authPath, nil)
+	require.NoError(t, err)
+
+	// access tokens act with every right of their role
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
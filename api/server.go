@@ -1,12 +1,15 @@
 package api
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/ratelimit"
 	"github.com/sangketkit01/simple-bank/token"
 	"github.com/sangketkit01/simple-bank/util"
 )
@@ -16,44 +19,84 @@ type Server struct{
 	config util.Config
 	store db.Store
 	tokenMaker token.Maker
+	cursorSigner *util.CursorSigner
+	revocationChecker token.RevocationChecker
+	apiKeyVerifier *token.APIKeyVerifier
+	loginLimiter *ratelimit.LoginLimiter
 	router *gin.Engine
 }
 
 // NewServer creates a new HTTP server and setup routing
 func NewServer(config util.Config, store db.Store) (*Server, error){
-	tokerMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+	tokerMaker, err := token.NewMaker(config)
 	if err != nil{
 		return nil, fmt.Errorf("cannot create token maker: %w",err)
 	}
+	cursorSigner, err := util.NewCursorSigner(config.TokenSymmetricKey)
+	if err != nil{
+		return nil, fmt.Errorf("cannot create cursor signer: %w",err)
+	}
+	revocationChecker := token.NewRevocationChecker(config, func(ctx context.Context, sessionID uuid.UUID) (token.SessionState, error){
+		state, err := db.SessionAuthState(ctx, store, sessionID)
+		return token.SessionState{Blocked: state.IsBlock, PasswordChangedAt: state.PasswordChangedAt}, err
+	})
+	apiKeyVerifier := token.NewAPIKeyVerifier(func(ctx context.Context, hashedKey string) (token.APIKeyState, error){
+		state, err := db.APIKeyAuthState(ctx, store, hashedKey)
+		return token.APIKeyState{
+			ID: state.ID,
+			Username: state.Username,
+			Role: state.Role,
+			Scopes: state.Scopes,
+			Revoked: state.IsRevoked,
+			ExpiredAt: state.ExpiredAt,
+		}, err
+	})
 	server := &Server{
 		config: config,
 		store: store,
 		tokenMaker: tokerMaker,
+		cursorSigner: cursorSigner,
+		revocationChecker: revocationChecker,
+		apiKeyVerifier: apiKeyVerifier,
+		loginLimiter: ratelimit.NewLoginLimiter(config),
 	}
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate) ; ok{
 		v.RegisterValidation("currency",validCurrency)
 	}
 
-	server.setupRouter()
+	err = server.setupRouter()
+	if err != nil{
+		return nil, fmt.Errorf("cannot setup router: %w",err)
+	}
 	return server, nil
 }
 
-func (server *Server) setupRouter(){
+func (server *Server) setupRouter() error{
 	router := gin.Default()
+	// only a proxy on the same host may set the client IP, the login limiter is keyed on it
+	err := router.SetTrustedProxies([]string{"127.0.0.1", "::1"})
+	if err != nil{
+		return err
+	}
+
 	router.POST("/users",server.createUser)
 	router.POST("/users/login",server.loginUser)
 	router.POST("/tokens/renew_access",server.renewAccessToken)
 
-	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
+	authRoutes := router.Group("/").Use(
+		authMiddleware(server.tokenMaker, server.revocationChecker, server.apiKeyVerifier),
+		roleMiddleware(util.DepositorRole, util.BankerRole),
+	)
 
-	authRoutes.POST("/accounts",server.createAccount)
-	authRoutes.GET("/accounts/:id",server.getAccount)
-	authRoutes.GET("/accounts",server.listAccount)
+	authRoutes.POST("/accounts",scopeMiddleware(util.AccountsWriteScope),verifiedEmailMiddleware(server.store),server.createAccount)
+	authRoutes.GET("/accounts/:id",scopeMiddleware(util.AccountsReadScope),server.getAccount)
+	authRoutes.GET("/accounts",scopeMiddleware(util.AccountsReadScope),server.listAccount)
 
-	authRoutes.POST("/transfers",server.createTransfer)
+	authRoutes.POST("/transfers",scopeMiddleware(util.TransfersWriteScope),verifiedEmailMiddleware(server.store),server.createTransfer)
 
 	server.router = router
+	return nil
 }
 
 // Start tuns the HTTP server on a specific address
@@ -1,12 +1,14 @@
 package api
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/token"
 )
 
 type renewAccessTokenRequest struct {
@@ -14,8 +16,11 @@ type renewAccessTokenRequest struct {
 }
 
 type renewAccessTokenResponse struct {
-	AccessToken          string    `json:"access_token"`
-	AccessTokenExpiredAt time.Time `json:"access_token_expired_at"`
+	SessionID             uuid.UUID `json:"session_id"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiredAt  time.Time `json:"access_token_expired_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiredAt time.Time `json:"refresh_token_expired_at"`
 }
 
 func (server *Server) renewAccessToken(ctx *gin.Context) {
@@ -25,51 +30,81 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		return
 	}
 
-	refreshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken)
+	refreshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken, token.TokenTypeRefreshToken)
 	if err != nil{
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
 
-	session, err := server.store.GetSession(ctx, refreshPayload.ID)
+	session, blockedSessionIDs, err := db.ValidRefreshSession(ctx, server.store, db.ValidRefreshSessionParams{
+		SessionID:    refreshPayload.SessionID,
+		Username:     refreshPayload.Username,
+		RefreshToken: req.RefreshToken,
+	})
 	if err != nil {
-		if err == db.ErrRecordNotFound {
+		switch {
+		case err == db.ErrRecordNotFound,
+			errors.Is(err, db.ErrSessionBlocked),
+			errors.Is(err, db.ErrSessionUserMismatch):
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
+		case errors.Is(err, db.ErrRefreshTokenReused):
+			server.refreshTokenReused(ctx, session, blockedSessionIDs)
+		case errors.Is(err, db.ErrSessionTokenMismatch),
+			errors.Is(err, db.ErrSessionExpired):
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		}
-
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	if session.IsBlock{
-		err := fmt.Errorf("blocked session")
-		ctx.JSON(http.StatusNotFound, errorResponse(err))
-		return
-	}
-
-	if session.Username != refreshPayload.Username{
-		err := fmt.Errorf("incorrect session user")
-		ctx.JSON(http.StatusNotFound, errorResponse(err))
+	// the new refresh token keeps the expiry of the session it replaces,
+	// so rotating does not extend the lifetime of a login
+	newSessionID, err := uuid.NewRandom()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	if session.RefreshToken != req.RefreshToken{
-		err := fmt.Errorf("mismatched session token")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	refreshToken, newRefreshPayload, err := server.tokenMaker.CreateToken(
+		refreshPayload.Username,
+		refreshPayload.Role,
+		newSessionID,
+		time.Until(session.ExpiredAt),
+		token.TokenTypeRefreshToken,
+	)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	if time.Now().After(session.ExpiredAt){
-		err := fmt.Errorf("expired session")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	result, err := server.store.RotateSessionTx(ctx, db.RotateSessionTxParams{
+		Session: session,
+		NewSession: db.CreateSessionParams{
+			ID:           newSessionID,
+			Username:     session.Username,
+			RefreshToken: refreshToken,
+			UserAgent:    ctx.Request.UserAgent(),
+			ClientIp:     ctx.ClientIP(),
+			IsBlock:      false,
+			ExpiredAt:    newRefreshPayload.ExpiredAt,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRefreshTokenReused) {
+			server.refreshTokenReused(ctx, session, result.BlockedSessionIDs)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-
 	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
 		refreshPayload.Username,
-		server.config.RefreshTokenDuration,
+		refreshPayload.Role,
+		result.Session.ID,
+		server.config.AccessTokenDuration,
+		token.TokenTypeAccessToken,
 	)
 
 	if err != nil {
@@ -78,9 +113,30 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 	}
 
 	response := renewAccessTokenResponse{
+		SessionID:             result.Session.ID,
 		AccessToken:           accessToken,
 		AccessTokenExpiredAt:  accessPayload.ExpiredAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiredAt: result.Session.ExpiredAt,
 	}
 
 	ctx.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// refreshTokenReused signs out the family of a reused refresh token and writes the response to the reuse
+func (server *Server) refreshTokenReused(ctx *gin.Context, session db.Session, blockedSessionIDs []uuid.UUID) {
+	err := token.RevokeReusedFamily(ctx, server.revocationChecker, token.RefreshTokenReuse{
+		Username:          session.Username,
+		SessionID:         session.ID,
+		FamilyID:          session.FamilyID,
+		BlockedSessionIDs: blockedSessionIDs,
+		ClientIP:          ctx.ClientIP(),
+		UserAgent:         ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusUnauthorized, errorResponse(db.ErrRefreshTokenReused))
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRenewAccessTokenAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		isBlock       bool
+		isRotated     bool
+		rotateTimes   int
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "OK",
+			rotateTimes: 1,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp renewAccessTokenResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.NotEmpty(t, rsp.AccessToken)
+				// the renewed token lives as long as an access token, not a refresh token
+				require.WithinDuration(t, time.Now().Add(time.Minute), rsp.AccessTokenExpiredAt, time.Second)
+				require.NotEmpty(t, rsp.RefreshToken)
+				require.WithinDuration(t, time.Now().Add(time.Hour), rsp.RefreshTokenExpiredAt, time.Second)
+			},
+		},
+		{
+			name:    "BlockedSession",
+			isBlock: true,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "ReusedRefreshToken",
+			isRotated: true,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), db.ErrRefreshTokenReused.Error())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+
+			refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, uuid.New(), time.Hour, token.TokenTypeRefreshToken)
+			require.NoError(t, err)
+
+			session := db.Session{
+				ID:           refreshPayload.SessionID,
+				Username:     user.Username,
+				RefreshToken: refreshToken,
+				IsBlock:      tc.isBlock,
+				IsRotated:    tc.isRotated,
+				ExpiredAt:    refreshPayload.ExpiredAt,
+				FamilyID:     refreshPayload.SessionID,
+			}
+			store.EXPECT().
+				GetSession(gomock.Any(), gomock.Eq(session.ID)).
+				Times(1).
+				Return(session, nil)
+			blockFamilyTimes := 0
+			if tc.isRotated {
+				blockFamilyTimes = 1
+			}
+			store.EXPECT().
+				BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).
+				Times(blockFamilyTimes).
+				Return([]uuid.UUID{session.ID}, nil)
+			store.EXPECT().
+				RotateSessionTx(gomock.Any(), gomock.Any()).
+				Times(tc.rotateTimes).
+				DoAndReturn(func(_ context.Context, arg db.RotateSessionTxParams) (db.RotateSessionTxResult, error) {
+					newSession := db.Session{
+						ID:           arg.NewSession.ID,
+						Username:     arg.NewSession.Username,
+						RefreshToken: arg.NewSession.RefreshToken,
+						ExpiredAt:    arg.NewSession.ExpiredAt,
+						FamilyID:     session.FamilyID,
+					}
+					return db.RotateSessionTxResult{Session: newSession}, nil
+				})
+
+			data, err := json.Marshal(renewAccessTokenRequest{RefreshToken: refreshToken})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -8,15 +8,21 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	db "github.com/sangketkit01/simple-bank/db/sqlc"
 	"github.com/sangketkit01/simple-bank/token"
+	"github.com/sangketkit01/simple-bank/val"
 )
 
+const idempotencyKeyHeader = "Idempotency-Key"
+
 type transferRequest struct{
 	FromAccountID    int64 `json:"from_account_id" binding:"required,min=1"`
 	ToAccountID int64 `json:"to_account_id" binding:"required,min=1"`
 	Amount int64 `json:"amount" binding:"required,gt=0"`
 	Currency string `json:"currency" binding:"required,currency"`
+	// QuoteID comes from the QuoteTransfer RPC and allows crediting an account of another currency
+	QuoteID string `json:"quote_id" binding:"omitempty,uuid"`
 }
 
 func (server *Server) createTransfer(ctx *gin.Context){
@@ -27,6 +33,20 @@ func (server *Server) createTransfer(ctx *gin.Context){
 		return
 	}
 
+	if err := val.ValidateCurrencyAmount(req.Amount, req.Currency) ; err != nil{
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	idempotencyKey := ctx.GetHeader(idempotencyKeyHeader)
+	if idempotencyKey != ""{
+		if err := val.ValidateIdempotencyKey(idempotencyKey) ; err != nil{
+			err = fmt.Errorf("invalid %s header: %w", idempotencyKeyHeader, err)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
 	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
 	if !valid{
 		log.Println("from account invalid")
@@ -40,7 +60,13 @@ func (server *Server) createTransfer(ctx *gin.Context){
 		return
 	}
 	
-	_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
+	var quoteID uuid.NullUUID
+	if req.QuoteID != ""{
+		quoteID = uuid.NullUUID{UUID: uuid.MustParse(req.QuoteID), Valid: true}
+		_, valid = server.loadAccount(ctx, req.ToAccountID)
+	}else{
+		_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
+	}
 	if !valid{
 		log.Println("to account invalid")
 		return
@@ -50,10 +76,30 @@ func (server *Server) createTransfer(ctx *gin.Context){
 		FromAccountID: req.FromAccountID,
 		ToAccountID: req.ToAccountID,
 		Amount: req.Amount,
+		QuoteID: quoteID,
+		Username: authPayload.Username,
+		IdempotencyKey: idempotencyKey,
+		IdempotencyKeyDuration: server.config.IdempotencyKeyDuration,
 	}
 
 	result, err := server.store.TransferTx(ctx, arg)
 	if err != nil{
+		if errors.Is(err, db.ErrInsufficientFunds){
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrIdempotencyKeyReused){
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrInvalidQuote){
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrQuoteMismatch){
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
 		log.Println("transfer error:", err)
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -64,6 +110,21 @@ func (server *Server) createTransfer(ctx *gin.Context){
 
 
 func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Account,bool){
+	account, valid := server.loadAccount(ctx, accountID)
+	if !valid{
+		return account, false
+	}
+
+	if account.Currency != currency{
+		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, currency)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return account, false
+	}
+
+	return account, true
+}
+
+func (server *Server) loadAccount(ctx *gin.Context, accountID int64) (db.Account,bool){
 	account, err := server.store.GetAccount(ctx, accountID)
 	if err != nil{
 		if err == sql.ErrNoRows{
@@ -75,11 +136,5 @@ func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency s
 		return account, false
 	}
 
-	if account.Currency != currency{
-		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, currency)
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return account, false
-	}
-
 	return account, true
 }
\ No newline at end of file
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.uber.org/mock/gomock"
 	"github.com/stretchr/testify/require"
 	mockdb "github.com/sangketkit01/simple-bank/db/mock"
@@ -113,7 +114,7 @@ func TestCreateTransfer_Success(t *testing.T) {
 	maker, err := token.NewPasetoMaker(util.RandomString(32))
 	require.NoError(t, err)
 	
-	token,payload ,  err := maker.CreateToken(account1.Owner, time.Hour)
+	token,payload ,  err := maker.CreateToken(account1.Owner, util.DepositorRole, uuid.New(), time.Hour, token.TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -141,7 +142,7 @@ func TestCreateTransfer_Success(t *testing.T) {
 	
 	// ตั้งค่า gin router
 	router := gin.Default()
-	router.Use(authMiddleware(maker))
+	router.Use(authMiddleware(maker, newTestRevocationChecker(), newTestAPIKeyVerifier()))
 	router.POST("/transfers", server.createTransfer)
 	
 	// ทำการส่ง request
@@ -167,7 +168,7 @@ func TestCreateTransfer_InvalidRequest(t *testing.T) {
 	maker, err := token.NewPasetoMaker(util.RandomString(32))
 	require.NoError(t, err)
 	
-	token, payload, err := maker.CreateToken(util.RandomOwner(), time.Hour)
+	token, payload, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Hour, token.TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -244,6 +245,19 @@ func TestCreateTransfer_InvalidRequest(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name: "quote_id ไม่ถูกต้อง",
+			requestBody: gin.H{
+				"from_account_id": 1,
+				"to_account_id":   2,
+				"amount":          100,
+				"currency":        "USD",
+				"quote_id":        "INVALID",
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 	}
 
 	for i := range testCases {
@@ -268,7 +282,7 @@ func TestCreateTransfer_InvalidRequest(t *testing.T) {
 			
 			// ตั้งค่า gin router
 			router := gin.Default()
-			router.Use(authMiddleware(maker))
+			router.Use(authMiddleware(maker, newTestRevocationChecker(), newTestAPIKeyVerifier()))
 			router.POST("/transfers", server.createTransfer)
 			
 			// ทำการส่ง request
@@ -292,7 +306,7 @@ func TestCreateTransfer_FromAccountNotFound(t *testing.T) {
 	maker, err := token.NewPasetoMaker(util.RandomString(32))
 	require.NoError(t, err)
 	
-	token, payload,  err := maker.CreateToken(util.RandomOwner(), time.Hour)
+	token, payload,  err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Hour, token.TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -320,7 +334,7 @@ func TestCreateTransfer_FromAccountNotFound(t *testing.T) {
 	
 	// ตั้งค่า gin router
 	router := gin.Default()
-	router.Use(authMiddleware(maker))
+	router.Use(authMiddleware(maker, newTestRevocationChecker(), newTestAPIKeyVerifier()))
 	router.POST("/transfers", server.createTransfer)
 	
 	// ทำการส่ง request
@@ -347,7 +361,7 @@ func TestCreateTransfer_ToAccountNotFound(t *testing.T) {
 	maker, err := token.NewPasetoMaker(util.RandomString(32))
 	require.NoError(t, err)
 	
-	token, payload, err := maker.CreateToken(fromAccount.Owner, time.Hour)
+	token, payload, err := maker.CreateToken(fromAccount.Owner, util.DepositorRole, uuid.New(), time.Hour, token.TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -375,7 +389,7 @@ func TestCreateTransfer_ToAccountNotFound(t *testing.T) {
 	
 	// ตั้งค่า gin router
 	router := gin.Default()
-	router.Use(authMiddleware(maker))
+	router.Use(authMiddleware(maker, newTestRevocationChecker(), newTestAPIKeyVerifier()))
 	router.POST("/transfers", server.createTransfer)
 	
 	// ทำการส่ง request
@@ -401,7 +415,7 @@ func TestCreateTransfer_FromAccountCurrencyMismatch(t *testing.T) {
 	maker, err := token.NewPasetoMaker(util.RandomString(32))
 	require.NoError(t, err)
 	
-	token, payload,err := maker.CreateToken(fromAccount.Owner, time.Hour)
+	token, payload,err := maker.CreateToken(fromAccount.Owner, util.DepositorRole, uuid.New(), time.Hour, token.TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -429,7 +443,7 @@ func TestCreateTransfer_FromAccountCurrencyMismatch(t *testing.T) {
 	
 	// ตั้งค่า gin router
 	router := gin.Default()
-	router.Use(authMiddleware(maker))
+	router.Use(authMiddleware(maker, newTestRevocationChecker(), newTestAPIKeyVerifier()))
 	router.POST("/transfers", server.createTransfer)
 	
 	// ทำการส่ง request
@@ -459,7 +473,7 @@ func TestCreateTransfer_ToAccountCurrencyMismatch(t *testing.T) {
 	maker, err := token.NewPasetoMaker(util.RandomString(32))
 	require.NoError(t, err)
 	
-	token, payload, err := maker.CreateToken(fromAccount.Owner, time.Hour)
+	token, payload, err := maker.CreateToken(fromAccount.Owner, util.DepositorRole, uuid.New(), time.Hour, token.TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -487,7 +501,7 @@ func TestCreateTransfer_ToAccountCurrencyMismatch(t *testing.T) {
 	
 	// ตั้งค่า gin router
 	router := gin.Default()
-	router.Use(authMiddleware(maker))
+	router.Use(authMiddleware(maker, newTestRevocationChecker(), newTestAPIKeyVerifier()))
 	router.POST("/transfers", server.createTransfer)
 	
 	// ทำการส่ง request
@@ -513,7 +527,7 @@ func TestCreateTransfer_UnauthorizedUser(t *testing.T) {
 	require.NoError(t, err)
 	
 	differentUser := util.RandomOwner() // สร้างชื่อผู้ใช้ที่ไม่ใช่เจ้าของบัญชี
-	token,  paylaod,err := maker.CreateToken(differentUser, time.Hour)
+	token,  paylaod,err := maker.CreateToken(differentUser, util.DepositorRole, uuid.New(), time.Hour, token.TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, paylaod)
 
@@ -541,7 +555,7 @@ func TestCreateTransfer_UnauthorizedUser(t *testing.T) {
 	
 	// ตั้งค่า gin router
 	router := gin.Default()
-	router.Use(authMiddleware(maker))
+	router.Use(authMiddleware(maker, newTestRevocationChecker(), newTestAPIKeyVerifier()))
 	router.POST("/transfers", server.createTransfer)
 	
 	// ทำการส่ง request
@@ -572,7 +586,7 @@ func TestCreateTransfer_DBError(t *testing.T) {
 	maker, err := token.NewPasetoMaker(util.RandomString(32))
 	require.NoError(t, err)
 	
-	token, payload,err := maker.CreateToken(fromAccount.Owner, time.Hour)
+	token, payload,err := maker.CreateToken(fromAccount.Owner, util.DepositorRole, uuid.New(), time.Hour, token.TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -600,7 +614,7 @@ func TestCreateTransfer_DBError(t *testing.T) {
 	
 	// ตั้งค่า gin router
 	router := gin.Default()
-	router.Use(authMiddleware(maker))
+	router.Use(authMiddleware(maker, newTestRevocationChecker(), newTestAPIKeyVerifier()))
 	router.POST("/transfers", server.createTransfer)
 	
 	// ทำการส่ง request
@@ -609,3 +623,53 @@ func TestCreateTransfer_DBError(t *testing.T) {
 	// ตรวจสอบ response
 	require.Equal(t, http.StatusInternalServerError, recorder.Code)
 }
+
+// TestCreateTransfer_InsufficientFunds ทดสอบกรณียอดเงินในบัญชีต้นทางไม่พอ
+func TestCreateTransfer_InsufficientFunds(t *testing.T) {
+	server, mockStore, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	user := util.RandomOwner()
+	fromAccount := createRandomAccount(user)
+	fromAccount.Currency = "USD"
+
+	toAccount := createRandomAccount(util.RandomOwner())
+	toAccount.Currency = "USD"
+
+	mockStore.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Return(fromAccount, nil)
+	mockStore.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Return(toAccount, nil)
+	mockStore.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Return(db.TransferTxResult{}, db.ErrInsufficientFunds)
+
+	maker, err := token.NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, payload, err := maker.CreateToken(fromAccount.Owner, util.DepositorRole, uuid.New(), time.Hour, token.TokenTypeAccessToken)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+
+	requestBody := transferRequest{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        fromAccount.Balance + 1,
+		Currency:      "USD",
+	}
+	bodyData, err := json.Marshal(requestBody)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(bodyData))
+	require.NoError(t, err)
+
+	authorizationHeader := fmt.Sprintf("Bearer %s", token)
+	req.Header.Set("Authorization", authorizationHeader)
+	req.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router := gin.Default()
+	router.Use(authMiddleware(maker, newTestRevocationChecker(), newTestAPIKeyVerifier()))
+	router.POST("/transfers", server.createTransfer)
+
+	router.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+}
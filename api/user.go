@@ -1,13 +1,16 @@
 package api
 
 import (
-	// "database/sql"
+	"database/sql"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/ratelimit"
+	"github.com/sangketkit01/simple-bank/token"
 	"github.com/sangketkit01/simple-bank/util"
 )
 
@@ -24,6 +27,7 @@ type userResponse struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	Role              string    `json:"role"`
 }
 
 func newUserResponse(user db.User) userResponse {
@@ -33,6 +37,7 @@ func newUserResponse(user db.User) userResponse {
 		Email:             user.Email,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt:         user.CreatedAt,
+		Role:              user.Role,
 	}
 }
 
@@ -92,10 +97,23 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
+	err := server.loginLimiter.Check(ctx, req.Username, ctx.ClientIP())
+	if err != nil {
+		if errors.Is(err, ratelimit.ErrTooManyAttempts) {
+			ctx.JSON(http.StatusTooManyRequests, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
 	user, err := server.store.GetUser(ctx, req.Username)
 	if err != nil {
-		if err == db.ErrRecordNotFound {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		if err == sql.ErrNoRows {
+			// spend the time of a password check, so the response time doesn't tell the username is unknown
+			util.CheckPassword(req.Password, util.DummyHashedPassword)
+			server.failLogin(ctx, req.Username, false)
 			return
 		}
 
@@ -103,13 +121,53 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
+	// a locked user is not told apart from a wrong password, and only the dummy hash is checked
+	if time.Now().Before(user.LockedUntil) {
+		util.CheckPassword(req.Password, util.DummyHashedPassword)
+		server.failLogin(ctx, user.Username, false)
+		return
+	}
+
 	err = util.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		server.failLogin(ctx, user.Username, true)
 		return
 	}
 
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	// this API has no second step, users with two-factor authentication sign in through the gRPC API
+	userTOTP, err := server.store.GetUserTOTP(ctx, user.Username)
+	if err != nil && err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if err == nil && userTOTP.IsConfirmed {
+		err := errors.New("two-factor authentication is enabled, use the VerifyMFA flow to sign in")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	// the failed logins are only forgiven once the sign in is complete
+	err = server.loginLimiter.Succeed(ctx, user.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.FailedLoginCount > 0 {
+		user, err = server.store.ResetFailedLogins(ctx, user.Username)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	sessionID, err := uuid.NewRandom()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, sessionID, server.config.AccessTokenDuration, token.TokenTypeAccessToken)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -117,7 +175,10 @@ func (server *Server) loginUser(ctx *gin.Context) {
 
 	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(
 		user.Username,
+		user.Role,
+		sessionID,
 		server.config.RefreshTokenDuration,
+		token.TokenTypeRefreshToken,
 	)
 
 	if err != nil {
@@ -126,13 +187,15 @@ func (server *Server) loginUser(ctx *gin.Context) {
 	}
 
 	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
-		ID:           refreshPayload.ID,
+		ID:           sessionID,
 		Username:     user.Username,
 		RefreshToken: refreshToken,
 		UserAgent:    ctx.Request.UserAgent(),
 		ClientIp:     ctx.ClientIP(),
 		IsBlock:      false,
 		ExpiredAt:    refreshPayload.ExpiredAt,
+		// a login starts a new family of rotated refresh tokens
+		FamilyID: sessionID,
 	})
 
 	if err != nil {
@@ -151,3 +214,32 @@ func (server *Server) loginUser(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, response)
 }
+
+// errInvalidCredentials is the answer to an unknown username, a wrong password and a locked user alike,
+// so a failed login does not tell which usernames exist
+var errInvalidCredentials = errors.New("incorrect username or password")
+
+// failLogin records a failed login and writes the response to it.
+// Only a wrong password of an existing user counts towards the lockout of that user.
+func (server *Server) failLogin(ctx *gin.Context, username string, wrongPassword bool) {
+	err := server.loginLimiter.Fail(ctx, username, ctx.ClientIP())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if wrongPassword {
+		_, err = server.store.RecordFailedLoginTx(ctx, db.RecordFailedLoginTxParams{
+			Username:           username,
+			LockoutThreshold:   server.config.LoginLockoutThreshold,
+			LockoutDuration:    server.config.LoginLockoutDuration,
+			MaxLockoutDuration: server.config.LoginMaxLockoutDuration,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidCredentials))
+}
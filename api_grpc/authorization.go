@@ -2,19 +2,40 @@ package apigrpc
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/sangketkit01/simple-bank/token"
+	"github.com/sangketkit01/simple-bank/util"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 const(
 	authorizationHeader = "authorization"
 	authorizationBearer = "bearer"
+	authorizationAPIKey = "apikey"
 )
 
-func (server *Server) authorizaUser(ctx context.Context) (*token.Payload, error){
+type authPayloadKey struct{}
+
+var(
+	// accountRoles may hold accounts and move money between them
+	accountRoles = []string{util.DepositorRole, util.BankerRole}
+	// allRoles may manage their own profile and sessions
+	allRoles = []string{util.DepositorRole, util.BankerRole, util.AdminRole}
+	// adminRoles may manage other users
+	adminRoles = []string{util.AdminRole}
+	// staffRoles may correct the ledger, e.g. reverse the transfers of other users
+	staffRoles = []string{util.BankerRole, util.AdminRole}
+)
+
+// authorizaUser verifies the access token or API key in the metadata and checks that its role is one of the accessible roles.
+// The scopes of an API key are checked by authorizeMethod.
+func (server *Server) authorizaUser(ctx context.Context, accessibleRoles []string) (*token.Payload, error){
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok{
 		return nil, fmt.Errorf("missing metadata")
@@ -33,15 +54,71 @@ func (server *Server) authorizaUser(ctx context.Context) (*token.Payload, error)
 	}
 
 	authType := strings.ToLower(fields[0])
+	if authType == authorizationAPIKey{
+		payload, err := server.apiKeyVerifier.VerifyAPIKey(ctx, fields[1])
+		if err != nil{
+			return nil, err
+		}
+
+		if !util.HasRole(payload.Role, accessibleRoles){
+			return nil, errPermissionDenied
+		}
+
+		return payload, nil
+	}
+
 	if authType != authorizationBearer{
 		return nil, fmt.Errorf("unsupported authorization type: %s", authType)
 	}
 	
 	accessToken := fields[1]
-	payload, err := server.tokenMaker.VerifyToken(accessToken)
+	payload, err := server.tokenMaker.VerifyToken(accessToken, token.TokenTypeAccessToken)
 	if err != nil{
 		return nil, fmt.Errorf("invalid access token")
 	}
 
+	revoked, err := server.revocationChecker.IsRevoked(ctx, payload)
+	if err != nil{
+		return nil, fmt.Errorf("cannot check access token: %w", err)
+	}
+	if revoked{
+		return nil, token.ErrRevokedToken
+	}
+
+	if !util.HasRole(payload.Role, accessibleRoles){
+		return nil, errPermissionDenied
+	}
+
+	return payload, nil
+}
+// requireVerifiedEmail checks that the user has verified the email address.
+// The user is looked up on every call, so a verification takes effect without renewing the access token.
+func (server *Server) requireVerifiedEmail(ctx context.Context, username string) error{
+	user, err := server.store.GetUser(ctx, username)
+	if err != nil{
+		if errors.Is(err, sql.ErrNoRows){
+			return unauthenticatedError(fmt.Errorf("user not found"))
+		}
+		return status.Errorf(codes.Internal, "failed to get user: %s", err)
+	}
+
+	if !user.IsEmailVerified{
+		return status.Errorf(codes.FailedPrecondition, "email address must be verified first, follow the link sent to %s or request a new one", user.Email)
+	}
+
+	return nil
+}
+
+func contextWithAuthPayload(ctx context.Context, payload *token.Payload) context.Context{
+	return context.WithValue(ctx, authPayloadKey{}, payload)
+}
+
+// authPayloadFromContext returns the payload stored by the auth interceptor
+func authPayloadFromContext(ctx context.Context) (*token.Payload, error){
+	payload, ok := ctx.Value(authPayloadKey{}).(*token.Payload)
+	if !ok || payload == nil{
+		return nil, fmt.Errorf("missing authorization payload")
+	}
+
 	return payload, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,314 @@
+package apigrpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestRolePermissionMatrix checks the role gate of every authenticated RPC.
+// Requests are left empty, so an allowed role stops at validation and a
+// denied role must be rejected before the store is touched.
+func TestRolePermissionMatrix(t *testing.T) {
+	username := util.RandomOwner()
+	roles := []string{util.DepositorRole, util.BankerRole, util.AdminRole}
+
+	testCases := []struct {
+		name         string
+		allowedRoles []string
+		buildStubs   func(store *mockdb.MockStore)
+		call         func(server *Server, ctx context.Context) error
+	}{
+		{
+			name:         "CreateAccount",
+			allowedRoles: []string{util.DepositorRole, util.BankerRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_CreateAccount_FullMethodName, &pb.CreateAccountRequest{}, server.CreateAccount)
+				return err
+			},
+		},
+		{
+			name:         "GetAccount",
+			allowedRoles: []string{util.DepositorRole, util.BankerRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_GetAccount_FullMethodName, &pb.GetAccountRequest{}, server.GetAccount)
+				return err
+			},
+		},
+		{
+			name:         "ListAccounts",
+			allowedRoles: []string{util.DepositorRole, util.BankerRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_ListAccounts_FullMethodName, &pb.ListAccountsRequest{}, server.ListAccounts)
+				return err
+			},
+		},
+		{
+			name:         "ListEntries",
+			allowedRoles: []string{util.DepositorRole, util.BankerRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_ListEntries_FullMethodName, &pb.ListEntriesRequest{}, server.ListEntries)
+				return err
+			},
+		},
+		{
+			name:         "ListTransfers",
+			allowedRoles: []string{util.DepositorRole, util.BankerRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_ListTransfers_FullMethodName, &pb.ListTransfersRequest{}, server.ListTransfers)
+				return err
+			},
+		},
+		{
+			name:         "CreateTransfer",
+			allowedRoles: []string{util.DepositorRole, util.BankerRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_CreateTransfer_FullMethodName, &pb.CreateTransferRequest{}, server.CreateTransfer)
+				return err
+			},
+		},
+		{
+			name:         "QuoteTransfer",
+			allowedRoles: []string{util.DepositorRole, util.BankerRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_QuoteTransfer_FullMethodName, &pb.QuoteTransferRequest{}, server.QuoteTransfer)
+				return err
+			},
+		},
+		{
+			name:         "CreateScheduledTransfer",
+			allowedRoles: []string{util.DepositorRole, util.BankerRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_CreateScheduledTransfer_FullMethodName, &pb.CreateScheduledTransferRequest{}, server.CreateScheduledTransfer)
+				return err
+			},
+		},
+		{
+			name:         "ListScheduledTransfers",
+			allowedRoles: []string{util.DepositorRole, util.BankerRole},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListScheduledTransfers(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return([]db.ScheduledTransfer{}, nil)
+			},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_ListScheduledTransfers_FullMethodName, &pb.ListScheduledTransfersRequest{}, server.ListScheduledTransfers)
+				return err
+			},
+		},
+		{
+			name:         "GetScheduledTransfer",
+			allowedRoles: []string{util.DepositorRole, util.BankerRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_GetScheduledTransfer_FullMethodName, &pb.GetScheduledTransferRequest{}, server.GetScheduledTransfer)
+				return err
+			},
+		},
+		{
+			name:         "UpdateScheduledTransfer",
+			allowedRoles: []string{util.DepositorRole, util.BankerRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_UpdateScheduledTransfer_FullMethodName, &pb.UpdateScheduledTransferRequest{}, server.UpdateScheduledTransfer)
+				return err
+			},
+		},
+		{
+			name:         "DeleteScheduledTransfer",
+			allowedRoles: []string{util.DepositorRole, util.BankerRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_DeleteScheduledTransfer_FullMethodName, &pb.DeleteScheduledTransferRequest{}, server.DeleteScheduledTransfer)
+				return err
+			},
+		},
+		{
+			name:         "CreateHold",
+			allowedRoles: []string{util.DepositorRole, util.BankerRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_CreateHold_FullMethodName, &pb.CreateHoldRequest{}, server.CreateHold)
+				return err
+			},
+		},
+		{
+			name:         "CaptureHold",
+			allowedRoles: []string{util.DepositorRole, util.BankerRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_CaptureHold_FullMethodName, &pb.CaptureHoldRequest{}, server.CaptureHold)
+				return err
+			},
+		},
+		{
+			name:         "ReleaseHold",
+			allowedRoles: []string{util.DepositorRole, util.BankerRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_ReleaseHold_FullMethodName, &pb.ReleaseHoldRequest{}, server.ReleaseHold)
+				return err
+			},
+		},
+		{
+			name:         "UpdateUser",
+			allowedRoles: roles,
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_UpdateUser_FullMethodName, &pb.UpdateUserRequest{}, server.UpdateUser)
+				return err
+			},
+		},
+		{
+			name:         "ListSessions",
+			allowedRoles: roles,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListActiveSessions(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return([]db.Session{}, nil)
+			},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_ListSessions_FullMethodName, &pb.ListSessionsRequest{}, server.ListSessions)
+				return err
+			},
+		},
+		{
+			name:         "RevokeSession",
+			allowedRoles: roles,
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_RevokeSession_FullMethodName, &pb.RevokeSessionRequest{}, server.RevokeSession)
+				return err
+			},
+		},
+		{
+			name:         "LogoutAllSessions",
+			allowedRoles: roles,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return([]uuid.UUID{}, nil)
+			},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_LogoutAllSessions_FullMethodName, &pb.LogoutAllSessionsRequest{}, server.LogoutAllSessions)
+				return err
+			},
+		},
+		{
+			name:         "CreateAPIKey",
+			allowedRoles: roles,
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_CreateAPIKey_FullMethodName, &pb.CreateAPIKeyRequest{}, server.CreateAPIKey)
+				return err
+			},
+		},
+		{
+			name:         "ListAPIKeys",
+			allowedRoles: roles,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAPIKeys(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return([]db.ApiKey{}, nil)
+			},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_ListAPIKeys_FullMethodName, &pb.ListAPIKeysRequest{}, server.ListAPIKeys)
+				return err
+			},
+		},
+		{
+			name:         "RevokeAPIKey",
+			allowedRoles: roles,
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_RevokeAPIKey_FullMethodName, &pb.RevokeAPIKeyRequest{}, server.RevokeAPIKey)
+				return err
+			},
+		},
+		{
+			name:         "ResendVerifyEmail",
+			allowedRoles: roles,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(db.User{Username: username, IsEmailVerified: true}, nil)
+			},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_ResendVerifyEmail_FullMethodName, &pb.ResendVerifyEmailRequest{}, server.ResendVerifyEmail)
+				return err
+			},
+		},
+		{
+			name:         "UnlockUser",
+			allowedRoles: []string{util.AdminRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_UnlockUser_FullMethodName, &pb.UnlockUserRequest{}, server.UnlockUser)
+				return err
+			},
+		},
+		{
+			name:         "ReverseTransfer",
+			allowedRoles: []string{util.BankerRole, util.AdminRole},
+			buildStubs:   func(store *mockdb.MockStore) {},
+			call: func(server *Server, ctx context.Context) error {
+				_, err := callUnary(ctx, server, pb.SimpleBank_ReverseTransfer_FullMethodName, &pb.ReverseTransferRequest{}, server.ReverseTransfer)
+				return err
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, role := range roles {
+			allowed := util.HasRole(role, tc.allowedRoles)
+
+			t.Run(fmt.Sprintf("%s/%s", tc.name, role), func(t *testing.T) {
+				storeCtrl := gomock.NewController(t)
+				defer storeCtrl.Finish()
+				store := mockdb.NewMockStore(storeCtrl)
+
+				// a denied role has no expectations, so any store call fails the test
+				if allowed {
+					tc.buildStubs(store)
+					expectVerifiedEmail(store)
+				}
+
+				server := newTestServer(t, store, nil)
+				ctx := newContextWithBearerToken(t, server.tokenMaker, username, role, time.Minute)
+
+				err := tc.call(server, ctx)
+				st, _ := status.FromError(err)
+				if allowed {
+					require.NotEqual(t, codes.PermissionDenied, st.Code())
+					require.NotEqual(t, codes.Unauthenticated, st.Code())
+				} else {
+					require.Equal(t, codes.PermissionDenied, st.Code())
+				}
+			})
+		}
+	}
+}
@@ -1,8 +1,13 @@
 package apigrpc
 
 import (
+	"database/sql"
+	"encoding/base64"
+
+	"github.com/google/uuid"
 	db "github.com/sangketkit01/simple-bank/db/sqlc"
 	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/token"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -13,5 +18,161 @@ func convertUser(user db.User) *pb.User{
 		Email: user.Email,
 		PasswordChangedAt: timestamppb.New(user.PasswordChangedAt),
 		CreatedAt: timestamppb.New(user.CreatedAt),
+		Role: user.Role,
+	}
+}
+
+func convertAccount(account db.Account) *pb.Account {
+	return &pb.Account{
+		Id:        account.ID,
+		Owner:     account.Owner,
+		Balance:   account.Balance,
+		Currency:  account.Currency,
+		CreatedAt: timestamppb.New(account.CreatedAt),
+
+		AvailableBalance: account.AvailableBalance(),
+	}
+}
+
+func convertTransfer(transfer db.Transfer) *pb.Transfer {
+	pbTransfer := &pb.Transfer{
+		Id:            transfer.ID,
+		FromAccountId: transfer.FromAccountID.Int64,
+		ToAccountId:   transfer.ToAccountID.Int64,
+		Amount:        transfer.Amount,
+		CreatedAt:     timestamppb.New(transfer.CreatedAt),
+		CreditAmount:  transfer.CreditAmount,
+		ExchangeRate:  transfer.ExchangeRate,
+		ReversalOf:    transfer.ReversalOf.Int64,
+		Reason:        transfer.Reason,
+	}
+	if transfer.QuoteID.Valid {
+		pbTransfer.QuoteId = transfer.QuoteID.UUID.String()
+	}
+	return pbTransfer
+}
+
+func convertTransferQuote(quote db.TransferQuote, fromAccount db.Account, toAccount db.Account) *pb.TransferQuote {
+	return &pb.TransferQuote{
+		Id:            quote.ID.String(),
+		FromAccountId: quote.FromAccountID,
+		ToAccountId:   quote.ToAccountID,
+		Amount:        quote.Amount,
+		FromCurrency:  fromAccount.Currency,
+		CreditAmount:  quote.CreditAmount,
+		ToCurrency:    toAccount.Currency,
+		ExchangeRate:  quote.ExchangeRate,
+		ExpiredAt:     timestamppb.New(quote.ExpiredAt),
+	}
+}
+
+func convertEntry(entry db.Entry) *pb.Entry {
+	return &pb.Entry{
+		Id:        entry.ID,
+		AccountId: entry.AccountID.Int64,
+		Amount:    entry.Amount,
+		CreatedAt: timestamppb.New(entry.CreatedAt),
+	}
+}
+
+func convertSession(session db.Session, currentSessionID uuid.UUID) *pb.Session {
+	return &pb.Session{
+		SessionId: session.ID.String(),
+		UserAgent: session.UserAgent,
+		ClientIp:  session.ClientIp,
+		CreatedAt: timestamppb.New(session.CreatedAt),
+		ExpiredAt: timestamppb.New(session.ExpiredAt),
+		IsCurrent: session.ID == currentSessionID,
+	}
+}
+
+func convertDirection(direction pb.Direction) string {
+	switch direction {
+	case pb.Direction_DIRECTION_INCOMING:
+		return db.DirectionIncoming
+	case pb.Direction_DIRECTION_OUTGOING:
+		return db.DirectionOutgoing
+	}
+	return ""
+}
+
+func convertNullTime(t *timestamppb.Timestamp) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.AsTime(), Valid: true}
+}
+
+func convertPublicKey(publicKey token.PublicKey) *pb.PublicKey {
+	return &pb.PublicKey{
+		Kid: publicKey.KeyID,
+		Kty: "OKP",
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(publicKey.Key),
+		Use: "sig",
+		Alg: "EdDSA",
+	}
+}
+
+func convertAPIKey(apiKey db.ApiKey) *pb.APIKey {
+	response := &pb.APIKey{
+		Id:        apiKey.ID,
+		Name:      apiKey.Name,
+		Scopes:    apiKey.Scopes,
+		IsRevoked: apiKey.IsRevoked,
+		ExpiredAt: timestamppb.New(apiKey.ExpiredAt),
+		CreatedAt: timestamppb.New(apiKey.CreatedAt),
+	}
+	if apiKey.LastUsedAt.Valid {
+		response.LastUsedAt = timestamppb.New(apiKey.LastUsedAt.Time)
+	}
+	return response
+}
+
+func convertScheduledTransfer(scheduledTransfer db.ScheduledTransfer) *pb.ScheduledTransfer {
+	return &pb.ScheduledTransfer{
+		Id:            scheduledTransfer.ID,
+		Owner:         scheduledTransfer.Owner,
+		FromAccountId: scheduledTransfer.FromAccountID,
+		ToAccountId:   scheduledTransfer.ToAccountID,
+		Amount:        scheduledTransfer.Amount,
+		Currency:      scheduledTransfer.Currency,
+		Schedule:      scheduledTransfer.Schedule,
+		StartsAt:      timestamppb.New(scheduledTransfer.StartsAt),
+		NextRunAt:     timestamppb.New(scheduledTransfer.NextRunAt),
+		Status:        scheduledTransfer.Status,
+		FailureCount:  scheduledTransfer.FailureCount,
+		CreatedAt:     timestamppb.New(scheduledTransfer.CreatedAt),
+	}
+}
+
+func convertScheduledTransferRun(run db.ScheduledTransferRun) *pb.ScheduledTransferRun {
+	return &pb.ScheduledTransferRun{
+		Id:          run.ID,
+		ScheduledAt: timestamppb.New(run.ScheduledAt),
+		Status:      run.Status,
+		TransferId:  run.TransferID.Int64,
+		Error:       run.Error,
+		CreatedAt:   timestamppb.New(run.CreatedAt),
+	}
+}
+
+func convertHold(hold db.AccountHold) *pb.Hold {
+	pbHold := &pb.Hold{
+		Id:             hold.ID,
+		Owner:          hold.Owner,
+		AccountId:      hold.AccountID,
+		ToAccountId:    hold.ToAccountID,
+		Amount:         hold.Amount,
+		Currency:       hold.Currency,
+		Status:         hold.Status,
+		CapturedAmount: hold.CapturedAmount,
+		TransferId:     hold.TransferID.Int64,
+		ExpiresAt:      timestamppb.New(hold.ExpiresAt),
+		CreatedAt:      timestamppb.New(hold.CreatedAt),
+	}
+	if hold.SettledAt.Valid {
+		pbHold.SettledAt = timestamppb.New(hold.SettledAt.Time)
 	}
-}
\ No newline at end of file
+	return pbHold
+}
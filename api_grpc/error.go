@@ -1,6 +1,8 @@
 package apigrpc
 
 import (
+	"errors"
+
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -28,3 +30,15 @@ func invalidArguementError(violations []*errdetails.BadRequest_FieldViolation) e
 func unauthenticatedError(err error) error{
 	return status.Errorf(codes.Unauthenticated, "unauthorizaed: %s",err)
 }
+
+var errPermissionDenied = errors.New("permission denied")
+
+// authorizationError maps an authorizaUser error to PermissionDenied for a disallowed role
+// and to Unauthenticated otherwise
+func authorizationError(err error) error{
+	if errors.Is(err, errPermissionDenied){
+		return status.Errorf(codes.PermissionDenied, "%s", err)
+	}
+
+	return unauthenticatedError(err)
+}
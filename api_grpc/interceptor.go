@@ -0,0 +1,168 @@
+package apigrpc
+
+import (
+	"context"
+
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+// methodPolicy declares who may call an RPC
+type methodPolicy struct {
+	// public methods are served without an access token
+	public bool
+	// roles that may call a method which requires an access token
+	roles []string
+	// scope an API key needs to call the method, methods without a scope only accept access tokens
+	scope string
+	// verifiedEmail methods may only be called once the user has verified the email address
+	verifiedEmail bool
+}
+
+// withScope returns a copy of the policy that also accepts API keys granted the scope
+func (policy methodPolicy) withScope(scope string) methodPolicy {
+	policy.scope = scope
+	return policy
+}
+
+// withVerifiedEmail returns a copy of the policy that also requires a verified email address
+func (policy methodPolicy) withVerifiedEmail() methodPolicy {
+	policy.verifiedEmail = true
+	return policy
+}
+
+var (
+	publicMethod        = methodPolicy{public: true}
+	authenticatedMethod = methodPolicy{roles: allRoles}
+	accountMethod       = methodPolicy{roles: accountRoles}
+	adminMethod         = methodPolicy{roles: adminRoles}
+	staffMethod         = methodPolicy{roles: staffRoles}
+)
+
+// methodPolicies is the access policy of every RPC served by the gRPC server.
+// A method missing from the table is rejected, so a new RPC has to declare its policy here before it can be called.
+var methodPolicies = map[string]methodPolicy{
+	pb.SimpleBank_CreateUser_FullMethodName:           publicMethod,
+	pb.SimpleBank_LoginUser_FullMethodName:            publicMethod,
+	pb.SimpleBank_VerifyEmail_FullMethodName:          publicMethod,
+	pb.SimpleBank_RenewAccessToken_FullMethodName:     publicMethod,
+	pb.SimpleBank_Logout_FullMethodName:               publicMethod,
+	pb.SimpleBank_VerifyMFA_FullMethodName:            publicMethod,
+	pb.SimpleBank_RequestPasswordReset_FullMethodName: publicMethod,
+	pb.SimpleBank_ResetPassword_FullMethodName:        publicMethod,
+	pb.SimpleBank_GetPublicKeys_FullMethodName:        publicMethod,
+
+	pb.SimpleBank_UpdateUser_FullMethodName:        authenticatedMethod,
+	pb.SimpleBank_ListSessions_FullMethodName:      authenticatedMethod,
+	pb.SimpleBank_RevokeSession_FullMethodName:     authenticatedMethod,
+	pb.SimpleBank_LogoutAllSessions_FullMethodName: authenticatedMethod,
+	pb.SimpleBank_EnrollTOTP_FullMethodName:        authenticatedMethod,
+	pb.SimpleBank_ConfirmTOTP_FullMethodName:       authenticatedMethod,
+	pb.SimpleBank_CreateAPIKey_FullMethodName:      authenticatedMethod,
+	pb.SimpleBank_ListAPIKeys_FullMethodName:       authenticatedMethod,
+	pb.SimpleBank_RevokeAPIKey_FullMethodName:      authenticatedMethod,
+	pb.SimpleBank_ResendVerifyEmail_FullMethodName: authenticatedMethod,
+
+	pb.SimpleBank_CreateAccount_FullMethodName:  accountMethod.withScope(util.AccountsWriteScope).withVerifiedEmail(),
+	pb.SimpleBank_GetAccount_FullMethodName:     accountMethod.withScope(util.AccountsReadScope),
+	pb.SimpleBank_ListAccounts_FullMethodName:   accountMethod.withScope(util.AccountsReadScope),
+	pb.SimpleBank_CreateTransfer_FullMethodName: accountMethod.withScope(util.TransfersWriteScope).withVerifiedEmail(),
+	pb.SimpleBank_QuoteTransfer_FullMethodName:  accountMethod.withScope(util.TransfersWriteScope),
+	pb.SimpleBank_ListEntries_FullMethodName:    accountMethod.withScope(util.AccountsReadScope),
+	pb.SimpleBank_ListTransfers_FullMethodName:  accountMethod.withScope(util.TransfersReadScope),
+
+	pb.SimpleBank_CreateScheduledTransfer_FullMethodName: accountMethod.withScope(util.TransfersWriteScope).withVerifiedEmail(),
+	pb.SimpleBank_ListScheduledTransfers_FullMethodName:  accountMethod.withScope(util.TransfersReadScope),
+	pb.SimpleBank_GetScheduledTransfer_FullMethodName:    accountMethod.withScope(util.TransfersReadScope),
+	pb.SimpleBank_UpdateScheduledTransfer_FullMethodName: accountMethod.withScope(util.TransfersWriteScope),
+	pb.SimpleBank_DeleteScheduledTransfer_FullMethodName: accountMethod.withScope(util.TransfersWriteScope),
+
+	pb.SimpleBank_CreateHold_FullMethodName:  accountMethod.withScope(util.TransfersWriteScope).withVerifiedEmail(),
+	pb.SimpleBank_CaptureHold_FullMethodName: accountMethod.withScope(util.TransfersWriteScope).withVerifiedEmail(),
+	pb.SimpleBank_ReleaseHold_FullMethodName: accountMethod.withScope(util.TransfersWriteScope),
+
+	pb.SimpleBank_UnlockUser_FullMethodName: adminMethod,
+
+	pb.SimpleBank_ReverseTransfer_FullMethodName: staffMethod,
+
+	reflectionv1.ServerReflection_ServerReflectionInfo_FullMethodName:      publicMethod,
+	reflectionv1alpha.ServerReflection_ServerReflectionInfo_FullMethodName: publicMethod,
+}
+
+// authorizeMethod applies the access policy of method and returns the context to hand to the handler.
+func (server *Server) authorizeMethod(ctx context.Context, method string) (context.Context, error) {
+	policy, ok := methodPolicies[method]
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "no access policy for method %s", method)
+	}
+
+	if policy.public {
+		return ctx, nil
+	}
+
+	authPayload, err := server.authorizaUser(ctx, policy.roles)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	if !authPayload.AllowsScope(policy.scope) {
+		if policy.scope == "" {
+			return nil, status.Errorf(codes.PermissionDenied, "method %s cannot be called with an api key", method)
+		}
+		return nil, status.Errorf(codes.PermissionDenied, "api key is missing the %s scope", policy.scope)
+	}
+
+	if policy.verifiedEmail {
+		err = server.requireVerifiedEmail(ctx, authPayload.Username)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return contextWithAuthPayload(ctx, authPayload), nil
+}
+
+// UnaryAuthInterceptor authenticates unary RPCs according to methodPolicies
+func (server *Server) UnaryAuthInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp interface{}, err error) {
+	ctx, err = server.authorizeMethod(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// StreamAuthInterceptor authenticates streaming RPCs according to methodPolicies
+func (server *Server) StreamAuthInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, err := server.authorizeMethod(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &authorizedServerStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorizedServerStream overrides the stream context with one carrying the auth payload
+type authorizedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authorizedServerStream) Context() context.Context {
+	return stream.ctx
+}
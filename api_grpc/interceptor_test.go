@@ -0,0 +1,330 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMethodPoliciesCoverService(t *testing.T) {
+	serviceName := pb.SimpleBank_ServiceDesc.ServiceName
+
+	for _, method := range pb.SimpleBank_ServiceDesc.Methods {
+		fullMethod := fmt.Sprintf("/%s/%s", serviceName, method.MethodName)
+		_, ok := methodPolicies[fullMethod]
+		require.True(t, ok, "missing access policy for %s", fullMethod)
+	}
+
+	for _, stream := range pb.SimpleBank_ServiceDesc.Streams {
+		fullMethod := fmt.Sprintf("/%s/%s", serviceName, stream.StreamName)
+		_, ok := methodPolicies[fullMethod]
+		require.True(t, ok, "missing access policy for %s", fullMethod)
+	}
+}
+
+func TestUnaryAuthInterceptor(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		method        string
+		buildContext  func(t *testing.T, server *Server) context.Context
+		checkResponse func(t *testing.T, handlerCalled bool, err error)
+	}{
+		{
+			name:   "PublicMethod",
+			method: pb.SimpleBank_LoginUser_FullMethodName,
+			buildContext: func(t *testing.T, server *Server) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, handlerCalled bool, err error) {
+				require.NoError(t, err)
+				require.True(t, handlerCalled)
+			},
+		},
+		{
+			name:   "AuthenticatedMethod",
+			method: pb.SimpleBank_GetAccount_FullMethodName,
+			buildContext: func(t *testing.T, server *Server) context.Context {
+				return newContextWithBearerToken(t, server.tokenMaker, username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, handlerCalled bool, err error) {
+				require.NoError(t, err)
+				require.True(t, handlerCalled)
+			},
+		},
+		{
+			name:   "NoAuthorization",
+			method: pb.SimpleBank_GetAccount_FullMethodName,
+			buildContext: func(t *testing.T, server *Server) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, handlerCalled bool, err error) {
+				require.False(t, handlerCalled)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name:   "RoleNotAllowed",
+			method: pb.SimpleBank_GetAccount_FullMethodName,
+			buildContext: func(t *testing.T, server *Server) context.Context {
+				return newContextWithBearerToken(t, server.tokenMaker, username, util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, handlerCalled bool, err error) {
+				require.False(t, handlerCalled)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name:   "MethodWithoutPolicy",
+			method: "/pb.SimpleBank/DropAllAccounts",
+			buildContext: func(t *testing.T, server *Server) context.Context {
+				return newContextWithBearerToken(t, server.tokenMaker, username, util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, handlerCalled bool, err error) {
+				require.False(t, handlerCalled)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil, nil)
+			ctx := tc.buildContext(t, server)
+
+			handlerCalled := false
+			info := &grpc.UnaryServerInfo{Server: server, FullMethod: tc.method}
+			_, err := server.UnaryAuthInterceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				handlerCalled = true
+
+				policy := methodPolicies[tc.method]
+				if !policy.public {
+					authPayload, err := authPayloadFromContext(ctx)
+					require.NoError(t, err)
+					require.Equal(t, username, authPayload.Username)
+				}
+				return nil, nil
+			})
+			tc.checkResponse(t, handlerCalled, err)
+		})
+	}
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *testServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+func TestStreamAuthInterceptor(t *testing.T) {
+	server := newTestServer(t, nil, nil)
+	username := util.RandomOwner()
+	method := "/pb.SimpleBank/WatchAccount"
+
+	methodPolicies[method] = accountMethod
+	defer delete(methodPolicies, method)
+	info := &grpc.StreamServerInfo{FullMethod: method, IsServerStream: true}
+
+	ctx := newContextWithBearerToken(t, server.tokenMaker, username, util.BankerRole, time.Minute)
+	handlerCalled := false
+	err := server.StreamAuthInterceptor(nil, &testServerStream{ctx: ctx}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		handlerCalled = true
+
+		authPayload, err := authPayloadFromContext(stream.Context())
+		require.NoError(t, err)
+		require.Equal(t, username, authPayload.Username)
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, handlerCalled)
+
+	// the stream is rejected before the handler runs without an access token
+	err = server.StreamAuthInterceptor(nil, &testServerStream{ctx: context.Background()}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		t.Fatal("handler must not be called")
+		return nil
+	})
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.Unauthenticated, st.Code())
+}
+
+func TestUnaryAuthInterceptorAPIKey(t *testing.T) {
+	username := util.RandomOwner()
+	key, err := util.GenerateAPIKey()
+	require.NoError(t, err)
+
+	validState := db.GetAPIKeyAuthStateRow{
+		ID:        1,
+		Username:  username,
+		Scopes:    []string{util.AccountsReadScope},
+		ExpiredAt: time.Now().Add(time.Hour),
+		Role:      util.DepositorRole,
+	}
+
+	testCases := []struct {
+		name       string
+		method     string
+		buildStubs func(store *mockdb.MockStore)
+		expected   codes.Code
+	}{
+		{
+			name:   "OK",
+			method: pb.SimpleBank_GetAccount_FullMethodName,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAPIKeyAuthState(gomock.Any(), gomock.Eq(util.HashAPIKey(key))).
+					Times(1).
+					Return(validState, nil)
+				store.EXPECT().
+					TouchAPIKey(gomock.Any(), gomock.Eq(validState.ID)).
+					Times(1).
+					Return(nil)
+			},
+			expected: codes.OK,
+		},
+		{
+			name:   "MissingScope",
+			method: pb.SimpleBank_CreateTransfer_FullMethodName,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAPIKeyAuthState(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(validState, nil)
+				store.EXPECT().
+					TouchAPIKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:   "MethodWithoutScope",
+			method: pb.SimpleBank_CreateAPIKey_FullMethodName,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAPIKeyAuthState(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(validState, nil)
+				store.EXPECT().
+					TouchAPIKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:   "OwnerRoleNotAllowed",
+			method: pb.SimpleBank_GetAccount_FullMethodName,
+			buildStubs: func(store *mockdb.MockStore) {
+				state := validState
+				state.Role = util.AdminRole
+				store.EXPECT().
+					GetAPIKeyAuthState(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(state, nil)
+				store.EXPECT().
+					TouchAPIKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:   "UnknownKey",
+			method: pb.SimpleBank_GetAccount_FullMethodName,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAPIKeyAuthState(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetAPIKeyAuthStateRow{}, sql.ErrNoRows)
+				store.EXPECT().
+					TouchAPIKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expected: codes.Unauthenticated,
+		},
+		{
+			name:   "RevokedKey",
+			method: pb.SimpleBank_GetAccount_FullMethodName,
+			buildStubs: func(store *mockdb.MockStore) {
+				state := validState
+				state.IsRevoked = true
+				store.EXPECT().
+					GetAPIKeyAuthState(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(state, nil)
+				store.EXPECT().
+					TouchAPIKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expected: codes.Unauthenticated,
+		},
+		{
+			name:   "ExpiredKey",
+			method: pb.SimpleBank_GetAccount_FullMethodName,
+			buildStubs: func(store *mockdb.MockStore) {
+				state := validState
+				state.ExpiredAt = time.Now().Add(-time.Minute)
+				store.EXPECT().
+					GetAPIKeyAuthState(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(state, nil)
+				store.EXPECT().
+					TouchAPIKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			expected: codes.Unauthenticated,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := newContextWithAPIKey(key)
+
+			info := &grpc.UnaryServerInfo{Server: server, FullMethod: tc.method}
+			_, err := server.UnaryAuthInterceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				authPayload, err := authPayloadFromContext(ctx)
+				require.NoError(t, err)
+				require.Equal(t, username, authPayload.Username)
+				require.Equal(t, validState.ID, authPayload.APIKeyID)
+				return nil, nil
+			})
+
+			st, _ := status.FromError(err)
+			require.Equal(t, tc.expected, st.Code())
+		})
+	}
+}
@@ -1,24 +1,97 @@
 package apigrpc
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
 	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/token"
 	"github.com/sangketkit01/simple-bank/util"
 	"github.com/sangketkit01/simple-bank/worker"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 func newTestServer(t *testing.T, store db.Store, taskDistributor worker.TaskDistributor) *Server {
 	config := util.Config{
-		TokenSymmetricKey:   util.RandomString(32),
-		AccessTokenDuration: time.Minute,
+		TokenSymmetricKey:     util.RandomString(32),
+		AccessTokenDuration:   time.Minute,
+		MFAChallengeDuration:  time.Minute,
+		TransferQuoteDuration: time.Minute,
+		HoldDuration:          time.Hour,
 	}
 
 	server, err := NewServer(config, store, taskDistributor)
 	require.NoError(t, err)
 
+	// the sessions of tokens created by the tests are not in the mock store
+	server.revocationChecker = token.NewCachedRevocationChecker(func(ctx context.Context, sessionID uuid.UUID) (token.SessionState, error) {
+		return token.SessionState{}, nil
+	}, time.Minute, time.Minute)
+
 	return server
 
 }
+
+// expectVerifiedEmail lets every user pass the verified email check of the auth interceptor
+func expectVerifiedEmail(store *mockdb.MockStore) {
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, username string) (db.User, error) {
+			return db.User{Username: username, IsEmailVerified: true}, nil
+		})
+}
+
+func newContextWithBearerToken(t *testing.T, tokenMaker token.Maker, username string, role string, duration time.Duration) context.Context {
+	return newContextWithSessionToken(t, tokenMaker, username, role, uuid.New(), duration)
+}
+
+func newContextWithSessionToken(t *testing.T, tokenMaker token.Maker, username string, role string, sessionID uuid.UUID, duration time.Duration) context.Context {
+	accessToken, _, err := tokenMaker.CreateToken(username, role, sessionID, duration, token.TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	bearerToken := fmt.Sprintf("%s %s", authorizationBearer, accessToken)
+	md := metadata.MD{
+		authorizationHeader: []string{
+			bearerToken,
+		},
+	}
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func newContextWithAPIKey(key string) context.Context {
+	md := metadata.MD{
+		authorizationHeader: []string{
+			fmt.Sprintf("%s %s", authorizationAPIKey, key),
+		},
+	}
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+// callUnary invokes rpc through the auth interceptor as the gRPC server would for method
+func callUnary[Req any, Res any](
+	ctx context.Context,
+	server *Server,
+	method string,
+	req Req,
+	rpc func(context.Context, Req) (Res, error),
+) (Res, error) {
+	var zero Res
+
+	info := &grpc.UnaryServerInfo{Server: server, FullMethod: method}
+	res, err := server.UnaryAuthInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return rpc(ctx, req.(Req))
+	})
+	if err != nil {
+		return zero, err
+	}
+
+	return res.(Res), nil
+}
@@ -2,6 +2,10 @@ package apigrpc
 
 import (
 	"context"
+	"net"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
@@ -10,6 +14,7 @@ const(
 	grpcGatewayUserAgentHeader = "grpcgateway-user-agent"
 	userAgentHeader = "user-agent"
 	xForwardedForHeader = "x-forwarded-for"
+	idempotencyKeyHeader = "idempotency-key"
 )
 
 type Metadata struct{
@@ -17,25 +22,75 @@ type Metadata struct{
 	ClientIP string
 }
 
+// extractMetadata returns the user agent and IP of the client. Requests proxied by the
+// HTTP gateway come from the gateway itself, so the headers it forwards take precedence
+// over the gRPC user agent and the peer address.
+// The forwarded address is only trusted from the gateway, which dials in over loopback:
+// a direct client could otherwise claim a new address on every request.
 func (server *Server) extractMetadata(ctx context.Context) *Metadata{
 	mtdt := &Metadata{}
 
+	// without a peer the call was made in process
+	trustForwardedFor := true
+	if p, ok := peer.FromContext(ctx) ; ok{
+		mtdt.ClientIP = p.Addr.String()
+		trustForwardedFor = isLoopback(p.Addr)
+	}
+
 	if md, ok := metadata.FromIncomingContext(ctx); ok {
-		if userAgents := md.Get(grpcGatewayUserAgentHeader); len(userAgents) > 0{
+		if userAgents := md.Get(userAgentHeader); len(userAgents) > 0{
 			mtdt.UserAgent = userAgents[0]
 		}
 
-		if userAgents := md.Get(userAgentHeader); len(userAgents) > 0{
+		if userAgents := md.Get(grpcGatewayUserAgentHeader); len(userAgents) > 0{
 			mtdt.UserAgent = userAgents[0]
 		}
 
-		if clientIPs := md.Get(xForwardedForHeader); len(clientIPs) > 0{
-			mtdt.ClientIP = clientIPs[0]
+		// the gateway appends the address it received the request from,
+		// earlier entries are whatever the client chose to send
+		if clientIPs := md.Get(xForwardedForHeader); len(clientIPs) > 0 && trustForwardedFor{
+			forwarded := strings.Split(clientIPs[len(clientIPs)-1], ",")
+			mtdt.ClientIP = strings.TrimSpace(forwarded[len(forwarded)-1])
 		}
 	}
 
-	if p, ok := peer.FromContext(ctx) ; ok{
-		mtdt.ClientIP = p.Addr.String()
-	}
 	return mtdt
-}
\ No newline at end of file
+}
+
+// clientHost returns the IP of the client without the port a direct gRPC client is identified with
+func clientHost(clientIP string) string {
+	if host, _, err := net.SplitHostPort(clientIP); err == nil {
+		return host
+	}
+	return clientIP
+}
+
+// isLoopback reports whether the address is on the loopback interface
+func isLoopback(addr net.Addr) bool {
+	host := addr.String()
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// extractIdempotencyKey returns the idempotency key sent by the client, if any
+func extractIdempotencyKey(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get(idempotencyKeyHeader); len(keys) > 0 {
+			return keys[0]
+		}
+	}
+	return ""
+}
+
+// GatewayHeaderMatcher forwards the Idempotency-Key HTTP header to gRPC metadata
+// on top of the headers forwarded by the gateway by default
+func GatewayHeaderMatcher(key string) (string, bool) {
+	if strings.ToLower(key) == idempotencyKeyHeader {
+		return idempotencyKeyHeader, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
@@ -0,0 +1,82 @@
+package apigrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestExtractMetadata(t *testing.T) {
+	gatewayAddr := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 50000}
+	clientAddr := &net.TCPAddr{IP: net.ParseIP("198.51.100.20"), Port: 50000}
+
+	testCases := []struct {
+		name              string
+		peerAddr          net.Addr
+		md                metadata.MD
+		expectedUserAgent string
+		expectedClientIP  string
+	}{
+		{
+			name: "DirectGrpcClient",
+			md: metadata.Pairs(
+				userAgentHeader, "grpc-go/1.70.0",
+			),
+			expectedUserAgent: "grpc-go/1.70.0",
+			expectedClientIP:  gatewayAddr.String(),
+		},
+		{
+			name: "ThroughGateway",
+			md: metadata.Pairs(
+				userAgentHeader, "grpc-go/1.70.0",
+				grpcGatewayUserAgentHeader, "curl/8.5.0",
+				xForwardedForHeader, "203.0.113.7",
+			),
+			expectedUserAgent: "curl/8.5.0",
+			expectedClientIP:  "203.0.113.7",
+		},
+		{
+			name: "SpoofedForwardedFor",
+			md: metadata.Pairs(
+				grpcGatewayUserAgentHeader, "curl/8.5.0",
+				xForwardedForHeader, "10.0.0.1, 203.0.113.7",
+			),
+			expectedUserAgent: "curl/8.5.0",
+			expectedClientIP:  "203.0.113.7",
+		},
+		{
+			name:     "DirectClientForwardedFor",
+			peerAddr: clientAddr,
+			md: metadata.Pairs(
+				userAgentHeader, "grpc-go/1.70.0",
+				xForwardedForHeader, "203.0.113.7",
+			),
+			expectedUserAgent: "grpc-go/1.70.0",
+			expectedClientIP:  clientAddr.String(),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil, nil)
+
+			peerAddr := tc.peerAddr
+			if peerAddr == nil {
+				peerAddr = gatewayAddr
+			}
+
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: peerAddr})
+			ctx = metadata.NewIncomingContext(ctx, tc.md)
+
+			mtdt := server.extractMetadata(ctx)
+			require.Equal(t, tc.expectedUserAgent, mtdt.UserAgent)
+			require.Equal(t, tc.expectedClientIP, mtdt.ClientIP)
+		})
+	}
+}
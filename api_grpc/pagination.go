@@ -0,0 +1,40 @@
+package apigrpc
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sangketkit01/simple-bank/util"
+)
+
+// decodePageToken returns the keyset position encoded in the page token.
+// An empty token means the first page and returns null values.
+func (server *Server) decodePageToken(scope string, pageToken string) (sql.NullInt64, sql.NullTime, error) {
+	if pageToken == "" {
+		return sql.NullInt64{}, sql.NullTime{}, nil
+	}
+
+	cursor, err := server.cursorSigner.Decode(scope, pageToken)
+	if err != nil {
+		return sql.NullInt64{}, sql.NullTime{}, err
+	}
+
+	return sql.NullInt64{Int64: cursor.ID, Valid: true}, sql.NullTime{Time: cursor.CreatedAt, Valid: true}, nil
+}
+
+func (server *Server) encodePageToken(scope string, id int64, createdAt time.Time) string {
+	return server.cursorSigner.Encode(scope, util.Cursor{ID: id, CreatedAt: createdAt})
+}
+
+func accountsPageScope(owner string) string {
+	return fmt.Sprintf("accounts:%s", owner)
+}
+
+func entriesPageScope(accountID int64) string {
+	return fmt.Sprintf("entries:%d", accountID)
+}
+
+func transfersPageScope(accountID int64) string {
+	return fmt.Sprintf("transfers:%d", accountID)
+}
@@ -5,17 +5,20 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
 	db "github.com/sangketkit01/simple-bank/db/sqlc"
 	"github.com/sangketkit01/simple-bank/pb"
 	"github.com/sangketkit01/simple-bank/util"
 	"github.com/sangketkit01/simple-bank/val"
+	"github.com/sangketkit01/simple-bank/worker"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 func (server *Server) UpdateUser(ctx context.Context, in *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error){
-	authPayload, err := server.authorizaUser(ctx)
+	authPayload, err := authPayloadFromContext(ctx)
 	if err != nil{
 		return nil, unauthenticatedError(err)
 	}
@@ -25,9 +28,13 @@ func (server *Server) UpdateUser(ctx context.Context, in *pb.UpdateUserRequest)
 		return nil, invalidArguementError(violations)
 	}
 
-	if authPayload.Username != in.Username{
+	if authPayload.Username != in.Username && !util.CanManageUsers(authPayload.Role){
 		return nil, status.Errorf(codes.PermissionDenied, "cannot update other user info")
 	}
+
+	if in.Role != nil && !util.CanManageUsers(authPayload.Role){
+		return nil, status.Errorf(codes.PermissionDenied, "cannot change user role")
+	}
 	
 	arg := db.UpdateUserParams{
 		Username: in.Username,
@@ -39,6 +46,10 @@ func (server *Server) UpdateUser(ctx context.Context, in *pb.UpdateUserRequest)
 			String: in.GetEmail(),
 			Valid: in.Email != nil,
 		},
+		Role: sql.NullString{
+			String: in.GetRole(),
+			Valid: in.Role != nil,
+		},
 	}
 
 	if in.Password != nil{
@@ -57,7 +68,12 @@ func (server *Server) UpdateUser(ctx context.Context, in *pb.UpdateUserRequest)
 		}
 	}
 
-	user, err := server.store.UpdateUser(ctx, arg)
+	// tokens carry the role they were issued with, and a changed password must
+	// kick out whoever knew the old one, so either change signs the user out everywhere
+	txResult, err := server.store.UpdateUserTx(ctx, db.UpdateUserTxParams{
+		UpdateUserParams: arg,
+		BlockSessions: in.Role != nil || in.Password != nil,
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, status.Errorf(codes.NotFound, "user not found")
@@ -65,8 +81,31 @@ func (server *Server) UpdateUser(ctx context.Context, in *pb.UpdateUserRequest)
 		return nil, status.Errorf(codes.Internal, "failed to update user: %s",err)
 	}
 
+	err = server.revocationChecker.Revoke(ctx, txResult.BlockedSessionIDs...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke sessions: %s", err)
+	}
+
+	// a new email has to be verified again before it can be used,
+	// the email is only queued once the update is committed so it never points at a rolled back address
+	if txResult.EmailChanged {
+		taskPayload := &worker.PayloadSendVerifyEmail{
+			Username: txResult.User.Username,
+		}
+
+		opts := []asynq.Option{
+			asynq.MaxRetry(10),
+			asynq.Queue(worker.QueueCtitical),
+		}
+		err = server.taskDistributor.DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...)
+		if err != nil {
+			// the update is already committed, the user can still ask for the email with ResendVerifyEmail
+			log.Error().Err(err).Str("username", txResult.User.Username).Msg("failed to send verify email for the new email address")
+		}
+	}
+
 	response := &pb.UpdateUserResponse{
-		User: convertUser(user),
+		User: convertUser(txResult.User),
 	}
 	return response, nil
 }
@@ -94,5 +133,11 @@ func validUpdateUserRequest(req *pb.UpdateUserRequest) (violation []*errdetails.
 		}
 	}
 
+	if req.Role != nil{
+		if err := val.ValidateRole(req.GetRole()) ; err != nil{
+			violation = append(violation, fieldViolation("role",err))
+		}
+	}
+
 	return
 }
\ No newline at end of file
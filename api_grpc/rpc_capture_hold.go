@@ -0,0 +1,101 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CaptureHold transfers the whole hold, or part of it, to its destination account and releases the rest.
+// Either side of the hold may capture it.
+func (server *Server) CaptureHold(ctx context.Context, req *pb.CaptureHoldRequest) (*pb.CaptureHoldResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validCaptureHoldRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	hold, _, err := server.getHold(ctx, authPayload.Username, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Amount != nil {
+		if err := val.ValidateCurrencyAmount(req.GetAmount(), hold.Currency); err != nil {
+			return nil, invalidArguementError([]*errdetails.BadRequest_FieldViolation{fieldViolation("amount", err)})
+		}
+	}
+
+	result, err := server.store.CaptureHoldTx(ctx, db.CaptureHoldTxParams{
+		HoldID: hold.ID,
+		Amount: req.GetAmount(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrHoldNotActive) || errors.Is(err, db.ErrHoldExpired) || errors.Is(err, db.ErrInsufficientFunds) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		if errors.Is(err, db.ErrCaptureExceedsHold) {
+			return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to capture hold: %s", err)
+	}
+
+	response := &pb.CaptureHoldResponse{
+		Hold:        convertHold(result.Hold),
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry),
+		ToEntry:     convertEntry(result.ToEntry),
+	}
+	return response, nil
+}
+
+// getHold loads a hold username is a party to, either as the owner of the held account or as the owner
+// of the account it is held for, and reports whether username is the latter.
+// Holds of other users are reported as not found, so their ids can't be probed.
+func (server *Server) getHold(ctx context.Context, username string, id int64) (db.AccountHold, bool, error) {
+	hold, err := server.store.GetAccountHold(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return hold, false, status.Errorf(codes.NotFound, "hold not found")
+		}
+		return hold, false, status.Errorf(codes.Internal, "failed to get hold: %s", err)
+	}
+
+	toAccount, err := server.store.GetAccount(ctx, hold.ToAccountID)
+	if err != nil {
+		return db.AccountHold{}, false, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	isPayee := toAccount.Owner == username
+	if hold.Owner != username && !isPayee {
+		return db.AccountHold{}, false, status.Errorf(codes.NotFound, "hold not found")
+	}
+
+	return hold, isPayee, nil
+}
+
+func validCaptureHoldRequest(req *pb.CaptureHoldRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violation = append(violation, fieldViolation("id", err))
+	}
+	if req.Amount != nil {
+		if err := val.ValidateAmount(req.GetAmount()); err != nil {
+			violation = append(violation, fieldViolation("amount", err))
+		}
+	}
+
+	return
+}
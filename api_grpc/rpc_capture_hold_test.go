@@ -0,0 +1,207 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/token"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func randomHold(owner string) db.AccountHold {
+	return db.AccountHold{
+		ID:          util.RandomInt(1, 1000),
+		Owner:       owner,
+		AccountID:   1,
+		ToAccountID: 2,
+		Amount:      5000,
+		Currency:    util.USD,
+		Status:      util.HoldActive,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+}
+
+func TestCaptureHoldAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	payee, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	amount := func(amount int64) *int64 {
+		return &amount
+	}
+
+	testCases := []struct {
+		name          string
+		amount        *int64
+		buildStubs    func(store *mockdb.MockStore, hold db.AccountHold)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.CaptureHoldResponse, err error)
+	}{
+		{
+			name: "Full",
+			buildStubs: func(store *mockdb.MockStore, hold db.AccountHold) {
+				arg := db.CaptureHoldTxParams{HoldID: hold.ID}
+				hold.Status = util.HoldCaptured
+				hold.CapturedAmount = hold.Amount
+				hold.TransferID = sql.NullInt64{Int64: 1, Valid: true}
+				hold.SettledAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+				store.EXPECT().
+					CaptureHoldTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.CaptureHoldTxResult{
+						Hold: hold,
+						TransferTxResult: db.TransferTxResult{
+							Transfer: db.Transfer{ID: 1, Amount: hold.Amount, CreditAmount: hold.Amount, ExchangeRate: "1"},
+						},
+					}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, util.HoldCaptured, res.GetHold().GetStatus())
+				require.Equal(t, int64(5000), res.GetHold().GetCapturedAmount())
+				require.Equal(t, int64(1), res.GetHold().GetTransferId())
+				require.NotNil(t, res.GetHold().GetSettledAt())
+				require.Equal(t, int64(5000), res.GetTransfer().GetAmount())
+			},
+		},
+		{
+			name:   "Partial",
+			amount: amount(2000),
+			buildStubs: func(store *mockdb.MockStore, hold db.AccountHold) {
+				arg := db.CaptureHoldTxParams{HoldID: hold.ID, Amount: 2000}
+				hold.Status = util.HoldCaptured
+				hold.CapturedAmount = 2000
+
+				store.EXPECT().
+					CaptureHoldTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.CaptureHoldTxResult{Hold: hold}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(2000), res.GetHold().GetCapturedAmount())
+			},
+		},
+		{
+			name: "Payee",
+			buildStubs: func(store *mockdb.MockStore, hold db.AccountHold) {
+				store.EXPECT().
+					CaptureHoldTx(gomock.Any(), gomock.Eq(db.CaptureHoldTxParams{HoldID: hold.ID})).
+					Times(1).
+					Return(db.CaptureHoldTxResult{Hold: hold}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, payee.Username, payee.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "ExceedsHold",
+			amount: amount(6000),
+			buildStubs: func(store *mockdb.MockStore, hold db.AccountHold) {
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CaptureHoldTxResult{}, db.ErrCaptureExceedsHold)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name:   "InvalidAmount",
+			amount: amount(0),
+			buildStubs: func(store *mockdb.MockStore, hold db.AccountHold) {
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "NotActive",
+			buildStubs: func(store *mockdb.MockStore, hold db.AccountHold) {
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CaptureHoldTxResult{}, db.ErrHoldNotActive)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "OtherOwner",
+			buildStubs: func(store *mockdb.MockStore, hold db.AccountHold) {
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, other.Username, other.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			hold := randomHold(user.Username)
+			store.EXPECT().
+				GetAccountHold(gomock.Any(), gomock.Eq(hold.ID)).
+				AnyTimes().
+				Return(hold, nil)
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(hold.ToAccountID)).
+				AnyTimes().
+				Return(db.Account{ID: hold.ToAccountID, Owner: payee.Username, Currency: hold.Currency}, nil)
+			tc.buildStubs(store, hold)
+			expectVerifiedEmail(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			req := &pb.CaptureHoldRequest{Id: hold.ID, Amount: tc.amount}
+			res, err := callUnary(ctx, server, pb.SimpleBank_CaptureHold_FullMethodName, req, server.CaptureHold)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
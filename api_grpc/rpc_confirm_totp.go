@@ -0,0 +1,79 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ConfirmTOTP(ctx context.Context, req *pb.ConfirmTOTPRequest) (*pb.ConfirmTOTPResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validConfirmTOTPRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	userTOTP, err := server.store.GetUserTOTP(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is not enrolled")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get totp: %s", err)
+	}
+
+	if userTOTP.IsConfirmed {
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	}
+
+	step, ok := util.ValidateTOTP(userTOTP.Secret, req.GetCode(), time.Now())
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect code")
+	}
+
+	recoveryCodes, err := util.GenerateRecoveryCodes(util.RecoveryCodeCount)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s", err)
+	}
+
+	hashedRecoveryCodes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		hashedRecoveryCodes = append(hashedRecoveryCodes, util.HashRecoveryCode(code))
+	}
+
+	_, err = server.store.ConfirmTOTPTx(ctx, db.ConfirmTOTPTxParams{
+		Username:            authPayload.Username,
+		Step:                step,
+		HashedRecoveryCodes: hashedRecoveryCodes,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is already enabled")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to confirm totp: %s", err)
+	}
+
+	response := &pb.ConfirmTOTPResponse{
+		RecoveryCodes: recoveryCodes,
+	}
+	return response, nil
+}
+
+func validConfirmTOTPRequest(req *pb.ConfirmTOTPRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateTOTPCode(req.GetCode()); err != nil {
+		violation = append(violation, fieldViolation("code", err))
+	}
+
+	return
+}
@@ -0,0 +1,128 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConfirmTOTPAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	pending := randomUserTOTP(t, user.Username)
+	pending.IsConfirmed = false
+
+	now := time.Now()
+	code, err := util.TOTPCode(pending.Secret, now)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		req           *pb.ConfirmTOTPRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.ConfirmTOTPResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.ConfirmTOTPRequest{Code: code},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(pending, nil)
+				store.EXPECT().
+					ConfirmTOTPTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.ConfirmTOTPTxParams) (db.ConfirmTOTPTxResult, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, util.TOTPStep(now), arg.Step)
+						require.Len(t, arg.HashedRecoveryCodes, util.RecoveryCodeCount)
+						return db.ConfirmTOTPTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.ConfirmTOTPResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetRecoveryCodes(), util.RecoveryCodeCount)
+			},
+		},
+		{
+			name: "InvalidCode",
+			req:  &pb.ConfirmTOTPRequest{Code: "abcdef"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ConfirmTOTPResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "NotEnrolled",
+			req:  &pb.ConfirmTOTPRequest{Code: code},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.UserTotp{}, sql.ErrNoRows)
+				store.EXPECT().
+					ConfirmTOTPTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ConfirmTOTPResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "AlreadyEnabled",
+			req:  &pb.ConfirmTOTPRequest{Code: code},
+			buildStubs: func(store *mockdb.MockStore) {
+				confirmed := pending
+				confirmed.IsConfirmed = true
+
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(confirmed, nil)
+				store.EXPECT().
+					ConfirmTOTPTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ConfirmTOTPResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := newContextWithBearerToken(t, server.tokenMaker, user.Username, user.Role, time.Minute)
+			res, err := callUnary(ctx, server, pb.SimpleBank_ConfirmTOTP_FullMethodName, tc.req, server.ConfirmTOTP)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package apigrpc
+
+import (
+	"context"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validCreateAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	account, err := server.store.CreateAccount(ctx, db.CreateAccountParams{
+		Owner:    authPayload.Username,
+		Balance:  0,
+		Currency: req.GetCurrency(),
+	})
+	if err != nil {
+		switch db.ErrorCode(err) {
+		case db.UniqueViolation:
+			return nil, status.Errorf(codes.AlreadyExists, "account with currency %s already exists", req.GetCurrency())
+		case db.ForeignKeyViolation:
+			return nil, status.Errorf(codes.PermissionDenied, "account owner does not exist")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create account: %s", err)
+	}
+
+	response := &pb.CreateAccountResponse{
+		Account: convertAccount(account),
+	}
+	return response, nil
+}
+
+func validCreateAccountRequest(req *pb.CreateAccountRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violation = append(violation, fieldViolation("currency", err))
+	}
+
+	return
+}
@@ -0,0 +1,68 @@
+package apigrpc
+
+import (
+	"context"
+	"time"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateAPIKey creates an API key acting as the authenticated user, limited to the requested scopes.
+// The key is only returned in this response, the store keeps its hash.
+func (server *Server) CreateAPIKey(ctx context.Context, req *pb.CreateAPIKeyRequest) (*pb.CreateAPIKeyResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validCreateAPIKeyRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	key, err := util.GenerateAPIKey()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate api key: %s", err)
+	}
+
+	arg := db.CreateAPIKeyParams{
+		Username:  authPayload.Username,
+		Name:      req.GetName(),
+		HashedKey: util.HashAPIKey(key),
+		Scopes:    req.GetScopes(),
+		ExpiredAt: time.Now().Add(time.Duration(req.GetValidDays()) * 24 * time.Hour),
+	}
+
+	apiKey, err := server.store.CreateAPIKey(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create api key: %s", err)
+	}
+
+	response := &pb.CreateAPIKeyResponse{
+		ApiKey: convertAPIKey(apiKey),
+		Key:    key,
+	}
+	return response, nil
+}
+
+func validCreateAPIKeyRequest(req *pb.CreateAPIKeyRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateAPIKeyName(req.GetName()); err != nil {
+		violation = append(violation, fieldViolation("name", err))
+	}
+
+	if err := val.ValidateScopes(req.GetScopes()); err != nil {
+		violation = append(violation, fieldViolation("scopes", err))
+	}
+
+	if err := val.ValidateAPIKeyValidDays(req.GetValidDays()); err != nil {
+		violation = append(violation, fieldViolation("valid_days", err))
+	}
+
+	return
+}
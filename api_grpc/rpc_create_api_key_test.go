@@ -0,0 +1,155 @@
+package apigrpc
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateAPIKeyAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	scopes := []string{util.AccountsReadScope, util.TransfersReadScope}
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateAPIKeyRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.CreateAPIKeyResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.CreateAPIKeyRequest{
+				Name:      "reconciliation",
+				Scopes:    scopes,
+				ValidDays: 30,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateAPIKeyParams) (db.ApiKey, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, "reconciliation", arg.Name)
+						require.Equal(t, scopes, arg.Scopes)
+						require.Len(t, arg.HashedKey, 64)
+						require.WithinDuration(t, time.Now().Add(30*24*time.Hour), arg.ExpiredAt, time.Second)
+
+						return db.ApiKey{
+							ID:        1,
+							Username:  arg.Username,
+							Name:      arg.Name,
+							HashedKey: arg.HashedKey,
+							Scopes:    arg.Scopes,
+							ExpiredAt: arg.ExpiredAt,
+							CreatedAt: time.Now(),
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAPIKeyResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, util.IsAPIKey(res.GetKey()))
+				require.Equal(t, int64(1), res.GetApiKey().GetId())
+				require.Equal(t, scopes, res.GetApiKey().GetScopes())
+				require.Nil(t, res.GetApiKey().GetLastUsedAt())
+			},
+		},
+		{
+			name: "UnsupportedScope",
+			req: &pb.CreateAPIKeyRequest{
+				Name:      "reconciliation",
+				Scopes:    []string{"accounts:*"},
+				ValidDays: 30,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAPIKeyResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "NoScopes",
+			req: &pb.CreateAPIKeyRequest{
+				Name:      "reconciliation",
+				ValidDays: 30,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAPIKeyResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "InvalidValidDays",
+			req: &pb.CreateAPIKeyRequest{
+				Name:      "reconciliation",
+				Scopes:    scopes,
+				ValidDays: 366,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAPIKeyResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "InternalError",
+			req: &pb.CreateAPIKeyRequest{
+				Name:      "reconciliation",
+				Scopes:    scopes,
+				ValidDays: 30,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApiKey{}, fmt.Errorf("connection refused"))
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAPIKeyResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := newContextWithBearerToken(t, server.tokenMaker, user.Username, user.Role, time.Minute)
+			res, err := callUnary(ctx, server, pb.SimpleBank_CreateAPIKey_FullMethodName, tc.req, server.CreateAPIKey)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
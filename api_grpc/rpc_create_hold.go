@@ -0,0 +1,82 @@
+package apigrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateHold reserves an amount on an account of the authenticated user for a later capture to another account
+// of the same currency. The hold expires after the configured hold duration unless it is captured or released.
+func (server *Server) CreateHold(ctx context.Context, req *pb.CreateHoldRequest) (*pb.CreateHoldResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validCreateHoldRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	account, err := server.validAccount(ctx, req.GetAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if account.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+
+	_, err = server.validAccount(ctx, req.GetToAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := server.store.CreateHoldTx(ctx, db.CreateHoldTxParams{
+		Owner:       authPayload.Username,
+		AccountID:   req.GetAccountId(),
+		ToAccountID: req.GetToAccountId(),
+		Amount:      req.GetAmount(),
+		Currency:    req.GetCurrency(),
+		ExpiresAt:   time.Now().Add(server.config.HoldDuration),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) {
+			return nil, status.Errorf(codes.FailedPrecondition, "account [%d] has insufficient funds", req.GetAccountId())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create hold: %s", err)
+	}
+
+	response := &pb.CreateHoldResponse{
+		Hold:    convertHold(result.Hold),
+		Account: convertAccount(result.Account),
+	}
+	return response, nil
+}
+
+func validCreateHoldRequest(req *pb.CreateHoldRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violation = append(violation, fieldViolation("account_id", err))
+	}
+	if err := val.ValidateID(req.GetToAccountId()); err != nil {
+		violation = append(violation, fieldViolation("to_account_id", err))
+	} else if req.GetToAccountId() == req.GetAccountId() {
+		violation = append(violation, fieldViolation("to_account_id", fmt.Errorf("must be different from account_id")))
+	}
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violation = append(violation, fieldViolation("currency", err))
+	} else if err := val.ValidateCurrencyAmount(req.GetAmount(), req.GetCurrency()); err != nil {
+		violation = append(violation, fieldViolation("amount", err))
+	}
+
+	return
+}
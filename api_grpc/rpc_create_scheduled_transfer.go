@@ -0,0 +1,100 @@
+package apigrpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateScheduledTransfer creates a standing order: a transfer between accounts of the same currency
+// that the worker makes at every occurrence of the schedule
+func (server *Server) CreateScheduledTransfer(ctx context.Context, req *pb.CreateScheduledTransferRequest) (*pb.CreateScheduledTransferResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validCreateScheduledTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if fromAccount.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "from account doesn't belong to the authenticated user")
+	}
+
+	_, err = server.validAccount(ctx, req.GetToAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	startsAt := time.Now()
+	if req.StartTime != nil {
+		startsAt = req.GetStartTime().AsTime()
+	}
+	startsAt = startsAt.Truncate(time.Second)
+
+	schedule, _ := util.ParseSchedule(req.GetSchedule())
+	nextRunAt, ok := schedule.Occurrence(startsAt, 0)
+	if !ok {
+		return nil, invalidArguementError([]*errdetails.BadRequest_FieldViolation{
+			fieldViolation("schedule", fmt.Errorf("ends before start_time")),
+		})
+	}
+
+	scheduledTransfer, err := server.store.CreateScheduledTransfer(ctx, db.CreateScheduledTransferParams{
+		Owner:         authPayload.Username,
+		FromAccountID: req.GetFromAccountId(),
+		ToAccountID:   req.GetToAccountId(),
+		Amount:        req.GetAmount(),
+		Currency:      req.GetCurrency(),
+		Schedule:      req.GetSchedule(),
+		StartsAt:      startsAt,
+		NextRunAt:     nextRunAt,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create scheduled transfer: %s", err)
+	}
+
+	response := &pb.CreateScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduledTransfer),
+	}
+	return response, nil
+}
+
+func validCreateScheduledTransferRequest(req *pb.CreateScheduledTransferRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetFromAccountId()); err != nil {
+		violation = append(violation, fieldViolation("from_account_id", err))
+	}
+	if err := val.ValidateID(req.GetToAccountId()); err != nil {
+		violation = append(violation, fieldViolation("to_account_id", err))
+	} else if req.GetToAccountId() == req.GetFromAccountId() {
+		violation = append(violation, fieldViolation("to_account_id", fmt.Errorf("must be different from from_account_id")))
+	}
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violation = append(violation, fieldViolation("currency", err))
+	} else if err := val.ValidateCurrencyAmount(req.GetAmount(), req.GetCurrency()); err != nil {
+		violation = append(violation, fieldViolation("amount", err))
+	}
+	if err := val.ValidateSchedule(req.GetSchedule()); err != nil {
+		violation = append(violation, fieldViolation("schedule", err))
+	}
+	if req.StartTime != nil && req.GetStartTime().AsTime().Before(time.Now().Add(-time.Minute)) {
+		violation = append(violation, fieldViolation("start_time", fmt.Errorf("must not be in the past")))
+	}
+
+	return
+}
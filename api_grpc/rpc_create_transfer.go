@@ -0,0 +1,144 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateTransfer(ctx context.Context, req *pb.CreateTransferRequest) (*pb.CreateTransferResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validCreateTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	idempotencyKey := extractIdempotencyKey(ctx)
+	if idempotencyKey != "" {
+		if err := val.ValidateIdempotencyKey(idempotencyKey); err != nil {
+			return nil, invalidArguementError([]*errdetails.BadRequest_FieldViolation{fieldViolation(idempotencyKeyHeader, err)})
+		}
+	}
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if fromAccount.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "from account doesn't belong to the authenticated user")
+	}
+
+	// a quoted transfer may credit an account of another currency, the quote carries the exchange rate
+	var quoteID uuid.NullUUID
+	if req.GetQuoteId() != "" {
+		quoteID = uuid.NullUUID{UUID: uuid.MustParse(req.GetQuoteId()), Valid: true}
+		_, err = server.getAccount(ctx, req.GetToAccountId())
+	} else {
+		_, err = server.validAccount(ctx, req.GetToAccountId(), req.GetCurrency())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := server.store.TransferTx(ctx, db.TransferParams{
+		FromAccountID: req.GetFromAccountId(),
+		ToAccountID:   req.GetToAccountId(),
+		Amount:        req.GetAmount(),
+		QuoteID:       quoteID,
+
+		Username:               authPayload.Username,
+		IdempotencyKey:         idempotencyKey,
+		IdempotencyKeyDuration: server.config.IdempotencyKeyDuration,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) {
+			return nil, status.Errorf(codes.FailedPrecondition, "account [%d] has insufficient funds", req.GetFromAccountId())
+		}
+		if errors.Is(err, db.ErrIdempotencyKeyReused) {
+			return nil, status.Errorf(codes.AlreadyExists, "%s", err)
+		}
+		if errors.Is(err, db.ErrInvalidQuote) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		if errors.Is(err, db.ErrQuoteMismatch) {
+			return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to transfer: %s", err)
+	}
+
+	response := &pb.CreateTransferResponse{
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry),
+		ToEntry:     convertEntry(result.ToEntry),
+	}
+	return response, nil
+}
+
+// validAccount checks that the account exists and holds the given currency.
+func (server *Server) validAccount(ctx context.Context, accountID int64, currency string) (db.Account, error) {
+	account, err := server.getAccount(ctx, accountID)
+	if err != nil {
+		return account, err
+	}
+
+	if account.Currency != currency {
+		return account, status.Errorf(codes.InvalidArgument, "account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, currency)
+	}
+
+	return account, nil
+}
+
+// getAccount loads the account.
+func (server *Server) getAccount(ctx context.Context, accountID int64) (db.Account, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return account, status.Errorf(codes.NotFound, "account [%d] not found", accountID)
+		}
+		return account, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	return account, nil
+}
+
+func validCreateTransferRequest(req *pb.CreateTransferRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetFromAccountId()); err != nil {
+		violation = append(violation, fieldViolation("from_account_id", err))
+	}
+	if err := val.ValidateID(req.GetToAccountId()); err != nil {
+		violation = append(violation, fieldViolation("to_account_id", err))
+	} else if req.GetToAccountId() == req.GetFromAccountId() {
+		violation = append(violation, fieldViolation("to_account_id", fmt.Errorf("must be different from from_account_id")))
+	}
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violation = append(violation, fieldViolation("currency", err))
+		if err := val.ValidateAmount(req.GetAmount()); err != nil {
+			violation = append(violation, fieldViolation("amount", err))
+		}
+	} else if err := val.ValidateCurrencyAmount(req.GetAmount(), req.GetCurrency()); err != nil {
+		violation = append(violation, fieldViolation("amount", err))
+	}
+	if req.GetQuoteId() != "" {
+		if _, err := uuid.Parse(req.GetQuoteId()); err != nil {
+			violation = append(violation, fieldViolation("quote_id", err))
+		}
+	}
+
+	return
+}
@@ -0,0 +1,59 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DeleteScheduledTransfer cancels a scheduled transfer, it is kept along with its history
+func (server *Server) DeleteScheduledTransfer(ctx context.Context, req *pb.DeleteScheduledTransferRequest) (*pb.DeleteScheduledTransferResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validDeleteScheduledTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	scheduledTransfer, err := server.getOwnedScheduledTransfer(ctx, authPayload.Username, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	switch scheduledTransfer.Status {
+	case util.ScheduleCompleted:
+		return nil, status.Errorf(codes.FailedPrecondition, "scheduled transfer is %s", scheduledTransfer.Status)
+	case util.ScheduleCancelled:
+	default:
+		scheduledTransfer, err = server.store.UpdateScheduledTransfer(ctx, db.UpdateScheduledTransferParams{
+			ID:     scheduledTransfer.ID,
+			Status: sql.NullString{String: util.ScheduleCancelled, Valid: true},
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to cancel scheduled transfer: %s", err)
+		}
+	}
+
+	response := &pb.DeleteScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduledTransfer),
+	}
+	return response, nil
+}
+
+func validDeleteScheduledTransferRequest(req *pb.DeleteScheduledTransferRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violation = append(violation, fieldViolation("id", err))
+	}
+
+	return
+}
@@ -0,0 +1,46 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// totpIssuer is the name authenticator apps show next to the codes
+const totpIssuer = "Simple Bank"
+
+func (server *Server) EnrollTOTP(ctx context.Context, req *pb.EnrollTOTPRequest) (*pb.EnrollTOTPResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s", err)
+	}
+
+	// enrolling again before confirming replaces the pending secret,
+	// a confirmed secret is never replaced
+	userTOTP, err := server.store.UpsertUserTOTP(ctx, db.UpsertUserTOTPParams{
+		Username: authPayload.Username,
+		Secret:   secret,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is already enabled")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to enroll totp: %s", err)
+	}
+
+	response := &pb.EnrollTOTPResponse{
+		Secret:     userTOTP.Secret,
+		OtpauthUri: util.TOTPURI(totpIssuer, authPayload.Username, userTOTP.Secret),
+	}
+	return response, nil
+}
@@ -0,0 +1,63 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/token"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.GetAccountResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validGetAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	account, err := server.getAuthorizedAccount(ctx, authPayload, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.GetAccountResponse{
+		Account: convertAccount(account),
+	}
+	return response, nil
+}
+
+// getAuthorizedAccount loads the account and checks that the authenticated user may access it.
+// Bankers may read any account; everyone else only their own.
+func (server *Server) getAuthorizedAccount(ctx context.Context, authPayload *token.Payload, accountID int64) (db.Account, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return account, status.Errorf(codes.NotFound, "account not found")
+		}
+		return account, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if account.Owner != authPayload.Username && !util.CanReadAnyAccount(authPayload.Role) {
+		return account, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+
+	return account, nil
+}
+
+func validGetAccountRequest(req *pb.GetAccountRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violation = append(violation, fieldViolation("id", err))
+	}
+
+	return
+}
@@ -0,0 +1,29 @@
+package apigrpc
+
+import (
+	"context"
+
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/token"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetPublicKeys returns the keys access tokens can be verified with, in JSON Web Key Set form,
+// so other services can verify tokens without being able to mint them
+func (server *Server) GetPublicKeys(ctx context.Context, req *pb.GetPublicKeysRequest) (*pb.GetPublicKeysResponse, error) {
+	provider, ok := server.tokenMaker.(token.PublicKeyProvider)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "tokens are signed with a symmetric key")
+	}
+
+	publicKeys := provider.PublicKeys()
+	response := &pb.GetPublicKeysResponse{
+		Keys: make([]*pb.PublicKey, 0, len(publicKeys)),
+	}
+	for _, publicKey := range publicKeys {
+		response.Keys = append(response.Keys, convertPublicKey(publicKey))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,41 @@
+package apigrpc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/token"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetPublicKeysAPI(t *testing.T) {
+	server := newTestServer(t, nil, nil)
+
+	// the test server signs with a symmetric key, which must never be published
+	_, err := callUnary(context.Background(), server, pb.SimpleBank_GetPublicKeys_FullMethodName, &pb.GetPublicKeysRequest{}, server.GetPublicKeys)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.Unimplemented, st.Code())
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	keySet := token.NewKeySet()
+	keySet.AddPrivateKey("key-1", privateKey)
+	server.tokenMaker, err = token.NewPasetoPublicMaker(keySet, "key-1")
+	require.NoError(t, err)
+
+	res, err := callUnary(context.Background(), server, pb.SimpleBank_GetPublicKeys_FullMethodName, &pb.GetPublicKeysRequest{}, server.GetPublicKeys)
+	require.NoError(t, err)
+	require.Len(t, res.GetKeys(), 1)
+
+	key := res.GetKeys()[0]
+	require.Equal(t, "key-1", key.GetKid())
+	require.Equal(t, "OKP", key.GetKty())
+	require.Equal(t, "Ed25519", key.GetCrv())
+	require.Equal(t, base64.RawURLEncoding.EncodeToString(publicKey), key.GetX())
+}
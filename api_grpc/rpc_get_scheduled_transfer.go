@@ -0,0 +1,78 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// scheduledTransferRunsLimit is how many of the latest runs GetScheduledTransfer returns
+const scheduledTransferRunsLimit = 20
+
+// GetScheduledTransfer returns a scheduled transfer of the authenticated user with its latest runs
+func (server *Server) GetScheduledTransfer(ctx context.Context, req *pb.GetScheduledTransferRequest) (*pb.GetScheduledTransferResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validGetScheduledTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	scheduledTransfer, err := server.getOwnedScheduledTransfer(ctx, authPayload.Username, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := server.store.ListScheduledTransferRuns(ctx, db.ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduledTransfer.ID,
+		Limit:               scheduledTransferRunsLimit,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list scheduled transfer runs: %s", err)
+	}
+
+	response := &pb.GetScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduledTransfer),
+		Runs:              make([]*pb.ScheduledTransferRun, 0, len(runs)),
+	}
+	for _, run := range runs {
+		response.Runs = append(response.Runs, convertScheduledTransferRun(run))
+	}
+	return response, nil
+}
+
+// getOwnedScheduledTransfer loads a scheduled transfer of username.
+// Scheduled transfers of other users are reported as not found, so their ids can't be probed.
+func (server *Server) getOwnedScheduledTransfer(ctx context.Context, username string, id int64) (db.ScheduledTransfer, error) {
+	scheduledTransfer, err := server.store.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return scheduledTransfer, status.Errorf(codes.NotFound, "scheduled transfer not found")
+		}
+		return scheduledTransfer, status.Errorf(codes.Internal, "failed to get scheduled transfer: %s", err)
+	}
+
+	if scheduledTransfer.Owner != username {
+		return db.ScheduledTransfer{}, status.Errorf(codes.NotFound, "scheduled transfer not found")
+	}
+
+	return scheduledTransfer, nil
+}
+
+func validGetScheduledTransferRequest(req *pb.GetScheduledTransferRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violation = append(violation, fieldViolation("id", err))
+	}
+
+	return
+}
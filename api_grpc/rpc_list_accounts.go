@@ -0,0 +1,62 @@
+package apigrpc
+
+import (
+	"context"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validListAccountsRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	scope := accountsPageScope(authPayload.Username)
+	cursorID, cursorCreatedAt, err := server.decodePageToken(scope, req.GetPageToken())
+	if err != nil {
+		return nil, invalidArguementError([]*errdetails.BadRequest_FieldViolation{fieldViolation("page_token", err)})
+	}
+
+	// fetch one extra row to know whether there is a next page
+	accounts, err := server.store.ListAccounts(ctx, db.ListAccountsParams{
+		Owner:           authPayload.Username,
+		CursorID:        cursorID,
+		CursorCreatedAt: cursorCreatedAt,
+		PageSize:        req.GetPageSize() + 1,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list accounts: %s", err)
+	}
+
+	response := &pb.ListAccountsResponse{}
+	if len(accounts) > int(req.GetPageSize()) {
+		accounts = accounts[:req.GetPageSize()]
+		last := accounts[len(accounts)-1]
+		response.NextPageToken = server.encodePageToken(scope, last.ID, last.CreatedAt)
+	}
+
+	response.Accounts = make([]*pb.Account, 0, len(accounts))
+	for _, account := range accounts {
+		response.Accounts = append(response.Accounts, convertAccount(account))
+	}
+	return response, nil
+}
+
+func validListAccountsRequest(req *pb.ListAccountsRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violation = append(violation, fieldViolation("page_size", err))
+	}
+
+	return
+}
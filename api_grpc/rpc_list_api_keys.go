@@ -0,0 +1,29 @@
+package apigrpc
+
+import (
+	"context"
+
+	"github.com/sangketkit01/simple-bank/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListAPIKeys(ctx context.Context, req *pb.ListAPIKeysRequest) (*pb.ListAPIKeysResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	apiKeys, err := server.store.ListAPIKeys(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list api keys: %s", err)
+	}
+
+	response := &pb.ListAPIKeysResponse{
+		ApiKeys: make([]*pb.APIKey, 0, len(apiKeys)),
+	}
+	for _, apiKey := range apiKeys {
+		response.ApiKeys = append(response.ApiKeys, convertAPIKey(apiKey))
+	}
+	return response, nil
+}
@@ -0,0 +1,78 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListEntries(ctx context.Context, req *pb.ListEntriesRequest) (*pb.ListEntriesResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validListEntriesRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	account, err := server.getAuthorizedAccount(ctx, authPayload, req.GetAccountId())
+	if err != nil {
+		return nil, err
+	}
+
+	scope := entriesPageScope(account.ID)
+	cursorID, cursorCreatedAt, err := server.decodePageToken(scope, req.GetPageToken())
+	if err != nil {
+		return nil, invalidArguementError([]*errdetails.BadRequest_FieldViolation{fieldViolation("page_token", err)})
+	}
+
+	// fetch one extra row to know whether there is a next page
+	entries, err := server.store.ListEntries(ctx, db.ListEntriesParams{
+		AccountID:       sql.NullInt64{Int64: account.ID, Valid: true},
+		StartTime:       convertNullTime(req.GetStartTime()),
+		EndTime:         convertNullTime(req.GetEndTime()),
+		Direction:       convertDirection(req.GetDirection()),
+		CursorID:        cursorID,
+		CursorCreatedAt: cursorCreatedAt,
+		PageSize:        req.GetPageSize() + 1,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list entries: %s", err)
+	}
+
+	response := &pb.ListEntriesResponse{}
+	if len(entries) > int(req.GetPageSize()) {
+		entries = entries[:req.GetPageSize()]
+		last := entries[len(entries)-1]
+		response.NextPageToken = server.encodePageToken(scope, last.ID, last.CreatedAt)
+	}
+
+	response.Entries = make([]*pb.Entry, 0, len(entries))
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, convertEntry(entry))
+	}
+	return response, nil
+}
+
+func validListEntriesRequest(req *pb.ListEntriesRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violation = append(violation, fieldViolation("account_id", err))
+	}
+	if req.StartTime != nil && req.EndTime != nil && !req.GetStartTime().AsTime().Before(req.GetEndTime().AsTime()) {
+		violation = append(violation, fieldViolation("end_time", fmt.Errorf("must be after start_time")))
+	}
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violation = append(violation, fieldViolation("page_size", err))
+	}
+
+	return
+}
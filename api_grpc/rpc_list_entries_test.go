@@ -0,0 +1,297 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/token"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func randomEntry(accountID int64, id int64) db.Entry {
+	return db.Entry{
+		ID:        id,
+		AccountID: sql.NullInt64{Int64: accountID, Valid: true},
+		Amount:    util.RandomMoney(),
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestListEntriesAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+
+	n := 6
+	entries := make([]db.Entry, n)
+	for i := range entries {
+		entries[i] = randomEntry(account.ID, int64(100-i))
+	}
+
+	pageSize := int32(5)
+	startTime := time.Now().Add(-time.Hour)
+	endTime := time.Now()
+
+	testCases := []struct {
+		name          string
+		req           *pb.ListEntriesRequest
+		pageToken     func(server *Server) string
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ListEntriesResponse, err error)
+	}{
+		{
+			name: "OKWithNextPage",
+			req: &pb.ListEntriesRequest{
+				AccountId: account.ID,
+				StartTime: timestamppb.New(startTime),
+				EndTime:   timestamppb.New(endTime),
+				Direction: pb.Direction_DIRECTION_INCOMING,
+				PageSize:  pageSize,
+			},
+			pageToken: func(server *Server) string {
+				return server.encodePageToken(entriesPageScope(account.ID), 200, startTime)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				arg := db.ListEntriesParams{
+					AccountID:       sql.NullInt64{Int64: account.ID, Valid: true},
+					StartTime:       sql.NullTime{Time: timestamppb.New(startTime).AsTime(), Valid: true},
+					EndTime:         sql.NullTime{Time: timestamppb.New(endTime).AsTime(), Valid: true},
+					Direction:       db.DirectionIncoming,
+					CursorID:        sql.NullInt64{Int64: 200, Valid: true},
+					CursorCreatedAt: sql.NullTime{Time: startTime.UTC().Truncate(time.Microsecond), Valid: true},
+					PageSize:        pageSize + 1,
+				}
+				store.EXPECT().
+					ListEntries(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entries, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetEntries(), int(pageSize))
+				require.NotEmpty(t, res.GetNextPageToken())
+			},
+		},
+		{
+			name: "OKLastPage",
+			req: &pb.ListEntriesRequest{
+				AccountId: account.ID,
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				arg := db.ListEntriesParams{
+					AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+					PageSize:  pageSize + 1,
+				}
+				store.EXPECT().
+					ListEntries(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entries[:2], nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetEntries(), 2)
+				require.Empty(t, res.GetNextPageToken())
+			},
+		},
+		{
+			name: "PermissionDenied",
+			req: &pb.ListEntriesRequest{
+				AccountId: account.ID,
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListEntries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "other_user", util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "InvalidTimeRange",
+			req: &pb.ListEntriesRequest{
+				AccountId: account.ID,
+				StartTime: timestamppb.New(endTime),
+				EndTime:   timestamppb.New(startTime),
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					ListEntries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "InvalidPageToken",
+			req: &pb.ListEntriesRequest{
+				AccountId: account.ID,
+				PageSize:  pageSize,
+			},
+			pageToken: func(server *Server) string {
+				return server.encodePageToken(entriesPageScope(account.ID+1), 200, startTime)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListEntries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "InvalidPageSize",
+			req: &pb.ListEntriesRequest{
+				AccountId: account.ID,
+				PageSize:  100,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					ListEntries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "InternalError",
+			req: &pb.ListEntriesRequest{
+				AccountId: account.ID,
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListEntries(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.Entry{}, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req: &pb.ListEntriesRequest{
+				AccountId: account.ID,
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					ListEntries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			if tc.pageToken != nil {
+				tc.req.PageToken = tc.pageToken(server)
+			}
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_ListEntries_FullMethodName, tc.req, server.ListEntries)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package apigrpc
+
+import (
+	"context"
+
+	"github.com/sangketkit01/simple-bank/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListScheduledTransfers(ctx context.Context, req *pb.ListScheduledTransfersRequest) (*pb.ListScheduledTransfersResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	scheduledTransfers, err := server.store.ListScheduledTransfers(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list scheduled transfers: %s", err)
+	}
+
+	response := &pb.ListScheduledTransfersResponse{
+		ScheduledTransfers: make([]*pb.ScheduledTransfer, 0, len(scheduledTransfers)),
+	}
+	for _, scheduledTransfer := range scheduledTransfers {
+		response.ScheduledTransfers = append(response.ScheduledTransfers, convertScheduledTransfer(scheduledTransfer))
+	}
+	return response, nil
+}
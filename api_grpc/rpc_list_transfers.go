@@ -0,0 +1,78 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListTransfers(ctx context.Context, req *pb.ListTransfersRequest) (*pb.ListTransfersResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validListTransfersRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	account, err := server.getAuthorizedAccount(ctx, authPayload, req.GetAccountId())
+	if err != nil {
+		return nil, err
+	}
+
+	scope := transfersPageScope(account.ID)
+	cursorID, cursorCreatedAt, err := server.decodePageToken(scope, req.GetPageToken())
+	if err != nil {
+		return nil, invalidArguementError([]*errdetails.BadRequest_FieldViolation{fieldViolation("page_token", err)})
+	}
+
+	// fetch one extra row to know whether there is a next page
+	transfers, err := server.store.ListTransfers(ctx, db.ListTransfersParams{
+		AccountID:       sql.NullInt64{Int64: account.ID, Valid: true},
+		StartTime:       convertNullTime(req.GetStartTime()),
+		EndTime:         convertNullTime(req.GetEndTime()),
+		Direction:       convertDirection(req.GetDirection()),
+		CursorID:        cursorID,
+		CursorCreatedAt: cursorCreatedAt,
+		PageSize:        req.GetPageSize() + 1,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list transfers: %s", err)
+	}
+
+	response := &pb.ListTransfersResponse{}
+	if len(transfers) > int(req.GetPageSize()) {
+		transfers = transfers[:req.GetPageSize()]
+		last := transfers[len(transfers)-1]
+		response.NextPageToken = server.encodePageToken(scope, last.ID, last.CreatedAt)
+	}
+
+	response.Transfers = make([]*pb.Transfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		response.Transfers = append(response.Transfers, convertTransfer(transfer))
+	}
+	return response, nil
+}
+
+func validListTransfersRequest(req *pb.ListTransfersRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violation = append(violation, fieldViolation("account_id", err))
+	}
+	if req.StartTime != nil && req.EndTime != nil && !req.GetStartTime().AsTime().Before(req.GetEndTime().AsTime()) {
+		violation = append(violation, fieldViolation("end_time", fmt.Errorf("must be after start_time")))
+	}
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violation = append(violation, fieldViolation("page_size", err))
+	}
+
+	return
+}
@@ -3,9 +3,13 @@ package apigrpc
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"time"
 
+	"github.com/google/uuid"
 	db "github.com/sangketkit01/simple-bank/db/sqlc"
 	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/ratelimit"
 	"github.com/sangketkit01/simple-bank/util"
 	"github.com/sangketkit01/simple-bank/val"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -20,62 +24,144 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 		return nil, invalidArguementError(violations)
 	}
 
-	user, err := server.store.GetUser(ctx, req.Username)
+	clientIP := server.extractMetadata(ctx).ClientIP
+	err := server.loginLimiter.Check(ctx, req.GetUsername(), clientIP)
+	if err != nil {
+		if errors.Is(err, ratelimit.ErrTooManyAttempts) {
+			return nil, status.Errorf(codes.ResourceExhausted, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot check login attempts: %s", err)
+	}
+
+	user, err := server.store.GetUser(ctx, req.GetUsername())
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, status.Errorf(codes.NotFound, "User not found: %s", err)
+			// spend the time of a password check, so the response time doesn't tell the username is unknown
+			util.CheckPassword(req.GetPassword(), util.DummyHashedPassword)
+			return nil, server.failLogin(ctx, req.GetUsername(), clientIP, false)
 		}
 
 		return nil, status.Errorf(codes.Internal, "cannot get user: %s", err)
 	}
 
-	err = util.CheckPassword(req.Password, user.HashedPassword)
-	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "incorrect password: %s", err)
+	// a locked user is not told apart from a wrong password, and only the dummy hash is checked
+	if time.Now().Before(user.LockedUntil) {
+		util.CheckPassword(req.GetPassword(), util.DummyHashedPassword)
+		return nil, server.failLogin(ctx, user.Username, clientIP, false)
 	}
 
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	err = util.CheckPassword(req.GetPassword(), user.HashedPassword)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot create token: %s", err)
+		return nil, server.failLogin(ctx, user.Username, clientIP, true)
 	}
 
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(
-		user.Username,
-		server.config.RefreshTokenDuration,
-	)
-
+	mfaRequired, err := server.isMFAEnabled(ctx, user.Username)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot create refresh token: %s", err)
+		return nil, status.Errorf(codes.Internal, "cannot get two-factor settings: %s", err)
 	}
 
-	mtdt := server.extractMetadata(ctx)
+	if mfaRequired {
+		challengeID, err := uuid.NewRandom()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot create mfa token: %s", err)
+		}
 
-	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
-		ID:           refreshPayload.ID,
-		Username:     user.Username,
-		RefreshToken: refreshToken,
-		UserAgent:    mtdt.UserAgent,
-		ClientIp:     mtdt.ClientIP,
-		IsBlock:      false,
-		ExpiredAt:    refreshPayload.ExpiredAt,
-	})
+		challenge, err := server.store.CreateMFAChallenge(ctx, db.CreateMFAChallengeParams{
+			ID:        challengeID,
+			Username:  user.Username,
+			ExpiredAt: time.Now().Add(server.config.MFAChallengeDuration),
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot create mfa challenge: %s", err)
+		}
 
+		// nothing about the user is returned until the second factor is verified
+		response := &pb.LoginUserResponse{
+			MfaRequired:     true,
+			MfaToken:        challenge.ID.String(),
+			MfaTokenExpired: timestamppb.New(challenge.ExpiredAt),
+		}
+		return response, nil
+	}
+
+	user, err = server.succeedLogin(ctx, user)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot create session: %s", err)
+		return nil, err
+	}
+
+	userSession, err := server.createUserSession(ctx, user)
+	if err != nil {
+		return nil, err
 	}
 
 	response := &pb.LoginUserResponse{
 		User:                convertUser(user),
-		SessionId:           session.ID.String(),
-		AccessToken:         accessToken,
-		AccessTokenExpired:  timestamppb.New(accessPayload.ExpiredAt),
-		RefreshToken:        refreshToken,
-		RefreshTokenExpired: timestamppb.New(refreshPayload.ExpiredAt),
+		SessionId:           userSession.session.ID.String(),
+		AccessToken:         userSession.accessToken,
+		AccessTokenExpired:  timestamppb.New(userSession.accessPayload.ExpiredAt),
+		RefreshToken:        userSession.refreshToken,
+		RefreshTokenExpired: timestamppb.New(userSession.refreshPayload.ExpiredAt),
 	}
 
 	return response, nil
 }
 
+
+// errInvalidCredentials is the answer to an unknown username, a wrong password and a locked user alike,
+// so a failed login does not tell which usernames exist
+var errInvalidCredentials = status.Error(codes.Unauthenticated, "incorrect username or password")
+
+// failLogin records a failed login and returns the error to answer it with.
+// Only a wrong password of an existing user counts towards the lockout of that user.
+func (server *Server) failLogin(ctx context.Context, username string, clientIP string, wrongPassword bool) error {
+	err := server.recordFailedLogin(ctx, username, clientIP, wrongPassword)
+	if err != nil {
+		return err
+	}
+
+	return errInvalidCredentials
+}
+
+// recordFailedLogin counts a failed login against the login limits, and against the lockout of the user
+// when the user got a password or a second factor wrong
+func (server *Server) recordFailedLogin(ctx context.Context, username string, clientIP string, wrongCredentials bool) error {
+	err := server.loginLimiter.Fail(ctx, username, clientIP)
+	if err != nil {
+		return status.Errorf(codes.Internal, "cannot record login attempt: %s", err)
+	}
+
+	if wrongCredentials {
+		_, err = server.store.RecordFailedLoginTx(ctx, db.RecordFailedLoginTxParams{
+			Username:           username,
+			LockoutThreshold:   server.config.LoginLockoutThreshold,
+			LockoutDuration:    server.config.LoginLockoutDuration,
+			MaxLockoutDuration: server.config.LoginMaxLockoutDuration,
+		})
+		if err != nil {
+			return status.Errorf(codes.Internal, "cannot record failed login: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// succeedLogin clears the failed logins of the user once every factor has been verified
+func (server *Server) succeedLogin(ctx context.Context, user db.User) (db.User, error) {
+	err := server.loginLimiter.Succeed(ctx, user.Username)
+	if err != nil {
+		return user, status.Errorf(codes.Internal, "cannot reset login attempts: %s", err)
+	}
+
+	if user.FailedLoginCount > 0 {
+		user, err = server.store.ResetFailedLogins(ctx, user.Username)
+		if err != nil {
+			return user, status.Errorf(codes.Internal, "cannot reset failed logins: %s", err)
+		}
+	}
+
+	return user, nil
+}
+
 func validLoginUserRequest(req *pb.LoginUserRequest) (violation []*errdetails.BadRequest_FieldViolation){
 	if err := val.ValidateUsername(req.GetUsername()) ; err != nil{
 		violation = append(violation, fieldViolation("username",err))
@@ -0,0 +1,181 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/ratelimit"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func requireInvalidCredentials(t *testing.T, err error) {
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.Unauthenticated, st.Code())
+	require.Equal(t, status.Convert(errInvalidCredentials).Message(), st.Message())
+}
+
+func TestLoginUserLockout(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		req           *pb.LoginUserRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.LoginUserResponse, err error)
+	}{
+		{
+			name: "ResetsFailedLogins",
+			req:  &pb.LoginUserRequest{Username: user.Username, Password: password},
+			buildStubs: func(store *mockdb.MockStore) {
+				failedUser := user
+				failedUser.FailedLoginCount = 3
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(failedUser, nil)
+				store.EXPECT().
+					ResetFailedLogins(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.UserTotp{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateSessionParams) (db.Session, error) {
+						return db.Session{ID: arg.ID, Username: arg.Username, ExpiredAt: arg.ExpiredAt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, res.GetAccessToken())
+			},
+		},
+		{
+			name: "UserNotFound",
+			req:  &pb.LoginUserRequest{Username: user.Username, Password: password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().
+					RecordFailedLoginTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				requireInvalidCredentials(t, err)
+			},
+		},
+		{
+			name: "IncorrectPassword",
+			req:  &pb.LoginUserRequest{Username: user.Username, Password: "incorrect"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				arg := db.RecordFailedLoginTxParams{
+					Username:           user.Username,
+					LockoutThreshold:   5,
+					LockoutDuration:    time.Minute,
+					MaxLockoutDuration: time.Hour,
+				}
+				store.EXPECT().
+					RecordFailedLoginTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				requireInvalidCredentials(t, err)
+			},
+		},
+		{
+			name: "Locked",
+			req:  &pb.LoginUserRequest{Username: user.Username, Password: password},
+			buildStubs: func(store *mockdb.MockStore) {
+				lockedUser := user
+				lockedUser.LockedUntil = time.Now().Add(time.Minute)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(lockedUser, nil)
+				store.EXPECT().
+					RecordFailedLoginTx(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				// even the right password is answered like a wrong one
+				requireInvalidCredentials(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			server.config.LoginLockoutThreshold = 5
+			server.config.LoginLockoutDuration = time.Minute
+			server.config.LoginMaxLockoutDuration = time.Hour
+
+			res, err := callUnary(context.Background(), server, pb.SimpleBank_LoginUser_FullMethodName, tc.req, server.LoginUser)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestLoginUserTooManyAttempts(t *testing.T) {
+	username := util.RandomOwner()
+
+	storeCtrl := gomock.NewController(t)
+	defer storeCtrl.Finish()
+	store := mockdb.NewMockStore(storeCtrl)
+
+	// unknown usernames are limited the same way as existing ones
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(username)).
+		Times(2).
+		Return(db.User{}, sql.ErrNoRows)
+
+	server := newTestServer(t, store, nil)
+	server.loginLimiter = ratelimit.NewLoginLimiter(util.Config{
+		LoginAttemptWindow:        time.Minute,
+		LoginUsernameAttemptLimit: 2,
+	})
+
+	req := &pb.LoginUserRequest{Username: username, Password: util.RandomString(6)}
+	for i := 0; i < 2; i++ {
+		_, err := callUnary(context.Background(), server, pb.SimpleBank_LoginUser_FullMethodName, req, server.LoginUser)
+		requireInvalidCredentials(t, err)
+	}
+
+	_, err := callUnary(context.Background(), server, pb.SimpleBank_LoginUser_FullMethodName, req, server.LoginUser)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.ResourceExhausted, st.Code())
+}
@@ -0,0 +1,47 @@
+package apigrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sangketkit01/simple-bank/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	violations := validLogoutRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	_, session, err := server.validSession(ctx, req.GetRefreshToken())
+	if err != nil {
+		return nil, err
+	}
+
+	// block the whole family, so access tokens issued before a rotation stop working too
+	sessionIDs, err := server.store.BlockSessionFamily(ctx, session.FamilyID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to block session: %s", err)
+	}
+
+	err = server.revocationChecker.Revoke(ctx, sessionIDs...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke session: %s", err)
+	}
+
+	response := &pb.LogoutResponse{
+		SessionId: session.ID.String(),
+	}
+	return response, nil
+}
+
+func validLogoutRequest(req *pb.LogoutRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if req.GetRefreshToken() == "" {
+		violation = append(violation, fieldViolation("refresh_token", fmt.Errorf("must not be empty")))
+	}
+
+	return
+}
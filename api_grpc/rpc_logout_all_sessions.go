@@ -0,0 +1,34 @@
+package apigrpc
+
+import (
+	"context"
+
+	"github.com/sangketkit01/simple-bank/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) LogoutAllSessions(ctx context.Context, req *pb.LogoutAllSessionsRequest) (*pb.LogoutAllSessionsResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	sessionIDs, err := server.store.BlockUserSessions(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to block sessions: %s", err)
+	}
+
+	err = server.revocationChecker.Revoke(ctx, sessionIDs...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke sessions: %s", err)
+	}
+
+	response := &pb.LogoutAllSessionsResponse{
+		SessionIds: make([]string, 0, len(sessionIDs)),
+	}
+	for _, id := range sessionIDs {
+		response.SessionIds = append(response.SessionIds, id.String())
+	}
+	return response, nil
+}
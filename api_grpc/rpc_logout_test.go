@@ -0,0 +1,284 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestLogoutAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildSession  func(t *testing.T, tokenMaker token.Maker) (string, db.Session)
+		buildStubs    func(store *mockdb.MockStore, session db.Session)
+		checkResponse func(t *testing.T, session db.Session, res *pb.LogoutResponse, err error)
+	}{
+		{
+			name: "OK",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (string, db.Session) {
+				return newTestSession(t, tokenMaker, user.Username, time.Hour)
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+
+				store.EXPECT().
+					BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).
+					Times(1).
+					Return([]uuid.UUID{session.ID}, nil)
+			},
+			checkResponse: func(t *testing.T, session db.Session, res *pb.LogoutResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, session.ID.String(), res.GetSessionId())
+			},
+		},
+		{
+			name: "AlreadyBlocked",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (string, db.Session) {
+				refreshToken, session := newTestSession(t, tokenMaker, user.Username, time.Hour)
+				session.IsBlock = true
+				return refreshToken, session
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					BlockSessionFamily(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, session db.Session, res *pb.LogoutResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "IncorrectSessionUser",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (string, db.Session) {
+				refreshToken, session := newTestSession(t, tokenMaker, user.Username, time.Hour)
+				session.Username = "other_user"
+				return refreshToken, session
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					BlockSessionFamily(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, session db.Session, res *pb.LogoutResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "InternalError",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (string, db.Session) {
+				return newTestSession(t, tokenMaker, user.Username, time.Hour)
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, session db.Session, res *pb.LogoutResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			server := newTestServer(t, store, nil)
+			refreshToken, session := tc.buildSession(t, server.tokenMaker)
+			tc.buildStubs(store, session)
+
+			res, err := server.Logout(context.Background(), &pb.LogoutRequest{RefreshToken: refreshToken})
+			tc.checkResponse(t, session, res, err)
+		})
+	}
+}
+
+func TestLogoutAllSessionsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	sessionIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.LogoutAllSessionsResponse, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(sessionIDs, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.LogoutAllSessionsResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []string{sessionIDs[0].String(), sessionIDs[1].String()}, res.GetSessionIds())
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.LogoutAllSessionsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		},
+		{
+			name: "NoAuthorization",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.LogoutAllSessionsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_LogoutAllSessions_FullMethodName, &pb.LogoutAllSessionsRequest{}, server.LogoutAllSessions)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestLogoutRevokesAccessTokens(t *testing.T) {
+	user, _ := randomUser(t)
+
+	storeCtrl := gomock.NewController(t)
+	defer storeCtrl.Finish()
+	store := mockdb.NewMockStore(storeCtrl)
+
+	server := newTestServer(t, store, nil)
+	refreshToken, session := newTestSession(t, server.tokenMaker, user.Username, time.Hour)
+
+	// the session was rotated from an earlier one of its family, whose access token is still around
+	rotatedSessionID := uuid.New()
+	session.FamilyID = rotatedSessionID
+
+	newContext := func(sessionID uuid.UUID) context.Context {
+		accessToken, _, err := server.tokenMaker.CreateToken(user.Username, user.Role, sessionID, time.Minute, token.TokenTypeAccessToken)
+		require.NoError(t, err)
+		return metadata.NewIncomingContext(context.Background(), metadata.MD{
+			authorizationHeader: []string{fmt.Sprintf("%s %s", authorizationBearer, accessToken)},
+		})
+	}
+	ctx := newContext(session.ID)
+	rotatedCtx := newContext(rotatedSessionID)
+
+	_, err := server.authorizaUser(ctx, allRoles)
+	require.NoError(t, err)
+	_, err = server.authorizaUser(rotatedCtx, allRoles)
+	require.NoError(t, err)
+
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Eq(session.ID)).
+		Times(1).
+		Return(session, nil)
+	store.EXPECT().
+		BlockSessionFamily(gomock.Any(), gomock.Eq(rotatedSessionID)).
+		Times(1).
+		Return([]uuid.UUID{rotatedSessionID, session.ID}, nil)
+
+	_, err = server.Logout(context.Background(), &pb.LogoutRequest{RefreshToken: refreshToken})
+	require.NoError(t, err)
+
+	// the access tokens of every session of the family stop working before they expire
+	_, err = server.authorizaUser(ctx, allRoles)
+	require.ErrorIs(t, err, token.ErrRevokedToken)
+	_, err = server.authorizaUser(rotatedCtx, allRoles)
+	require.ErrorIs(t, err, token.ErrRevokedToken)
+}
+
+func TestRefreshTokenIsNotABearerToken(t *testing.T) {
+	user, _ := randomUser(t)
+
+	storeCtrl := gomock.NewController(t)
+	defer storeCtrl.Finish()
+	store := mockdb.NewMockStore(storeCtrl)
+
+	server := newTestServer(t, store, nil)
+	refreshToken, _ := newTestSession(t, server.tokenMaker, user.Username, time.Hour)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{
+		authorizationHeader: []string{fmt.Sprintf("%s %s", authorizationBearer, refreshToken)},
+	})
+	_, err := server.authorizaUser(ctx, allRoles)
+	require.Error(t, err)
+}
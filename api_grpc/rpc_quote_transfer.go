@@ -0,0 +1,114 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// QuoteTransfer locks in the exchange rate of a transfer between accounts of different currencies.
+// The quote is valid for a short time and is used once by passing its id to CreateTransfer.
+func (server *Server) QuoteTransfer(ctx context.Context, req *pb.QuoteTransferRequest) (*pb.QuoteTransferResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validQuoteTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	fromAccount, err := server.getAccount(ctx, req.GetFromAccountId())
+	if err != nil {
+		return nil, err
+	}
+
+	if fromAccount.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "from account doesn't belong to the authenticated user")
+	}
+
+	if err := val.ValidateCurrencyAmount(req.GetAmount(), fromAccount.Currency); err != nil {
+		return nil, invalidArguementError([]*errdetails.BadRequest_FieldViolation{fieldViolation("amount", err)})
+	}
+
+	toAccount, err := server.getAccount(ctx, req.GetToAccountId())
+	if err != nil {
+		return nil, err
+	}
+
+	if fromAccount.Currency == toAccount.Currency {
+		return nil, status.Errorf(codes.InvalidArgument, "accounts have the same currency %s, no quote is needed", fromAccount.Currency)
+	}
+
+	exchangeRate, err := server.store.GetLatestExchangeRate(ctx, db.GetLatestExchangeRateParams{
+		BaseCurrency:  fromAccount.Currency,
+		QuoteCurrency: toAccount.Currency,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.FailedPrecondition, "no exchange rate from %s to %s", fromAccount.Currency, toAccount.Currency)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get exchange rate: %s", err)
+	}
+
+	creditAmount, err := util.ConvertAmount(req.GetAmount(), exchangeRate.Rate)
+	if err == nil {
+		// the to account can only be credited what its currency can express
+		creditAmount, err = util.RoundAmount(creditAmount, toAccount.Currency)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to convert amount: %s", err)
+	}
+
+	if creditAmount <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "amount too small to convert from %s to %s", fromAccount.Currency, toAccount.Currency)
+	}
+
+	quote, err := server.store.CreateTransferQuote(ctx, db.CreateTransferQuoteParams{
+		ID:             uuid.New(),
+		Username:       authPayload.Username,
+		FromAccountID:  fromAccount.ID,
+		ToAccountID:    toAccount.ID,
+		Amount:         req.GetAmount(),
+		CreditAmount:   creditAmount,
+		ExchangeRateID: exchangeRate.ID,
+		ExchangeRate:   exchangeRate.Rate,
+		ExpiredAt:      time.Now().Add(server.config.TransferQuoteDuration),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create transfer quote: %s", err)
+	}
+
+	response := &pb.QuoteTransferResponse{
+		Quote: convertTransferQuote(quote, fromAccount, toAccount),
+	}
+	return response, nil
+}
+
+func validQuoteTransferRequest(req *pb.QuoteTransferRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetFromAccountId()); err != nil {
+		violation = append(violation, fieldViolation("from_account_id", err))
+	}
+	if err := val.ValidateID(req.GetToAccountId()); err != nil {
+		violation = append(violation, fieldViolation("to_account_id", err))
+	} else if req.GetToAccountId() == req.GetFromAccountId() {
+		violation = append(violation, fieldViolation("to_account_id", fmt.Errorf("must be different from from_account_id")))
+	}
+	if err := val.ValidateAmount(req.GetAmount()); err != nil {
+		violation = append(violation, fieldViolation("amount", err))
+	}
+
+	return
+}
@@ -0,0 +1,320 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/token"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestQuoteTransferAPI(t *testing.T) {
+	amount := int64(100)
+
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+
+	account1 := randomAccount(user1.Username)
+	account2 := randomAccount(user2.Username)
+	account3 := randomAccount(user2.Username)
+
+	account4 := randomAccount(user2.Username)
+
+	account1.ID, account2.ID, account3.ID, account4.ID = 1, 2, 3, 4
+	account1.Currency = util.USD
+	account2.Currency = util.EUR
+	account3.Currency = util.USD
+	account4.Currency = "JPY"
+
+	require.NoError(t, util.SetCurrencies([]util.Currency{
+		{Code: util.USD, MinorUnit: 2, Enabled: true},
+		{Code: util.EUR, MinorUnit: 2, Enabled: true},
+		{Code: "JPY", MinorUnit: 0, Enabled: true},
+	}))
+	defer util.SetCurrencies([]util.Currency{
+		{Code: util.USD, MinorUnit: 2, Enabled: true},
+		{Code: util.EUR, MinorUnit: 2, Enabled: true},
+		{Code: util.CAD, MinorUnit: 2, Enabled: true},
+	})
+
+	exchangeRate := db.ExchangeRate{
+		ID:            1,
+		BaseCurrency:  util.USD,
+		QuoteCurrency: util.EUR,
+		Rate:          "0.9250000000",
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.QuoteTransferRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.QuoteTransferResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.QuoteTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().
+					GetLatestExchangeRate(gomock.Any(), gomock.Eq(db.GetLatestExchangeRateParams{
+						BaseCurrency:  util.USD,
+						QuoteCurrency: util.EUR,
+					})).
+					Times(1).
+					Return(exchangeRate, nil)
+				store.EXPECT().
+					CreateTransferQuote(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTransferQuoteParams) (db.TransferQuote, error) {
+						require.Equal(t, user1.Username, arg.Username)
+						require.Equal(t, amount, arg.Amount)
+						require.Equal(t, int64(92), arg.CreditAmount)
+						require.Equal(t, exchangeRate.ID, arg.ExchangeRateID)
+						require.WithinDuration(t, time.Now().Add(time.Minute), arg.ExpiredAt, time.Second)
+
+						return db.TransferQuote{
+							ID:             arg.ID,
+							Username:       arg.Username,
+							FromAccountID:  arg.FromAccountID,
+							ToAccountID:    arg.ToAccountID,
+							Amount:         arg.Amount,
+							CreditAmount:   arg.CreditAmount,
+							ExchangeRateID: arg.ExchangeRateID,
+							ExchangeRate:   arg.ExchangeRate,
+							ExpiredAt:      arg.ExpiredAt,
+						}, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.QuoteTransferResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+
+				quote := res.GetQuote()
+				_, err = uuid.Parse(quote.GetId())
+				require.NoError(t, err)
+				require.Equal(t, amount, quote.GetAmount())
+				require.Equal(t, util.USD, quote.GetFromCurrency())
+				require.Equal(t, int64(92), quote.GetCreditAmount())
+				require.Equal(t, util.EUR, quote.GetToCurrency())
+				require.Equal(t, exchangeRate.Rate, quote.GetExchangeRate())
+			},
+		},
+		{
+			name: "RoundedToCurrencyPrecision",
+			req: &pb.QuoteTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account4.ID,
+				Amount:        1050,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account4.ID)).Times(1).Return(account4, nil)
+				store.EXPECT().
+					GetLatestExchangeRate(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ExchangeRate{ID: 2, BaseCurrency: util.USD, QuoteCurrency: "JPY", Rate: "149.5300000000"}, nil)
+				store.EXPECT().
+					CreateTransferQuote(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTransferQuoteParams) (db.TransferQuote, error) {
+						// 10.50 USD is 1570.065 JPY, which has no decimal places
+						require.Equal(t, int64(157000), arg.CreditAmount)
+						return db.TransferQuote{ID: arg.ID, Amount: arg.Amount, CreditAmount: arg.CreditAmount}, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.QuoteTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(157000), res.GetQuote().GetCreditAmount())
+			},
+		},
+		{
+			name: "AmountPrecision",
+			req: &pb.QuoteTransferRequest{
+				FromAccountId: account4.ID,
+				ToAccountId:   account1.ID,
+				Amount:        1050,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account4.ID)).Times(1).Return(account4, nil)
+				store.EXPECT().GetLatestExchangeRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user2.Username, user2.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.QuoteTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			req: &pb.QuoteTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().CreateTransferQuote(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user2.Username, user2.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.QuoteTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "SameCurrency",
+			req: &pb.QuoteTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account3.ID,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account3.ID)).Times(1).Return(account3, nil)
+				store.EXPECT().GetLatestExchangeRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.QuoteTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "NoExchangeRate",
+			req: &pb.QuoteTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetLatestExchangeRate(gomock.Any(), gomock.Any()).Times(1).Return(db.ExchangeRate{}, sql.ErrNoRows)
+				store.EXPECT().CreateTransferQuote(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.QuoteTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "AmountTooSmall",
+			req: &pb.QuoteTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        1,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetLatestExchangeRate(gomock.Any(), gomock.Any()).Times(1).Return(exchangeRate, nil)
+				store.EXPECT().CreateTransferQuote(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.QuoteTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "SameAccount",
+			req: &pb.QuoteTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account1.ID,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.QuoteTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req: &pb.QuoteTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.QuoteTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_QuoteTransfer_FullMethodName, tc.req, server.QuoteTransfer)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package apigrpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReleaseHold cancels an active hold without moving money, its amount becomes available again.
+// The payee may release a hold at any time, the payer only once it has expired.
+func (server *Server) ReleaseHold(ctx context.Context, req *pb.ReleaseHoldRequest) (*pb.ReleaseHoldResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validReleaseHoldRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	hold, isPayee, err := server.getHold(ctx, authPayload.Username, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	// the payer could otherwise take back funds the payee is counting on
+	expired := !time.Now().Before(hold.ExpiresAt)
+	if !isPayee && !expired {
+		return nil, status.Errorf(codes.FailedPrecondition, "only the payee can release a hold before it expires")
+	}
+
+	result, err := server.store.ReleaseHoldTx(ctx, db.ReleaseHoldTxParams{
+		HoldID: hold.ID,
+		Expire: !isPayee,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrHoldNotActive) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to release hold: %s", err)
+	}
+
+	response := &pb.ReleaseHoldResponse{
+		Hold:    convertHold(result.Hold),
+		Account: convertAccount(result.Account),
+	}
+	return response, nil
+}
+
+func validReleaseHoldRequest(req *pb.ReleaseHoldRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violation = append(violation, fieldViolation("id", err))
+	}
+
+	return
+}
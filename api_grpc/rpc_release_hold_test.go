@@ -0,0 +1,144 @@
+package apigrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/token"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReleaseHoldAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	payee, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		expired       bool
+		buildStubs    func(store *mockdb.MockStore, hold db.AccountHold)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ReleaseHoldResponse, err error)
+	}{
+		{
+			name: "Payee",
+			buildStubs: func(store *mockdb.MockStore, hold db.AccountHold) {
+				hold.Status = util.HoldReleased
+
+				store.EXPECT().
+					ReleaseHoldTx(gomock.Any(), gomock.Eq(db.ReleaseHoldTxParams{HoldID: hold.ID})).
+					Times(1).
+					Return(db.ReleaseHoldTxResult{Hold: hold}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, payee.Username, payee.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReleaseHoldResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, util.HoldReleased, res.GetHold().GetStatus())
+			},
+		},
+		{
+			name:    "PayerAfterExpiry",
+			expired: true,
+			buildStubs: func(store *mockdb.MockStore, hold db.AccountHold) {
+				hold.Status = util.HoldExpired
+
+				store.EXPECT().
+					ReleaseHoldTx(gomock.Any(), gomock.Eq(db.ReleaseHoldTxParams{HoldID: hold.ID, Expire: true})).
+					Times(1).
+					Return(db.ReleaseHoldTxResult{Hold: hold}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReleaseHoldResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, util.HoldExpired, res.GetHold().GetStatus())
+			},
+		},
+		{
+			name: "PayerBeforeExpiry",
+			buildStubs: func(store *mockdb.MockStore, hold db.AccountHold) {
+				store.EXPECT().ReleaseHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReleaseHoldResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "NotActive",
+			buildStubs: func(store *mockdb.MockStore, hold db.AccountHold) {
+				store.EXPECT().ReleaseHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ReleaseHoldTxResult{}, db.ErrHoldNotActive)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, payee.Username, payee.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReleaseHoldResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "OtherUser",
+			buildStubs: func(store *mockdb.MockStore, hold db.AccountHold) {
+				store.EXPECT().ReleaseHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, other.Username, other.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReleaseHoldResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			hold := randomHold(user.Username)
+			if tc.expired {
+				hold.ExpiresAt = time.Now().Add(-time.Minute)
+			}
+			store.EXPECT().
+				GetAccountHold(gomock.Any(), gomock.Eq(hold.ID)).
+				AnyTimes().
+				Return(hold, nil)
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(hold.ToAccountID)).
+				AnyTimes().
+				Return(db.Account{ID: hold.ToAccountID, Owner: payee.Username, Currency: hold.Currency}, nil)
+			tc.buildStubs(store, hold)
+
+			server := newTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			req := &pb.ReleaseHoldRequest{Id: hold.ID}
+			res, err := callUnary(ctx, server, pb.SimpleBank_ReleaseHold_FullMethodName, req, server.ReleaseHold)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package apigrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/token"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (server *Server) RenewAccessToken(ctx context.Context, req *pb.RenewAccessTokenRequest) (*pb.RenewAccessTokenResponse, error) {
+	violations := validRenewAccessTokenRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	refreshPayload, session, err := server.validSession(ctx, req.GetRefreshToken())
+	if err != nil {
+		return nil, err
+	}
+
+	// the new refresh token keeps the expiry of the session it replaces,
+	// so rotating does not extend the lifetime of a login
+	newSessionID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create session id: %s", err)
+	}
+
+	refreshToken, newRefreshPayload, err := server.tokenMaker.CreateToken(
+		refreshPayload.Username,
+		refreshPayload.Role,
+		newSessionID,
+		time.Until(session.ExpiredAt),
+		token.TokenTypeRefreshToken,
+	)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create refresh token: %s", err)
+	}
+
+	mtdt := server.extractMetadata(ctx)
+
+	result, err := server.store.RotateSessionTx(ctx, db.RotateSessionTxParams{
+		Session: session,
+		NewSession: db.CreateSessionParams{
+			ID:           newSessionID,
+			Username:     session.Username,
+			RefreshToken: refreshToken,
+			UserAgent:    mtdt.UserAgent,
+			ClientIp:     mtdt.ClientIP,
+			IsBlock:      false,
+			ExpiredAt:    newRefreshPayload.ExpiredAt,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRefreshTokenReused) {
+			return nil, server.refreshTokenReused(ctx, session, result.BlockedSessionIDs)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot rotate session: %s", err)
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
+		refreshPayload.Username,
+		refreshPayload.Role,
+		result.Session.ID,
+		server.config.AccessTokenDuration,
+		token.TokenTypeAccessToken,
+	)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create token: %s", err)
+	}
+
+	response := &pb.RenewAccessTokenResponse{
+		AccessToken:         accessToken,
+		AccessTokenExpired:  timestamppb.New(accessPayload.ExpiredAt),
+		SessionId:           result.Session.ID.String(),
+		RefreshToken:        refreshToken,
+		RefreshTokenExpired: timestamppb.New(result.Session.ExpiredAt),
+	}
+	return response, nil
+}
+
+func validRenewAccessTokenRequest(req *pb.RenewAccessTokenRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if req.GetRefreshToken() == "" {
+		violation = append(violation, fieldViolation("refresh_token", fmt.Errorf("must not be empty")))
+	}
+
+	return
+}
@@ -0,0 +1,244 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/token"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTestSession creates a refresh token and the matching session row
+func newTestSession(t *testing.T, tokenMaker token.Maker, username string, duration time.Duration) (string, db.Session) {
+	refreshToken, refreshPayload, err := tokenMaker.CreateToken(username, util.DepositorRole, uuid.New(), duration, token.TokenTypeRefreshToken)
+	require.NoError(t, err)
+
+	session := db.Session{
+		ID:           refreshPayload.SessionID,
+		Username:     username,
+		RefreshToken: refreshToken,
+		ExpiredAt:    refreshPayload.ExpiredAt,
+		CreatedAt:    refreshPayload.IssuedAt,
+		FamilyID:     refreshPayload.SessionID,
+	}
+	return refreshToken, session
+}
+
+func TestRenewAccessTokenAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildSession  func(t *testing.T, tokenMaker token.Maker) (string, db.Session)
+		buildStubs    func(store *mockdb.MockStore, session db.Session)
+		checkResponse func(t *testing.T, res *pb.RenewAccessTokenResponse, err error)
+	}{
+		{
+			name: "OK",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (string, db.Session) {
+				return newTestSession(t, tokenMaker, user.Username, time.Hour)
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RotateSessionTxParams) (db.RotateSessionTxResult, error) {
+						require.Equal(t, session, arg.Session)
+						require.Equal(t, session.Username, arg.NewSession.Username)
+						require.NotEqual(t, session.RefreshToken, arg.NewSession.RefreshToken)
+						// rotating keeps the expiry of the login
+						require.WithinDuration(t, session.ExpiredAt, arg.NewSession.ExpiredAt, time.Second)
+
+						newSession := db.Session{
+							ID:           arg.NewSession.ID,
+							Username:     arg.NewSession.Username,
+							RefreshToken: arg.NewSession.RefreshToken,
+							ExpiredAt:    arg.NewSession.ExpiredAt,
+							FamilyID:     session.FamilyID,
+						}
+						return db.RotateSessionTxResult{Session: newSession}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, res.GetAccessToken())
+				// the renewed token lives as long as an access token, not a refresh token
+				require.WithinDuration(t, time.Now().Add(time.Minute), res.GetAccessTokenExpired().AsTime(), time.Second)
+				require.NotEmpty(t, res.GetRefreshToken())
+				require.NotEmpty(t, res.GetSessionId())
+			},
+		},
+		{
+			name: "BlockedSession",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (string, db.Session) {
+				refreshToken, session := newTestSession(t, tokenMaker, user.Username, time.Hour)
+				session.IsBlock = true
+				return refreshToken, session
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "RotatedTokenReused",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (string, db.Session) {
+				refreshToken, session := newTestSession(t, tokenMaker, user.Username, time.Hour)
+				session.IsRotated = true
+				return refreshToken, session
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).
+					Times(1).
+					Return([]uuid.UUID{session.ID, uuid.New()}, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "ConcurrentReuse",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (string, db.Session) {
+				return newTestSession(t, tokenMaker, user.Username, time.Hour)
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RotateSessionTxResult{BlockedSessionIDs: []uuid.UUID{session.ID}}, db.ErrRefreshTokenReused)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "MismatchedSessionToken",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (string, db.Session) {
+				refreshToken, session := newTestSession(t, tokenMaker, user.Username, time.Hour)
+				session.RefreshToken = "other_token"
+				return refreshToken, session
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "SessionNotFound",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (string, db.Session) {
+				return newTestSession(t, tokenMaker, user.Username, time.Hour)
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(db.Session{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+		{
+			name: "ExpiredRefreshToken",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (string, db.Session) {
+				return newTestSession(t, tokenMaker, user.Username, -time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "EmptyRefreshToken",
+			buildSession: func(t *testing.T, tokenMaker token.Maker) (string, db.Session) {
+				return "", db.Session{}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			server := newTestServer(t, store, nil)
+			refreshToken, session := tc.buildSession(t, server.tokenMaker)
+			tc.buildStubs(store, session)
+
+			res, err := server.RenewAccessToken(context.Background(), &pb.RenewAccessTokenRequest{RefreshToken: refreshToken})
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package apigrpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hibiken/asynq"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/val"
+	"github.com/sangketkit01/simple-bank/worker"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RequestPasswordReset queues a reset email for the address.
+// It answers the same way whether or not the address belongs to a user, so it cannot be used to discover accounts.
+// The requests per address and per client IP are limited within PasswordResetRequestWindow,
+// a limit of zero or less disables it.
+func (server *Server) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	violations := validRequestPasswordResetRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	clientIP := clientHost(server.extractMetadata(ctx).ClientIP)
+	exceeded, err := server.passwordResetLimitExceeded(ctx, "password_reset:ip:"+clientIP, server.config.PasswordResetIPLimit)
+	if err != nil {
+		return nil, err
+	}
+	if !exceeded {
+		// addresses are counted whether or not they belong to a user, so the limit tells nothing either
+		exceeded, err = server.passwordResetLimitExceeded(ctx, "password_reset:email:"+strings.ToLower(req.GetEmail()), server.config.PasswordResetEmailLimit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if exceeded {
+		return nil, status.Errorf(codes.ResourceExhausted, "too many password resets requested, try again later")
+	}
+
+	taskPayload := &worker.PayloadSendPasswordResetEmail{
+		Email: req.GetEmail(),
+	}
+
+	opts := []asynq.Option{
+		asynq.MaxRetry(10),
+		asynq.Queue(worker.QueueCtitical),
+	}
+
+	err = server.taskDistributor.DistributeTaskSendPasswordResetEmail(ctx, taskPayload, opts...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to request password reset: %s", err)
+	}
+
+	return &pb.RequestPasswordResetResponse{}, nil
+}
+
+// passwordResetLimitExceeded records a password reset request for key and reports whether it is over the limit.
+func (server *Server) passwordResetLimitExceeded(ctx context.Context, key string, limit int) (bool, error) {
+	count, err := server.passwordResetLimiter.Add(ctx, key)
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to check password reset limit: %s", err)
+	}
+
+	return limit > 0 && count > int64(limit), nil
+}
+
+func validRequestPasswordResetRequest(req *pb.RequestPasswordResetRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateEmail(req.GetEmail()); err != nil {
+		violation = append(violation, fieldViolation("email", err))
+	}
+
+	return
+}
@@ -0,0 +1,155 @@
+package apigrpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/ratelimit"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/sangketkit01/simple-bank/worker"
+	mockwk "github.com/sangketkit01/simple-bank/worker/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestPasswordResetAPI(t *testing.T) {
+	email := util.RandomEmail()
+
+	testCases := []struct {
+		name          string
+		req           *pb.RequestPasswordResetRequest
+		buildStubs    func(taskDistributor *mockwk.MockTaskDistributor)
+		checkResponse func(t *testing.T, res *pb.RequestPasswordResetResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.RequestPasswordResetRequest{Email: email},
+			buildStubs: func(taskDistributor *mockwk.MockTaskDistributor) {
+				taskPayload := &worker.PayloadSendPasswordResetEmail{
+					Email: email,
+				}
+				taskDistributor.EXPECT().
+					DistributeTaskSendPasswordResetEmail(gomock.Any(), gomock.Eq(taskPayload), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+			},
+		},
+		{
+			name: "InvalidEmail",
+			req:  &pb.RequestPasswordResetRequest{Email: "invalid-email"},
+			buildStubs: func(taskDistributor *mockwk.MockTaskDistributor) {
+				taskDistributor.EXPECT().
+					DistributeTaskSendPasswordResetEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "DistributeTaskError",
+			req:  &pb.RequestPasswordResetRequest{Email: email},
+			buildStubs: func(taskDistributor *mockwk.MockTaskDistributor) {
+				taskDistributor.EXPECT().
+					DistributeTaskSendPasswordResetEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(fmt.Errorf("redis is down"))
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			taskCtrl := gomock.NewController(t)
+			defer taskCtrl.Finish()
+			taskDistributor := mockwk.NewMockTaskDistributor(taskCtrl)
+
+			tc.buildStubs(taskDistributor)
+
+			// the store is never touched, so the response does not depend on whether the email is registered
+			server := newTestServer(t, nil, taskDistributor)
+			res, err := callUnary(context.Background(), server, pb.SimpleBank_RequestPasswordReset_FullMethodName, tc.req, server.RequestPasswordReset)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestRequestPasswordResetRateLimit(t *testing.T) {
+	newServer := func(t *testing.T, sent int) *Server {
+		taskCtrl := gomock.NewController(t)
+		t.Cleanup(taskCtrl.Finish)
+		taskDistributor := mockwk.NewMockTaskDistributor(taskCtrl)
+		taskDistributor.EXPECT().
+			DistributeTaskSendPasswordResetEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(sent).
+			Return(nil)
+
+		server := newTestServer(t, nil, taskDistributor)
+		server.config.PasswordResetEmailLimit = 2
+		server.config.PasswordResetIPLimit = 3
+		server.passwordResetLimiter = ratelimit.NewMemoryLimiter(time.Hour)
+		return server
+	}
+
+	contextWithPeer := func(ip string) context.Context {
+		addr := &net.TCPAddr{IP: net.ParseIP(ip), Port: 50000}
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+	}
+
+	requestReset := func(server *Server, ctx context.Context, email string) error {
+		req := &pb.RequestPasswordResetRequest{Email: email}
+		_, err := callUnary(ctx, server, pb.SimpleBank_RequestPasswordReset_FullMethodName, req, server.RequestPasswordReset)
+		return err
+	}
+
+	requireExhausted := func(t *testing.T, err error) {
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		require.Equal(t, codes.ResourceExhausted, st.Code())
+	}
+
+	t.Run("PerEmail", func(t *testing.T) {
+		server := newServer(t, 3)
+		email := util.RandomEmail()
+
+		// the address is limited across clients, whatever its case
+		require.NoError(t, requestReset(server, contextWithPeer("198.51.100.1"), email))
+		require.NoError(t, requestReset(server, contextWithPeer("198.51.100.2"), email))
+		requireExhausted(t, requestReset(server, contextWithPeer("198.51.100.3"), strings.ToUpper(email)))
+
+		require.NoError(t, requestReset(server, contextWithPeer("198.51.100.3"), util.RandomEmail()))
+	})
+
+	t.Run("PerIP", func(t *testing.T) {
+		server := newServer(t, 4)
+		ctx := contextWithPeer("198.51.100.1")
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, requestReset(server, ctx, util.RandomEmail()))
+		}
+		requireExhausted(t, requestReset(server, ctx, util.RandomEmail()))
+
+		require.NoError(t, requestReset(server, contextWithPeer("198.51.100.2"), util.RandomEmail()))
+	})
+}
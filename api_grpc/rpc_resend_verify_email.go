@@ -0,0 +1,59 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/hibiken/asynq"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/worker"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ResendVerifyEmail queues a new verification email for the authenticated user.
+// The number of emails per user is limited within VerifyEmailResendWindow, a limit of zero or less disables it.
+func (server *Server) ResendVerifyEmail(ctx context.Context, req *pb.ResendVerifyEmailRequest) (*pb.ResendVerifyEmailResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err)
+	}
+
+	if user.IsEmailVerified {
+		return nil, status.Errorf(codes.FailedPrecondition, "email address is already verified")
+	}
+
+	count, err := server.verifyEmailLimiter.Add(ctx, "verify_email:"+user.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check verify email limit: %s", err)
+	}
+	limit := int64(server.config.VerifyEmailResendLimit)
+	if limit > 0 && count > limit {
+		return nil, status.Errorf(codes.ResourceExhausted, "too many verification emails requested, try again later")
+	}
+
+	taskPayload := &worker.PayloadSendVerifyEmail{
+		Username: user.Username,
+	}
+
+	opts := []asynq.Option{
+		asynq.MaxRetry(10),
+		asynq.Queue(worker.QueueCtitical),
+	}
+
+	err = server.taskDistributor.DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to send verify email: %s", err)
+	}
+
+	return &pb.ResendVerifyEmailResponse{}, nil
+}
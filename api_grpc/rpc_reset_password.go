@@ -0,0 +1,70 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ResetPassword sets a new password with the secret code mailed by RequestPasswordReset,
+// lifts any login lockout and signs the user out of every session
+func (server *Server) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	violations := validResetPasswordRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	hashedPassword, err := util.HashPassword(req.GetNewPassword())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to hash password %s", err)
+	}
+
+	txResult, err := server.store.ResetPasswordTx(ctx, db.ResetPasswordTxParams{
+		ResetID:        req.GetResetId(),
+		SecretCode:     req.GetSecretCode(),
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "password reset not found, already used or expired")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to reset password: %s", err)
+	}
+
+	err = server.loginLimiter.Succeed(ctx, txResult.User.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reset login attempts: %s", err)
+	}
+
+	err = server.revocationChecker.Revoke(ctx, txResult.BlockedSessionIDs...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke sessions: %s", err)
+	}
+
+	response := &pb.ResetPasswordResponse{
+		IsReset: true,
+	}
+	return response, nil
+}
+
+func validResetPasswordRequest(req *pb.ResetPasswordRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetResetId()); err != nil {
+		violation = append(violation, fieldViolation("reset_id", err))
+	}
+	if err := val.ValidateSecretCode(req.GetSecretCode()); err != nil {
+		violation = append(violation, fieldViolation("secret_code", err))
+	}
+	if err := val.ValidatePassword(req.GetNewPassword()); err != nil {
+		violation = append(violation, fieldViolation("new_password", err))
+	}
+
+	return
+}
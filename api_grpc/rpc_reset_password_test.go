@@ -0,0 +1,138 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/token"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestResetPasswordAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	sessionID := uuid.New()
+	newPassword := util.RandomString(6)
+	resetID := util.RandomInt(1, 1000)
+	secretCode := util.RandomString(32)
+
+	testCases := []struct {
+		name          string
+		req           *pb.ResetPasswordRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, res *pb.ResetPasswordResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.ResetPasswordRequest{
+				ResetId:     resetID,
+				SecretCode:  secretCode,
+				NewPassword: newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.ResetPasswordTxParams) (db.ResetPasswordTxResult, error) {
+						require.Equal(t, resetID, arg.ResetID)
+						require.Equal(t, secretCode, arg.SecretCode)
+						require.NoError(t, util.CheckPassword(newPassword, arg.HashedPassword))
+						return db.ResetPasswordTxResult{
+							User:              user,
+							BlockedSessionIDs: []uuid.UUID{sessionID},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, server *Server, res *pb.ResetPasswordResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetIsReset())
+
+				// access tokens issued before the reset stop working right away
+				ctx := newContextWithSessionToken(t, server.tokenMaker, user.Username, user.Role, sessionID, time.Minute)
+				_, err = server.authorizaUser(ctx, allRoles)
+				require.ErrorIs(t, err, token.ErrRevokedToken)
+			},
+		},
+		{
+			name: "InvalidArguments",
+			req: &pb.ResetPasswordRequest{
+				ResetId:     0,
+				SecretCode:  "short",
+				NewPassword: "123",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, res *pb.ResetPasswordResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "ResetNotFound",
+			req: &pb.ResetPasswordRequest{
+				ResetId:     resetID,
+				SecretCode:  secretCode,
+				NewPassword: newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ResetPasswordTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, server *Server, res *pb.ResetPasswordResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+		{
+			name: "InternalError",
+			req: &pb.ResetPasswordRequest{
+				ResetId:     resetID,
+				SecretCode:  secretCode,
+				NewPassword: newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ResetPasswordTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, server *Server, res *pb.ResetPasswordResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Internal, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			res, err := callUnary(context.Background(), server, pb.SimpleBank_ResetPassword_FullMethodName, tc.req, server.ResetPassword)
+			tc.checkResponse(t, server, res, err)
+		})
+	}
+}
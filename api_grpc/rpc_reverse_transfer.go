@@ -0,0 +1,90 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReverseTransfer refunds a transfer, fully or partially, with a compensating transfer linked to it.
+// Only bankers and admins may call it, see methodPolicies.
+func (server *Server) ReverseTransfer(ctx context.Context, req *pb.ReverseTransferRequest) (*pb.ReverseTransferResponse, error) {
+	violations := validReverseTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	if req.Amount != nil {
+		transfer, err := server.store.GetTransfer(ctx, req.GetId())
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, status.Errorf(codes.NotFound, "transfer not found")
+			}
+			return nil, status.Errorf(codes.Internal, "failed to get transfer: %s", err)
+		}
+
+		// a partial refund is paid in the currency of the source account of the transfer
+		fromAccount, err := server.getAccount(ctx, transfer.FromAccountID.Int64)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := val.ValidateCurrencyAmount(req.GetAmount(), fromAccount.Currency); err != nil {
+			return nil, invalidArguementError([]*errdetails.BadRequest_FieldViolation{fieldViolation("amount", err)})
+		}
+	}
+
+	result, err := server.store.ReverseTransferTx(ctx, db.ReverseTransferTxParams{
+		TransferID: req.GetId(),
+		Amount:     req.GetAmount(),
+		Reason:     req.GetReason(),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "transfer not found")
+		}
+		if errors.Is(err, db.ErrReversalOfReversal) || errors.Is(err, db.ErrTransferFullyReversed) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) {
+			return nil, status.Errorf(codes.FailedPrecondition, "account [%d] has insufficient funds", result.ReversedTransfer.ToAccountID.Int64)
+		}
+		if errors.Is(err, db.ErrReversalExceedsTransfer) || errors.Is(err, db.ErrReversalTooSmall) {
+			return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to reverse transfer: %s", err)
+	}
+
+	response := &pb.ReverseTransferResponse{
+		Reversal:         convertTransfer(result.Transfer),
+		ReversedTransfer: convertTransfer(result.ReversedTransfer),
+		FromAccount:      convertAccount(result.FromAccount),
+		ToAccount:        convertAccount(result.ToAccount),
+		FromEntry:        convertEntry(result.FromEntry),
+		ToEntry:          convertEntry(result.ToEntry),
+	}
+	return response, nil
+}
+
+func validReverseTransferRequest(req *pb.ReverseTransferRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violation = append(violation, fieldViolation("id", err))
+	}
+	if req.Amount != nil {
+		if err := val.ValidateAmount(req.GetAmount()); err != nil {
+			violation = append(violation, fieldViolation("amount", err))
+		}
+	}
+	if err := val.ValidateReversalReason(req.GetReason()); err != nil {
+		violation = append(violation, fieldViolation("reason", err))
+	}
+
+	return
+}
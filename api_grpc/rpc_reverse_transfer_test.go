@@ -0,0 +1,195 @@
+package apigrpc
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReverseTransferAPI(t *testing.T) {
+	banker, _ := randomUser(t)
+	banker.Role = util.BankerRole
+
+	account1 := randomAccount(util.RandomOwner())
+	account2 := randomAccount(util.RandomOwner())
+	account1.ID, account2.ID = 1, 2
+	account1.Currency = util.USD
+	account2.Currency = util.USD
+
+	require.NoError(t, util.SetCurrencies([]util.Currency{
+		{Code: util.USD, MinorUnit: 2, Enabled: true},
+		{Code: "JPY", MinorUnit: 0, Enabled: true},
+	}))
+	defer util.SetCurrencies([]util.Currency{
+		{Code: util.USD, MinorUnit: 2, Enabled: true},
+		{Code: util.EUR, MinorUnit: 2, Enabled: true},
+		{Code: util.CAD, MinorUnit: 2, Enabled: true},
+	})
+
+	transfer := db.Transfer{
+		ID:            util.RandomInt(1, 1000),
+		FromAccountID: sql.NullInt64{Int64: account1.ID, Valid: true},
+		ToAccountID:   sql.NullInt64{Int64: account2.ID, Valid: true},
+		Amount:        5000,
+		CreditAmount:  5000,
+		ExchangeRate:  "1",
+	}
+
+	reversal := func(amount int64) db.ReverseTransferTxResult {
+		return db.ReverseTransferTxResult{
+			ReversedTransfer: transfer,
+			TransferTxResult: db.TransferTxResult{
+				Transfer: db.Transfer{
+					ID:            transfer.ID + 1,
+					FromAccountID: transfer.ToAccountID,
+					ToAccountID:   transfer.FromAccountID,
+					Amount:        amount,
+					CreditAmount:  amount,
+					ExchangeRate:  "1",
+					ReversalOf:    sql.NullInt64{Int64: transfer.ID, Valid: true},
+					Reason:        "duplicate charge",
+				},
+			},
+		}
+	}
+
+	amount := func(amount int64) *int64 {
+		return &amount
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ReverseTransferRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.ReverseTransferResponse, err error)
+	}{
+		{
+			name: "Full",
+			req:  &pb.ReverseTransferRequest{Id: transfer.ID, Reason: "duplicate charge"},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ReverseTransferTxParams{TransferID: transfer.ID, Reason: "duplicate charge"}
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(reversal(5000), nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, transfer.ID, res.GetReversal().GetReversalOf())
+				require.Equal(t, "duplicate charge", res.GetReversal().GetReason())
+				require.Equal(t, int64(5000), res.GetReversal().GetAmount())
+				require.Equal(t, account2.ID, res.GetReversal().GetFromAccountId())
+				require.Equal(t, transfer.ID, res.GetReversedTransfer().GetId())
+			},
+		},
+		{
+			name: "Partial",
+			req:  &pb.ReverseTransferRequest{Id: transfer.ID, Amount: amount(2000), Reason: "duplicate charge"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+
+				arg := db.ReverseTransferTxParams{TransferID: transfer.ID, Amount: 2000, Reason: "duplicate charge"}
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(reversal(2000), nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(2000), res.GetReversal().GetAmount())
+			},
+		},
+		{
+			name: "MissingReason",
+			req:  &pb.ReverseTransferRequest{Id: transfer.ID, Reason: "  "},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "AmountPrecision",
+			req:  &pb.ReverseTransferRequest{Id: transfer.ID, Amount: amount(150), Reason: "duplicate charge"},
+			buildStubs: func(store *mockdb.MockStore) {
+				jpyAccount := account1
+				jpyAccount.Currency = "JPY"
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(jpyAccount, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "AlreadyReversed",
+			req:  &pb.ReverseTransferRequest{Id: transfer.ID, Reason: "duplicate charge"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ReverseTransferTxResult{}, db.ErrTransferFullyReversed)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "ExceedsTransfer",
+			req:  &pb.ReverseTransferRequest{Id: transfer.ID, Amount: amount(6000), Reason: "duplicate charge"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ReverseTransferTxResult{}, db.ErrReversalExceedsTransfer)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "NotFound",
+			req:  &pb.ReverseTransferRequest{Id: transfer.ID, Reason: "duplicate charge"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ReverseTransferTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := newContextWithBearerToken(t, server.tokenMaker, banker.Username, banker.Role, time.Minute)
+			res, err := callUnary(ctx, server, pb.SimpleBank_ReverseTransfer_FullMethodName, tc.req, server.ReverseTransfer)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
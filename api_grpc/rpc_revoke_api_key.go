@@ -0,0 +1,52 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RevokeAPIKey revokes an API key of the authenticated user, it is rejected from the next request on
+func (server *Server) RevokeAPIKey(ctx context.Context, req *pb.RevokeAPIKeyRequest) (*pb.RevokeAPIKeyResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validRevokeAPIKeyRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	// keys of other users are reported as not found, so their ids can't be probed
+	apiKey, err := server.store.RevokeAPIKey(ctx, db.RevokeAPIKeyParams{
+		ID:       req.GetId(),
+		Username: authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "api key not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to revoke api key: %s", err)
+	}
+
+	response := &pb.RevokeAPIKeyResponse{
+		ApiKey: convertAPIKey(apiKey),
+	}
+	return response, nil
+}
+
+func validRevokeAPIKeyRequest(req *pb.RevokeAPIKeyRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violation = append(violation, fieldViolation("id", err))
+	}
+
+	return
+}
@@ -0,0 +1,89 @@
+package apigrpc
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRevokeAPIKeyAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		req           *pb.RevokeAPIKeyRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.RevokeAPIKeyResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.RevokeAPIKeyRequest{Id: 7},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.RevokeAPIKeyParams{ID: 7, Username: user.Username}
+				store.EXPECT().
+					RevokeAPIKey(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.ApiKey{ID: 7, Username: user.Username, IsRevoked: true}, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.RevokeAPIKeyResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(7), res.GetApiKey().GetId())
+				require.True(t, res.GetApiKey().GetIsRevoked())
+			},
+		},
+		{
+			name: "NotFound",
+			req:  &pb.RevokeAPIKeyRequest{Id: 7},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RevokeAPIKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApiKey{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, res *pb.RevokeAPIKeyResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+		{
+			name: "InvalidID",
+			req:  &pb.RevokeAPIKeyRequest{Id: 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RevokeAPIKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RevokeAPIKeyResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := newContextWithBearerToken(t, server.tokenMaker, user.Username, user.Role, time.Minute)
+			res, err := callUnary(ctx, server, pb.SimpleBank_RevokeAPIKey_FullMethodName, tc.req, server.RevokeAPIKey)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/sangketkit01/simple-bank/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validRevokeSessionRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	sessionID := uuid.MustParse(req.GetSessionId())
+	if sessionID == authPayload.SessionID {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot revoke the current session, use logout instead")
+	}
+
+	session, err := server.store.GetSession(ctx, sessionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "session not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get session: %s", err)
+	}
+
+	if session.Username != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "session doesn't belong to the authenticated user")
+	}
+
+	// a rotated session was replaced by the next one of its family, which is the one to revoke
+	if session.IsRotated {
+		return nil, status.Errorf(codes.NotFound, "session not found")
+	}
+
+	// block the whole family, so access tokens issued before a rotation stop working too
+	sessionIDs, err := server.store.BlockSessionFamily(ctx, session.FamilyID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to block session: %s", err)
+	}
+
+	err = server.revocationChecker.Revoke(ctx, sessionIDs...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke session: %s", err)
+	}
+
+	response := &pb.RevokeSessionResponse{
+		SessionIds: make([]string, 0, len(sessionIDs)),
+	}
+	for _, id := range sessionIDs {
+		response.SessionIds = append(response.SessionIds, id.String())
+	}
+	return response, nil
+}
+
+func validRevokeSessionRequest(req *pb.RevokeSessionRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if _, err := uuid.Parse(req.GetSessionId()); err != nil {
+		violation = append(violation, fieldViolation("session_id", err))
+	}
+
+	return
+}
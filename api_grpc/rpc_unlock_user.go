@@ -0,0 +1,48 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnlockUser lifts the login lockout of a user and forgets the failed logins of the username.
+// Only admins may call it, see methodPolicies.
+func (server *Server) UnlockUser(ctx context.Context, req *pb.UnlockUserRequest) (*pb.UnlockUserResponse, error) {
+	violations := validUnlockUserRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	user, err := server.store.ResetFailedLogins(ctx, req.GetUsername())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to unlock user: %s", err)
+	}
+
+	err = server.loginLimiter.Succeed(ctx, user.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reset login attempts: %s", err)
+	}
+
+	response := &pb.UnlockUserResponse{
+		User: convertUser(user),
+	}
+	return response, nil
+}
+
+func validUnlockUserRequest(req *pb.UnlockUserRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateUsername(req.GetUsername()); err != nil {
+		violation = append(violation, fieldViolation("username", err))
+	}
+
+	return
+}
@@ -0,0 +1,130 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpdateScheduledTransfer changes the amount or the schedule of a scheduled transfer, or pauses and resumes it.
+// Occurrences missed while the schedule wasn't active are skipped when it is resumed.
+func (server *Server) UpdateScheduledTransfer(ctx context.Context, req *pb.UpdateScheduledTransferRequest) (*pb.UpdateScheduledTransferResponse, error) {
+	authPayload, err := authPayloadFromContext(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validUpdateScheduledTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	scheduledTransfer, err := server.getOwnedScheduledTransfer(ctx, authPayload.Username, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if scheduledTransfer.Status == util.ScheduleCompleted || scheduledTransfer.Status == util.ScheduleCancelled {
+		return nil, status.Errorf(codes.FailedPrecondition, "scheduled transfer is %s", scheduledTransfer.Status)
+	}
+
+	arg := db.UpdateScheduledTransferParams{
+		ID: scheduledTransfer.ID,
+	}
+
+	if req.Amount != nil {
+		if err := val.ValidateCurrencyAmount(req.GetAmount(), scheduledTransfer.Currency); err != nil {
+			return nil, invalidArguementError([]*errdetails.BadRequest_FieldViolation{fieldViolation("amount", err)})
+		}
+		arg.Amount = sql.NullInt64{Int64: req.GetAmount(), Valid: true}
+	}
+
+	now := time.Now()
+	rule := scheduledTransfer.Schedule
+	startsAt := scheduledTransfer.StartsAt
+	occurrence := int(scheduledTransfer.NextOccurrence)
+	reschedule := false
+
+	// a new schedule starts over from the next run
+	if req.Schedule != nil {
+		rule = req.GetSchedule()
+		startsAt = scheduledTransfer.NextRunAt
+		if startsAt.Before(now) {
+			startsAt = now.Truncate(time.Second)
+		}
+		occurrence = 0
+		reschedule = true
+
+		arg.Schedule = sql.NullString{String: rule, Valid: true}
+		arg.StartsAt = sql.NullTime{Time: startsAt, Valid: true}
+	}
+
+	newStatus := scheduledTransfer.Status
+	if req.Status != nil && req.GetStatus() != scheduledTransfer.Status {
+		newStatus = req.GetStatus()
+		if newStatus == util.ScheduleActive {
+			arg.FailureCount = sql.NullInt32{Int32: 0, Valid: true}
+			reschedule = true
+		}
+	}
+
+	if reschedule {
+		schedule, err := util.ParseSchedule(rule)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to parse schedule: %s", err)
+		}
+
+		nextRunAt, n, ok := schedule.NextOccurrence(startsAt, occurrence, now.Truncate(time.Second))
+		if ok {
+			arg.NextRunAt = sql.NullTime{Time: nextRunAt, Valid: true}
+			arg.NextOccurrence = sql.NullInt32{Int32: int32(n), Valid: true}
+		} else {
+			newStatus = util.ScheduleCompleted
+		}
+	}
+
+	if newStatus != scheduledTransfer.Status {
+		arg.Status = sql.NullString{String: newStatus, Valid: true}
+	}
+
+	scheduledTransfer, err = server.store.UpdateScheduledTransfer(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update scheduled transfer: %s", err)
+	}
+
+	response := &pb.UpdateScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduledTransfer),
+	}
+	return response, nil
+}
+
+func validUpdateScheduledTransferRequest(req *pb.UpdateScheduledTransferRequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violation = append(violation, fieldViolation("id", err))
+	}
+	if req.Amount != nil {
+		if err := val.ValidateAmount(req.GetAmount()); err != nil {
+			violation = append(violation, fieldViolation("amount", err))
+		}
+	}
+	if req.Schedule != nil {
+		if err := val.ValidateSchedule(req.GetSchedule()); err != nil {
+			violation = append(violation, fieldViolation("schedule", err))
+		}
+	}
+	if req.Status != nil {
+		if err := val.ValidateScheduledTransferStatus(req.GetStatus()); err != nil {
+			violation = append(violation, fieldViolation("status", err))
+		}
+	}
+
+	return
+}
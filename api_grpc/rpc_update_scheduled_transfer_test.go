@@ -0,0 +1,219 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/token"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func randomScheduledTransfer(owner string, status string) db.ScheduledTransfer {
+	startsAt := time.Now().Add(-10 * 24 * time.Hour).Truncate(time.Second)
+	return db.ScheduledTransfer{
+		ID:             util.RandomInt(1, 1000),
+		Owner:          owner,
+		FromAccountID:  1,
+		ToAccountID:    2,
+		Amount:         1000,
+		Currency:       util.USD,
+		Schedule:       "FREQ=DAILY",
+		StartsAt:       startsAt,
+		NextRunAt:      startsAt.AddDate(0, 0, 2),
+		NextOccurrence: 2,
+		Status:         status,
+		FailureCount:   3,
+	}
+}
+
+func TestUpdateScheduledTransferAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		status        string
+		req           func(id int64) *pb.UpdateScheduledTransferRequest
+		buildStubs    func(store *mockdb.MockStore, scheduledTransfer db.ScheduledTransfer)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.UpdateScheduledTransferResponse, err error)
+	}{
+		{
+			name:   "Pause",
+			status: util.ScheduleActive,
+			req: func(id int64) *pb.UpdateScheduledTransferRequest {
+				status := util.SchedulePaused
+				return &pb.UpdateScheduledTransferRequest{Id: id, Status: &status}
+			},
+			buildStubs: func(store *mockdb.MockStore, scheduledTransfer db.ScheduledTransfer) {
+				arg := db.UpdateScheduledTransferParams{
+					ID:     scheduledTransfer.ID,
+					Status: sql.NullString{String: util.SchedulePaused, Valid: true},
+				}
+				scheduledTransfer.Status = util.SchedulePaused
+				store.EXPECT().UpdateScheduledTransfer(gomock.Any(), gomock.Eq(arg)).Times(1).Return(scheduledTransfer, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateScheduledTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, util.SchedulePaused, res.GetScheduledTransfer().GetStatus())
+			},
+		},
+		{
+			name:   "ResumeSkipsMissedRuns",
+			status: util.ScheduleSuspended,
+			req: func(id int64) *pb.UpdateScheduledTransferRequest {
+				status := util.ScheduleActive
+				return &pb.UpdateScheduledTransferRequest{Id: id, Status: &status}
+			},
+			buildStubs: func(store *mockdb.MockStore, scheduledTransfer db.ScheduledTransfer) {
+				store.EXPECT().
+					UpdateScheduledTransfer(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateScheduledTransferParams) (db.ScheduledTransfer, error) {
+						require.Equal(t, sql.NullString{String: util.ScheduleActive, Valid: true}, arg.Status)
+						require.Equal(t, sql.NullInt32{Int32: 0, Valid: true}, arg.FailureCount)
+						require.False(t, arg.Schedule.Valid)
+
+						// the schedule started 10 days ago, the run of today is the next one
+						require.True(t, arg.NextRunAt.Valid)
+						require.False(t, arg.NextRunAt.Time.Before(time.Now().Truncate(time.Second)))
+						require.WithinDuration(t, time.Now(), arg.NextRunAt.Time, 24*time.Hour)
+						require.Contains(t, []int32{10, 11}, arg.NextOccurrence.Int32)
+
+						scheduledTransfer.Status = util.ScheduleActive
+						scheduledTransfer.NextRunAt = arg.NextRunAt.Time
+						return scheduledTransfer, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateScheduledTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, util.ScheduleActive, res.GetScheduledTransfer().GetStatus())
+			},
+		},
+		{
+			name:   "NewSchedule",
+			status: util.ScheduleActive,
+			req: func(id int64) *pb.UpdateScheduledTransferRequest {
+				schedule := "FREQ=WEEKLY;COUNT=4"
+				amount := int64(2000)
+				return &pb.UpdateScheduledTransferRequest{Id: id, Schedule: &schedule, Amount: &amount}
+			},
+			buildStubs: func(store *mockdb.MockStore, scheduledTransfer db.ScheduledTransfer) {
+				store.EXPECT().
+					UpdateScheduledTransfer(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateScheduledTransferParams) (db.ScheduledTransfer, error) {
+						require.Equal(t, sql.NullInt64{Int64: 2000, Valid: true}, arg.Amount)
+						require.Equal(t, sql.NullString{String: "FREQ=WEEKLY;COUNT=4", Valid: true}, arg.Schedule)
+						require.Equal(t, sql.NullInt32{Int32: 0, Valid: true}, arg.NextOccurrence)
+						require.True(t, arg.StartsAt.Time.Equal(arg.NextRunAt.Time))
+						require.False(t, arg.Status.Valid)
+						return scheduledTransfer, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateScheduledTransferResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "InvalidStatus",
+			status: util.ScheduleActive,
+			req: func(id int64) *pb.UpdateScheduledTransferRequest {
+				status := util.ScheduleSuspended
+				return &pb.UpdateScheduledTransferRequest{Id: id, Status: &status}
+			},
+			buildStubs: func(store *mockdb.MockStore, scheduledTransfer db.ScheduledTransfer) {
+				store.EXPECT().UpdateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateScheduledTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name:   "Completed",
+			status: util.ScheduleCompleted,
+			req: func(id int64) *pb.UpdateScheduledTransferRequest {
+				status := util.ScheduleActive
+				return &pb.UpdateScheduledTransferRequest{Id: id, Status: &status}
+			},
+			buildStubs: func(store *mockdb.MockStore, scheduledTransfer db.ScheduledTransfer) {
+				store.EXPECT().UpdateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateScheduledTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name:   "OtherOwner",
+			status: util.ScheduleActive,
+			req: func(id int64) *pb.UpdateScheduledTransferRequest {
+				status := util.SchedulePaused
+				return &pb.UpdateScheduledTransferRequest{Id: id, Status: &status}
+			},
+			buildStubs: func(store *mockdb.MockStore, scheduledTransfer db.ScheduledTransfer) {
+				store.EXPECT().UpdateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, other.Username, other.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateScheduledTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			scheduledTransfer := randomScheduledTransfer(user.Username, tc.status)
+			store.EXPECT().
+				GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduledTransfer.ID)).
+				AnyTimes().
+				Return(scheduledTransfer, nil)
+			tc.buildStubs(store, scheduledTransfer)
+
+			server := newTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := callUnary(ctx, server, pb.SimpleBank_UpdateScheduledTransfer_FullMethodName, tc.req(scheduledTransfer.ID), server.UpdateScheduledTransfer)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
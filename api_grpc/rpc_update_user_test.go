@@ -7,11 +7,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	mockdb "github.com/sangketkit01/simple-bank/db/mock"
 	db "github.com/sangketkit01/simple-bank/db/sqlc"
 	"github.com/sangketkit01/simple-bank/pb"
 	"github.com/sangketkit01/simple-bank/token"
 	"github.com/sangketkit01/simple-bank/util"
+	"github.com/sangketkit01/simple-bank/worker"
+	mockwk "github.com/sangketkit01/simple-bank/worker/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"google.golang.org/grpc/codes"
@@ -25,6 +28,8 @@ func TestUpdateUserAPI(t *testing.T) {
 	newName := util.RandomOwner()
 	newEmail := util.RandomEmail()
 	invalidEmail := "invalidEmail"
+	bankerRole := util.BankerRole
+	invalidRole := "superuser"
 
 	testCases := []struct {
 		name          string
@@ -63,13 +68,13 @@ func TestUpdateUserAPI(t *testing.T) {
 				}
 
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Eq(arg)).
+					UpdateUserTx(gomock.Any(), gomock.Eq(db.UpdateUserTxParams{UpdateUserParams: arg})).
 					Times(1).
-					Return(updateUser, nil)
+					Return(db.UpdateUserTxResult{User: updateUser}, nil)
 
 			},
 			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
-				accessToken, _, err := tokenMaker.CreateToken(user.Username, time.Minute)
+				accessToken, _, err := tokenMaker.CreateToken(user.Username, user.Role, uuid.New(), time.Minute, token.TokenTypeAccessToken)
 				bearerToken := fmt.Sprintf("%s %s",authorizationBearer, accessToken)
 				require.NoError(t, err)
 				md := metadata.MD{
@@ -98,13 +103,13 @@ func TestUpdateUserAPI(t *testing.T) {
 			buildStubs: func(store *mockdb.MockStore) {
 
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Any()).
+					UpdateUserTx(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(db.User{}, sql.ErrNoRows)
+					Return(db.UpdateUserTxResult{}, sql.ErrNoRows)
 
 			},
 			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
-				accessToken, _, err := tokenMaker.CreateToken(user.Username, time.Minute)
+				accessToken, _, err := tokenMaker.CreateToken(user.Username, user.Role, uuid.New(), time.Minute, token.TokenTypeAccessToken)
 				bearerToken := fmt.Sprintf("%s %s",authorizationBearer, accessToken)
 				require.NoError(t, err)
 				md := metadata.MD{
@@ -131,12 +136,12 @@ func TestUpdateUserAPI(t *testing.T) {
 			buildStubs: func(store *mockdb.MockStore) {
 
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Any()).
+					UpdateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 
 			},
 			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
-				accessToken, _, err := tokenMaker.CreateToken(user.Username, -time.Minute)
+				accessToken, _, err := tokenMaker.CreateToken(user.Username, user.Role, uuid.New(), -time.Minute, token.TokenTypeAccessToken)
 				bearerToken := fmt.Sprintf("%s %s",authorizationBearer, accessToken)
 				require.NoError(t, err)
 				md := metadata.MD{
@@ -163,12 +168,12 @@ func TestUpdateUserAPI(t *testing.T) {
 			buildStubs: func(store *mockdb.MockStore) {
 
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Any()).
+					UpdateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 
 			},
 			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
-				accessToken, _, err := tokenMaker.CreateToken(user.Username, -time.Minute)
+				accessToken, _, err := tokenMaker.CreateToken(user.Username, user.Role, uuid.New(), -time.Minute, token.TokenTypeAccessToken)
 				bearerToken := fmt.Sprintf("%s %s",authorizationBearer, accessToken)
 				require.NoError(t, err)
 				md := metadata.MD{
@@ -195,12 +200,12 @@ func TestUpdateUserAPI(t *testing.T) {
 			buildStubs: func(store *mockdb.MockStore) {
 
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Any()).
+					UpdateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 
 			},
 			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
-				accessToken, _, err := tokenMaker.CreateToken(user.Username, -time.Minute)
+				accessToken, _, err := tokenMaker.CreateToken(user.Username, user.Role, uuid.New(), -time.Minute, token.TokenTypeAccessToken)
 				bearerToken := fmt.Sprintf("%s %s",authorizationBearer, accessToken)
 				require.NoError(t, err)
 				md := metadata.MD{
@@ -217,6 +222,100 @@ func TestUpdateUserAPI(t *testing.T) {
 				require.Equal(t, codes.Unauthenticated, st.Code())
 			},
 		},
+		{
+			name: "AdminChangesRole",
+			req: &pb.UpdateUserRequest{
+				Username: user.Username,
+				Role:     &bankerRole,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateUserParams{
+					Username: user.Username,
+					Role: sql.NullString{
+						String: bankerRole,
+						Valid:  true,
+					},
+				}
+				updatedUser := user
+				updatedUser.Role = bankerRole
+
+				store.EXPECT().
+					UpdateUserTx(gomock.Any(), gomock.Eq(db.UpdateUserTxParams{UpdateUserParams: arg, BlockSessions: true})).
+					Times(1).
+					Return(db.UpdateUserTxResult{User: updatedUser, BlockedSessionIDs: []uuid.UUID{uuid.New()}}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "admin_user", util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, user.Username, res.GetUser().GetUsername())
+				require.Equal(t, bankerRole, res.GetUser().GetRole())
+			},
+		},
+		{
+			name: "BankerUpdatesOtherUser",
+			req: &pb.UpdateUserRequest{
+				Username: user.Username,
+				FullName: &newName,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "banker_user", util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "DepositorChangesOwnRole",
+			req: &pb.UpdateUserRequest{
+				Username: user.Username,
+				Role:     &bankerRole,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "InvalidRole",
+			req: &pb.UpdateUserRequest{
+				Username: user.Username,
+				Role:     &invalidRole,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "admin_user", util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.UpdateUserResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
 	}
 
 	for i := range testCases {
@@ -232,8 +331,82 @@ func TestUpdateUserAPI(t *testing.T) {
 			server := newTestServer(t, store, nil)
 
 			ctx := tc.buildContext(t, server.tokenMaker)
-			res, err := server.UpdateUser(ctx, tc.req)
+			res, err := callUnary(ctx, server, pb.SimpleBank_UpdateUser_FullMethodName, tc.req, server.UpdateUser)
 			tc.checkResponse(t, res, err)
 		})
 	}
 }
+
+func TestUpdateUserPasswordRevokesSessions(t *testing.T) {
+	user, _ := randomUser(t)
+	newPassword := util.RandomString(6)
+	sessionID := uuid.New()
+
+	storeCtrl := gomock.NewController(t)
+	defer storeCtrl.Finish()
+	store := mockdb.NewMockStore(storeCtrl)
+
+	server := newTestServer(t, store, nil)
+	ctx := newContextWithSessionToken(t, server.tokenMaker, user.Username, user.Role, sessionID, time.Minute)
+
+	store.EXPECT().
+		UpdateUserTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.UpdateUserTxParams) (db.UpdateUserTxResult, error) {
+			require.True(t, arg.BlockSessions)
+			require.True(t, arg.PasswordChangedAt.Valid)
+			require.NoError(t, util.CheckPassword(newPassword, arg.HashedPassword.String))
+			return db.UpdateUserTxResult{User: user, BlockedSessionIDs: []uuid.UUID{sessionID}}, nil
+		})
+
+	_, err := callUnary(ctx, server, pb.SimpleBank_UpdateUser_FullMethodName, &pb.UpdateUserRequest{
+		Username: user.Username,
+		Password: &newPassword,
+	}, server.UpdateUser)
+	require.NoError(t, err)
+
+	// the token used to change the password is signed out together with every other session
+	_, err = server.authorizaUser(ctx, allRoles)
+	require.ErrorIs(t, err, token.ErrRevokedToken)
+}
+
+func TestUpdateUserEmailSendsVerifyEmail(t *testing.T) {
+	user, _ := randomUser(t)
+	user.IsEmailVerified = true
+	newEmail := util.RandomEmail()
+
+	storeCtrl := gomock.NewController(t)
+	defer storeCtrl.Finish()
+	store := mockdb.NewMockStore(storeCtrl)
+
+	taskCtrl := gomock.NewController(t)
+	defer taskCtrl.Finish()
+	taskDistributor := mockwk.NewMockTaskDistributor(taskCtrl)
+
+	// the store resets the verification of a changed email and reports the change
+	updatedUser := user
+	updatedUser.Email = newEmail
+	updatedUser.IsEmailVerified = false
+
+	store.EXPECT().
+		UpdateUserTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.UpdateUserTxParams) (db.UpdateUserTxResult, error) {
+			require.Equal(t, newEmail, arg.Email.String)
+			return db.UpdateUserTxResult{User: updatedUser, EmailChanged: true}, nil
+		})
+	taskDistributor.EXPECT().
+		DistributeTaskSendVerifyEmail(gomock.Any(), gomock.Eq(&worker.PayloadSendVerifyEmail{Username: user.Username}), gomock.Any()).
+		Times(1).
+		Return(nil)
+
+	server := newTestServer(t, store, taskDistributor)
+	ctx := newContextWithBearerToken(t, server.tokenMaker, user.Username, user.Role, time.Minute)
+
+	res, err := callUnary(ctx, server, pb.SimpleBank_UpdateUser_FullMethodName, &pb.UpdateUserRequest{
+		Username: user.Username,
+		Email:    &newEmail,
+	}, server.UpdateUser)
+	require.NoError(t, err)
+	require.Equal(t, newEmail, res.GetUser().GetEmail())
+}
@@ -0,0 +1,183 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/ratelimit"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/sangketkit01/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxMFAAttempts is the number of wrong codes after which an mfa token stops working
+const maxMFAAttempts = 5
+
+func (server *Server) VerifyMFA(ctx context.Context, req *pb.VerifyMFARequest) (*pb.VerifyMFAResponse, error) {
+	violations := validVerifyMFARequest(req)
+	if violations != nil {
+		return nil, invalidArguementError(violations)
+	}
+
+	challengeID := uuid.MustParse(req.GetMfaToken())
+	challenge, err := server.store.GetMFAChallenge(ctx, challengeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, unauthenticatedError(fmt.Errorf("invalid mfa token"))
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get mfa challenge: %s", err)
+	}
+
+	if challenge.IsUsed || challenge.Attempts >= maxMFAAttempts || time.Now().After(challenge.ExpiredAt) {
+		return nil, unauthenticatedError(fmt.Errorf("mfa token is no longer valid"))
+	}
+
+	clientIP := server.extractMetadata(ctx).ClientIP
+	err = server.loginLimiter.Check(ctx, challenge.Username, clientIP)
+	if err != nil {
+		if errors.Is(err, ratelimit.ErrTooManyAttempts) {
+			return nil, status.Errorf(codes.ResourceExhausted, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot check login attempts: %s", err)
+	}
+
+	user, err := server.store.GetUser(ctx, challenge.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get user: %s", err)
+	}
+
+	// wrong codes count towards the lockout, a user locked since the password was checked cannot finish
+	if time.Now().Before(user.LockedUntil) {
+		return nil, unauthenticatedError(fmt.Errorf("mfa token is no longer valid"))
+	}
+
+	arg, ok, err := server.checkSecondFactor(ctx, challenge, req)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to verify second factor: %s", err)
+	}
+
+	if ok {
+		// the challenge and the second factor are consumed together, so a token cannot be exchanged twice concurrently
+		_, err = server.store.VerifyMFATx(ctx, arg)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, unauthenticatedError(fmt.Errorf("mfa token is no longer valid"))
+			}
+			if !errors.Is(err, db.ErrSecondFactorRejected) {
+				return nil, status.Errorf(codes.Internal, "failed to use mfa challenge: %s", err)
+			}
+			ok = false
+		}
+	}
+
+	if !ok {
+		_, err = server.store.IncrementMFAChallengeAttempts(ctx, challenge.ID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to update mfa challenge: %s", err)
+		}
+
+		err = server.recordFailedLogin(ctx, user.Username, clientIP, true)
+		if err != nil {
+			return nil, err
+		}
+		return nil, unauthenticatedError(fmt.Errorf("incorrect code"))
+	}
+
+	user, err = server.succeedLogin(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	userSession, err := server.createUserSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.VerifyMFAResponse{
+		User:                convertUser(user),
+		SessionId:           userSession.session.ID.String(),
+		AccessToken:         userSession.accessToken,
+		AccessTokenExpired:  timestamppb.New(userSession.accessPayload.ExpiredAt),
+		RefreshToken:        userSession.refreshToken,
+		RefreshTokenExpired: timestamppb.New(userSession.refreshPayload.ExpiredAt),
+	}
+	return response, nil
+}
+
+// isMFAEnabled reports whether the user has confirmed a TOTP secret
+func (server *Server) isMFAEnabled(ctx context.Context, username string) (bool, error) {
+	userTOTP, err := server.store.GetUserTOTP(ctx, username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return userTOTP.IsConfirmed, nil
+}
+
+// checkSecondFactor checks the TOTP code or the recovery code of the request and returns what
+// VerifyMFATx has to consume for it. A TOTP code is accepted once, replaying it inside its time window fails.
+func (server *Server) checkSecondFactor(ctx context.Context, challenge db.MfaChallenge, req *pb.VerifyMFARequest) (db.VerifyMFATxParams, bool, error) {
+	arg := db.VerifyMFATxParams{
+		ChallengeID: challenge.ID,
+		MaxAttempts: maxMFAAttempts,
+		Username:    challenge.Username,
+	}
+
+	if req.GetRecoveryCode() != "" {
+		arg.HashedRecoveryCode = util.HashRecoveryCode(req.GetRecoveryCode())
+		return arg, true, nil
+	}
+
+	userTOTP, err := server.store.GetUserTOTP(ctx, challenge.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return arg, false, nil
+		}
+		return arg, false, err
+	}
+
+	if !userTOTP.IsConfirmed {
+		return arg, false, nil
+	}
+
+	step, ok := util.ValidateTOTP(userTOTP.Secret, req.GetCode(), time.Now())
+	if !ok {
+		return arg, false, nil
+	}
+
+	arg.Step = step
+	return arg, true, nil
+}
+
+func validVerifyMFARequest(req *pb.VerifyMFARequest) (violation []*errdetails.BadRequest_FieldViolation) {
+	if _, err := uuid.Parse(req.GetMfaToken()); err != nil {
+		violation = append(violation, fieldViolation("mfa_token", err))
+	}
+
+	switch {
+	case req.GetCode() != "" && req.GetRecoveryCode() != "":
+		violation = append(violation, fieldViolation("code", fmt.Errorf("cannot be combined with recovery_code")))
+	case req.GetRecoveryCode() != "":
+		if err := val.ValidateRecoveryCode(req.GetRecoveryCode()); err != nil {
+			violation = append(violation, fieldViolation("recovery_code", err))
+		}
+	default:
+		if err := val.ValidateTOTPCode(req.GetCode()); err != nil {
+			violation = append(violation, fieldViolation("code", err))
+		}
+	}
+
+	return
+}
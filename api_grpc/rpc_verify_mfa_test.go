@@ -0,0 +1,629 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	mockdb "github.com/sangketkit01/simple-bank/db/mock"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/ratelimit"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func randomUserTOTP(t *testing.T, username string) db.UserTotp {
+	secret, err := util.GenerateTOTPSecret()
+	require.NoError(t, err)
+
+	return db.UserTotp{
+		Username:    username,
+		Secret:      secret,
+		IsConfirmed: true,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func randomMFAChallenge(username string) db.MfaChallenge {
+	return db.MfaChallenge{
+		ID:        uuid.New(),
+		Username:  username,
+		ExpiredAt: time.Now().Add(time.Minute),
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestLoginUserMFA(t *testing.T) {
+	user, password := randomUser(t)
+	userTOTP := randomUserTOTP(t, user.Username)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.LoginUserResponse, err error)
+	}{
+		{
+			name: "NotEnrolled",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.UserTotp{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateSessionParams) (db.Session, error) {
+						return db.Session{ID: arg.ID, Username: arg.Username, ExpiredAt: arg.ExpiredAt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.False(t, res.GetMfaRequired())
+				require.NotEmpty(t, res.GetAccessToken())
+				require.NotEmpty(t, res.GetRefreshToken())
+			},
+		},
+		{
+			name: "PendingEnrollment",
+			buildStubs: func(store *mockdb.MockStore) {
+				pending := userTOTP
+				pending.IsConfirmed = false
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(pending, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateSessionParams) (db.Session, error) {
+						return db.Session{ID: arg.ID, Username: arg.Username, ExpiredAt: arg.ExpiredAt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.False(t, res.GetMfaRequired())
+				require.NotEmpty(t, res.GetAccessToken())
+			},
+		},
+		{
+			name: "MFARequiredKeepsFailedLogins",
+			buildStubs: func(store *mockdb.MockStore) {
+				failedUser := user
+				failedUser.FailedLoginCount = 2
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(failedUser, nil)
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(userTOTP, nil)
+				store.EXPECT().
+					ResetFailedLogins(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CreateMFAChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateMFAChallengeParams) (db.MfaChallenge, error) {
+						return db.MfaChallenge{ID: arg.ID, Username: arg.Username, ExpiredAt: arg.ExpiredAt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetMfaRequired())
+			},
+		},
+		{
+			name: "MFARequired",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(userTOTP, nil)
+				store.EXPECT().
+					CreateMFAChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateMFAChallengeParams) (db.MfaChallenge, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.WithinDuration(t, time.Now().Add(time.Minute), arg.ExpiredAt, time.Second)
+						return db.MfaChallenge{ID: arg.ID, Username: arg.Username, ExpiredAt: arg.ExpiredAt}, nil
+					})
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetMfaRequired())
+				require.NotEmpty(t, res.GetMfaToken())
+				require.NotNil(t, res.GetMfaTokenExpired())
+				require.Empty(t, res.GetAccessToken())
+				require.Empty(t, res.GetRefreshToken())
+				require.Nil(t, res.GetUser())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			res, err := server.LoginUser(context.Background(), &pb.LoginUserRequest{
+				Username: user.Username,
+				Password: password,
+			})
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestVerifyMFAAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	userTOTP := randomUserTOTP(t, user.Username)
+	challenge := randomMFAChallenge(user.Username)
+
+	now := time.Now()
+	code, err := util.TOTPCode(userTOTP.Secret, now)
+	require.NoError(t, err)
+	recoveryCodes, err := util.GenerateRecoveryCodes(1)
+	require.NoError(t, err)
+
+	// wrongCode is a well formed code that matches none of the accepted steps
+	wrongCode := ""
+	for i := 0; wrongCode == ""; i++ {
+		candidate := fmt.Sprintf("%06d", i)
+		if _, ok := util.ValidateTOTP(userTOTP.Secret, candidate, now); !ok {
+			wrongCode = candidate
+		}
+	}
+
+	expectUser := func(store *mockdb.MockStore) {
+		store.EXPECT().
+			GetUser(gomock.Any(), gomock.Eq(user.Username)).
+			Times(1).
+			Return(user, nil)
+	}
+
+	// a wrong code uses up an attempt of the challenge and counts as a failed login of the user
+	expectFailure := func(store *mockdb.MockStore) {
+		store.EXPECT().
+			IncrementMFAChallengeAttempts(gomock.Any(), gomock.Eq(challenge.ID)).
+			Times(1)
+		store.EXPECT().
+			RecordFailedLoginTx(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(ctx context.Context, arg db.RecordFailedLoginTxParams) (db.User, error) {
+				require.Equal(t, user.Username, arg.Username)
+				return user, nil
+			})
+		store.EXPECT().
+			CreateSession(gomock.Any(), gomock.Any()).
+			Times(0)
+	}
+
+	expectSession := func(store *mockdb.MockStore) {
+		store.EXPECT().
+			ResetFailedLogins(gomock.Any(), gomock.Any()).
+			Times(0)
+		store.EXPECT().
+			CreateSession(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(ctx context.Context, arg db.CreateSessionParams) (db.Session, error) {
+				require.Equal(t, arg.ID, arg.FamilyID)
+				return db.Session{ID: arg.ID, Username: arg.Username, ExpiredAt: arg.ExpiredAt}, nil
+			})
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.VerifyMFARequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.VerifyMFAResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.VerifyMFARequest{MfaToken: challenge.ID.String(), Code: code},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMFAChallenge(gomock.Any(), gomock.Eq(challenge.ID)).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(userTOTP, nil)
+				store.EXPECT().
+					VerifyMFATx(gomock.Any(), gomock.Eq(db.VerifyMFATxParams{
+						ChallengeID: challenge.ID,
+						MaxAttempts: maxMFAAttempts,
+						Username:    user.Username,
+						Step:        util.TOTPStep(now),
+					})).
+					Times(1).
+					Return(challenge, nil)
+				expectUser(store)
+				expectSession(store)
+			},
+			checkResponse: func(t *testing.T, res *pb.VerifyMFAResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, user.Username, res.GetUser().GetUsername())
+				require.NotEmpty(t, res.GetSessionId())
+				require.NotEmpty(t, res.GetAccessToken())
+				require.NotEmpty(t, res.GetRefreshToken())
+			},
+		},
+		{
+			name: "RecoveryCode",
+			req:  &pb.VerifyMFARequest{MfaToken: challenge.ID.String(), RecoveryCode: recoveryCodes[0]},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMFAChallenge(gomock.Any(), gomock.Eq(challenge.ID)).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					VerifyMFATx(gomock.Any(), gomock.Eq(db.VerifyMFATxParams{
+						ChallengeID:        challenge.ID,
+						MaxAttempts:        maxMFAAttempts,
+						Username:           user.Username,
+						HashedRecoveryCode: util.HashRecoveryCode(recoveryCodes[0]),
+					})).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Any()).
+					Times(0)
+				expectUser(store)
+				expectSession(store)
+			},
+			checkResponse: func(t *testing.T, res *pb.VerifyMFAResponse, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, res.GetAccessToken())
+			},
+		},
+		{
+			name: "UsedRecoveryCode",
+			req:  &pb.VerifyMFARequest{MfaToken: challenge.ID.String(), RecoveryCode: recoveryCodes[0]},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMFAChallenge(gomock.Any(), gomock.Eq(challenge.ID)).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					VerifyMFATx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MfaChallenge{}, db.ErrSecondFactorRejected)
+				expectUser(store)
+				expectFailure(store)
+			},
+			checkResponse: func(t *testing.T, res *pb.VerifyMFAResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "IncorrectCode",
+			req:  &pb.VerifyMFARequest{MfaToken: challenge.ID.String(), Code: wrongCode},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMFAChallenge(gomock.Any(), gomock.Eq(challenge.ID)).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(userTOTP, nil)
+				store.EXPECT().
+					VerifyMFATx(gomock.Any(), gomock.Any()).
+					Times(0)
+				expectUser(store)
+				expectFailure(store)
+			},
+			checkResponse: func(t *testing.T, res *pb.VerifyMFAResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "ReplayedCode",
+			req:  &pb.VerifyMFARequest{MfaToken: challenge.ID.String(), Code: code},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMFAChallenge(gomock.Any(), gomock.Eq(challenge.ID)).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(userTOTP, nil)
+				store.EXPECT().
+					VerifyMFATx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MfaChallenge{}, db.ErrSecondFactorRejected)
+				expectUser(store)
+				expectFailure(store)
+			},
+			checkResponse: func(t *testing.T, res *pb.VerifyMFAResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "ResetsFailedLogins",
+			req:  &pb.VerifyMFARequest{MfaToken: challenge.ID.String(), Code: code},
+			buildStubs: func(store *mockdb.MockStore) {
+				failedUser := user
+				failedUser.FailedLoginCount = 2
+
+				store.EXPECT().
+					GetMFAChallenge(gomock.Any(), gomock.Eq(challenge.ID)).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(failedUser, nil)
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(userTOTP, nil)
+				store.EXPECT().
+					VerifyMFATx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					ResetFailedLogins(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateSessionParams) (db.Session, error) {
+						return db.Session{ID: arg.ID, Username: arg.Username, ExpiredAt: arg.ExpiredAt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.VerifyMFAResponse, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, res.GetAccessToken())
+			},
+		},
+		{
+			name: "LockedUser",
+			req:  &pb.VerifyMFARequest{MfaToken: challenge.ID.String(), Code: code},
+			buildStubs: func(store *mockdb.MockStore) {
+				lockedUser := user
+				lockedUser.LockedUntil = time.Now().Add(time.Minute)
+
+				store.EXPECT().
+					GetMFAChallenge(gomock.Any(), gomock.Eq(challenge.ID)).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(lockedUser, nil)
+				store.EXPECT().
+					VerifyMFATx(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.VerifyMFAResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "ExpiredChallenge",
+			req:  &pb.VerifyMFARequest{MfaToken: challenge.ID.String(), Code: code},
+			buildStubs: func(store *mockdb.MockStore) {
+				expired := challenge
+				expired.ExpiredAt = time.Now().Add(-time.Second)
+
+				store.EXPECT().
+					GetMFAChallenge(gomock.Any(), gomock.Eq(challenge.ID)).
+					Times(1).
+					Return(expired, nil)
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.VerifyMFAResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "TooManyAttempts",
+			req:  &pb.VerifyMFARequest{MfaToken: challenge.ID.String(), Code: code},
+			buildStubs: func(store *mockdb.MockStore) {
+				locked := challenge
+				locked.Attempts = maxMFAAttempts
+
+				store.EXPECT().
+					GetMFAChallenge(gomock.Any(), gomock.Eq(challenge.ID)).
+					Times(1).
+					Return(locked, nil)
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.VerifyMFAResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "ChallengeUsedConcurrently",
+			req:  &pb.VerifyMFARequest{MfaToken: challenge.ID.String(), Code: code},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMFAChallenge(gomock.Any(), gomock.Eq(challenge.ID)).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					GetUserTOTP(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(userTOTP, nil)
+				store.EXPECT().
+					VerifyMFATx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MfaChallenge{}, sql.ErrNoRows)
+				expectUser(store)
+				store.EXPECT().
+					IncrementMFAChallengeAttempts(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.VerifyMFAResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "UnknownToken",
+			req:  &pb.VerifyMFARequest{MfaToken: uuid.New().String(), Code: code},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMFAChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MfaChallenge{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, res *pb.VerifyMFAResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "CodeAndRecoveryCode",
+			req:  &pb.VerifyMFARequest{MfaToken: challenge.ID.String(), Code: code, RecoveryCode: recoveryCodes[0]},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMFAChallenge(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.VerifyMFAResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "InvalidToken",
+			req:  &pb.VerifyMFARequest{MfaToken: "invalid", Code: code},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMFAChallenge(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.VerifyMFAResponse, err error) {
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			store := mockdb.NewMockStore(storeCtrl)
+
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			res, err := callUnary(context.Background(), server, pb.SimpleBank_VerifyMFA_FullMethodName, tc.req, server.VerifyMFA)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestVerifyMFATooManyAttempts(t *testing.T) {
+	user, _ := randomUser(t)
+	challenge := randomMFAChallenge(user.Username)
+	recoveryCodes, err := util.GenerateRecoveryCodes(1)
+	require.NoError(t, err)
+
+	storeCtrl := gomock.NewController(t)
+	defer storeCtrl.Finish()
+	store := mockdb.NewMockStore(storeCtrl)
+
+	// wrong codes use up the login attempts of the username, like wrong passwords
+	store.EXPECT().
+		GetMFAChallenge(gomock.Any(), gomock.Eq(challenge.ID)).
+		Times(2).
+		Return(challenge, nil)
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return(user, nil)
+	store.EXPECT().
+		VerifyMFATx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.MfaChallenge{}, db.ErrSecondFactorRejected)
+	store.EXPECT().
+		IncrementMFAChallengeAttempts(gomock.Any(), gomock.Eq(challenge.ID)).
+		Times(1)
+	store.EXPECT().
+		RecordFailedLoginTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(user, nil)
+
+	server := newTestServer(t, store, nil)
+	server.loginLimiter = ratelimit.NewLoginLimiter(util.Config{
+		LoginAttemptWindow:        time.Minute,
+		LoginUsernameAttemptLimit: 1,
+	})
+
+	req := &pb.VerifyMFARequest{MfaToken: challenge.ID.String(), RecoveryCode: recoveryCodes[0]}
+	_, err = callUnary(context.Background(), server, pb.SimpleBank_VerifyMFA_FullMethodName, req, server.VerifyMFA)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.Unauthenticated, st.Code())
+
+	_, err = callUnary(context.Background(), server, pb.SimpleBank_VerifyMFA_FullMethodName, req, server.VerifyMFA)
+	st, ok = status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.ResourceExhausted, st.Code())
+}
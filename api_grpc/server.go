@@ -1,10 +1,13 @@
 package apigrpc
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/google/uuid"
 	db "github.com/sangketkit01/simple-bank/db/sqlc"
 	"github.com/sangketkit01/simple-bank/pb"
+	"github.com/sangketkit01/simple-bank/ratelimit"
 	"github.com/sangketkit01/simple-bank/token"
 	"github.com/sangketkit01/simple-bank/util"
 	"github.com/sangketkit01/simple-bank/worker"
@@ -16,20 +19,51 @@ type Server struct{
 	config util.Config
 	store db.Store
 	tokenMaker token.Maker
+	cursorSigner *util.CursorSigner
+	revocationChecker token.RevocationChecker
+	apiKeyVerifier *token.APIKeyVerifier
 	taskDistributor worker.TaskDistributor
+	loginLimiter *ratelimit.LoginLimiter
+	verifyEmailLimiter ratelimit.Limiter
+	passwordResetLimiter ratelimit.Limiter
 }
 
 // NewServer creates a new gRPC server and setup routing
 func NewServer(config util.Config, store db.Store, taskDistributor worker.TaskDistributor) (*Server, error){
-	tokerMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+	tokerMaker, err := token.NewMaker(config)
 	if err != nil{
 		return nil, fmt.Errorf("cannot create token maker: %w",err)
 	}
+	cursorSigner, err := util.NewCursorSigner(config.TokenSymmetricKey)
+	if err != nil{
+		return nil, fmt.Errorf("cannot create cursor signer: %w",err)
+	}
+	revocationChecker := token.NewRevocationChecker(config, func(ctx context.Context, sessionID uuid.UUID) (token.SessionState, error){
+		state, err := db.SessionAuthState(ctx, store, sessionID)
+		return token.SessionState{Blocked: state.IsBlock, PasswordChangedAt: state.PasswordChangedAt}, err
+	})
+	apiKeyVerifier := token.NewAPIKeyVerifier(func(ctx context.Context, hashedKey string) (token.APIKeyState, error){
+		state, err := db.APIKeyAuthState(ctx, store, hashedKey)
+		return token.APIKeyState{
+			ID: state.ID,
+			Username: state.Username,
+			Role: state.Role,
+			Scopes: state.Scopes,
+			Revoked: state.IsRevoked,
+			ExpiredAt: state.ExpiredAt,
+		}, err
+	})
 	server := &Server{
 		config: config,
 		store: store,
 		tokenMaker: tokerMaker,
+		cursorSigner: cursorSigner,
+		revocationChecker: revocationChecker,
+		apiKeyVerifier: apiKeyVerifier,
 		taskDistributor: taskDistributor,
+		loginLimiter: ratelimit.NewLoginLimiter(config),
+		verifyEmailLimiter: ratelimit.NewLimiter(config, config.VerifyEmailResendWindow),
+		passwordResetLimiter: ratelimit.NewLimiter(config, config.PasswordResetRequestWindow),
 	}
 
 	return server, nil
@@ -0,0 +1,121 @@
+package apigrpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/token"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// userSession holds the tokens issued when a user signs in
+type userSession struct {
+	session        db.Session
+	accessToken    string
+	accessPayload  *token.Payload
+	refreshToken   string
+	refreshPayload *token.Payload
+}
+
+// createUserSession issues an access and a refresh token for the user and stores the new session.
+// It is shared by every way of signing in.
+func (server *Server) createUserSession(ctx context.Context, user db.User) (*userSession, error) {
+	sessionID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create session id: %s", err)
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, sessionID, server.config.AccessTokenDuration, token.TokenTypeAccessToken)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create token: %s", err)
+	}
+
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(
+		user.Username,
+		user.Role,
+		sessionID,
+		server.config.RefreshTokenDuration,
+		token.TokenTypeRefreshToken,
+	)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create refresh token: %s", err)
+	}
+
+	mtdt := server.extractMetadata(ctx)
+
+	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
+		ID:           sessionID,
+		Username:     user.Username,
+		RefreshToken: refreshToken,
+		UserAgent:    mtdt.UserAgent,
+		ClientIp:     mtdt.ClientIP,
+		IsBlock:      false,
+		ExpiredAt:    refreshPayload.ExpiredAt,
+		// a login starts a new family of rotated refresh tokens
+		FamilyID: sessionID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create session: %s", err)
+	}
+
+	return &userSession{
+		session:        session,
+		accessToken:    accessToken,
+		accessPayload:  accessPayload,
+		refreshToken:   refreshToken,
+		refreshPayload: refreshPayload,
+	}, nil
+}
+
+// validSession verifies the refresh token and checks it against its session row.
+func (server *Server) validSession(ctx context.Context, refreshToken string) (*token.Payload, db.Session, error) {
+	refreshPayload, err := server.tokenMaker.VerifyToken(refreshToken, token.TokenTypeRefreshToken)
+	if err != nil {
+		return nil, db.Session{}, unauthenticatedError(err)
+	}
+
+	session, blockedSessionIDs, err := db.ValidRefreshSession(ctx, server.store, db.ValidRefreshSessionParams{
+		SessionID:    refreshPayload.SessionID,
+		Username:     refreshPayload.Username,
+		RefreshToken: refreshToken,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, session, status.Errorf(codes.NotFound, "session not found")
+		case errors.Is(err, db.ErrRefreshTokenReused):
+			return nil, session, server.refreshTokenReused(ctx, session, blockedSessionIDs)
+		case errors.Is(err, db.ErrSessionBlocked),
+			errors.Is(err, db.ErrSessionUserMismatch),
+			errors.Is(err, db.ErrSessionTokenMismatch),
+			errors.Is(err, db.ErrSessionExpired):
+			return nil, session, unauthenticatedError(err)
+		}
+		return nil, session, status.Errorf(codes.Internal, "failed to check session: %s", err)
+	}
+
+	return refreshPayload, session, nil
+}
+
+// refreshTokenReused signs out the family of a reused refresh token and returns the error to answer with
+func (server *Server) refreshTokenReused(ctx context.Context, session db.Session, blockedSessionIDs []uuid.UUID) error {
+	mtdt := server.extractMetadata(ctx)
+
+	err := token.RevokeReusedFamily(ctx, server.revocationChecker, token.RefreshTokenReuse{
+		Username:          session.Username,
+		SessionID:         session.ID,
+		FamilyID:          session.FamilyID,
+		BlockedSessionIDs: blockedSessionIDs,
+		ClientIP:          mtdt.ClientIP,
+		UserAgent:         mtdt.UserAgent,
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to revoke session family: %s", err)
+	}
+
+	return unauthenticatedError(db.ErrRefreshTokenReused)
+}
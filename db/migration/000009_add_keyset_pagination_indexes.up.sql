@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS "entries_account_id_id_idx";
+DROP INDEX IF EXISTS "transfers_from_account_id_id_idx";
+DROP INDEX IF EXISTS "transfers_to_account_id_id_idx";
+
+CREATE INDEX ON "accounts" ("owner", "created_at", "id");
+
+CREATE INDEX ON "entries" ("account_id", "created_at", "id");
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at", "id");
+
+CREATE INDEX ON "transfers" ("to_account_id", "created_at", "id");
@@ -0,0 +1,9 @@
+ALTER TABLE "sessions" ADD COLUMN "family_id" uuid;
+
+UPDATE "sessions" SET "family_id" = "id";
+
+ALTER TABLE "sessions" ALTER COLUMN "family_id" SET NOT NULL;
+
+ALTER TABLE "sessions" ADD COLUMN "is_rotated" boolean NOT NULL DEFAULT false;
+
+CREATE INDEX ON "sessions" ("family_id");
@@ -0,0 +1,2 @@
+ALTER TABLE "users" ADD COLUMN "failed_login_count" int NOT NULL DEFAULT 0;
+ALTER TABLE "users" ADD COLUMN "locked_until" timestamptz NOT NULL DEFAULT '0001-01-01 00:00:00Z';
@@ -0,0 +1,15 @@
+CREATE TABLE "api_keys" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "hashed_key" varchar UNIQUE NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "is_revoked" bool NOT NULL DEFAULT false,
+  "last_used_at" timestamptz,
+  "expired_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "api_keys" ("username");
+
+ALTER TABLE "api_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
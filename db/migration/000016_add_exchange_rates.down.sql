@@ -0,0 +1,6 @@
+ALTER TABLE "transfers" DROP COLUMN "quote_id";
+ALTER TABLE "transfers" DROP COLUMN "exchange_rate";
+ALTER TABLE "transfers" DROP COLUMN "credit_amount";
+
+DROP TABLE IF EXISTS "transfer_quotes";
+DROP TABLE IF EXISTS "exchange_rates";
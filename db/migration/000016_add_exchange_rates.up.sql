@@ -0,0 +1,48 @@
+CREATE TABLE "exchange_rates" (
+  "id" bigserial PRIMARY KEY,
+  "base_currency" varchar NOT NULL,
+  "quote_currency" varchar NOT NULL,
+  "rate" numeric(20,10) NOT NULL,
+  "source" varchar NOT NULL,
+  "valid_from" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "exchange_rates_rate_check" CHECK ("rate" > 0)
+);
+
+CREATE INDEX ON "exchange_rates" ("base_currency", "quote_currency", "valid_from");
+
+CREATE TABLE "transfer_quotes" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "credit_amount" bigint NOT NULL,
+  "exchange_rate_id" bigint NOT NULL,
+  "exchange_rate" numeric(20,10) NOT NULL,
+  "is_used" bool NOT NULL DEFAULT false,
+  "expired_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "transfer_quotes" ("username");
+
+ALTER TABLE "transfer_quotes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_quotes" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_quotes" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_quotes" ADD FOREIGN KEY ("exchange_rate_id") REFERENCES "exchange_rates" ("id");
+
+ALTER TABLE "transfers" ADD COLUMN "credit_amount" bigint;
+
+UPDATE "transfers" SET "credit_amount" = "amount";
+
+ALTER TABLE "transfers" ALTER COLUMN "credit_amount" SET NOT NULL;
+
+ALTER TABLE "transfers" ADD COLUMN "exchange_rate" numeric(20,10) NOT NULL DEFAULT 1;
+
+ALTER TABLE "transfers" ADD COLUMN "quote_id" uuid UNIQUE;
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("quote_id") REFERENCES "transfer_quotes" ("id");
@@ -0,0 +1,24 @@
+CREATE TABLE "currencies" (
+  "code" varchar(3) PRIMARY KEY,
+  "minor_unit" int NOT NULL,
+  "is_enabled" bool NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "currencies_minor_unit_check" CHECK ("minor_unit" BETWEEN 0 AND 2)
+);
+
+COMMENT ON COLUMN "currencies"."code" IS 'ISO 4217 currency code';
+
+COMMENT ON COLUMN "currencies"."minor_unit" IS 'number of decimal places of the currency, amounts are stored in hundredths so it is at most 2';
+
+INSERT INTO "currencies" ("code", "minor_unit") VALUES
+  ('USD', 2),
+  ('EUR', 2),
+  ('CAD', 2),
+  ('THB', 2),
+  ('JPY', 0);
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("base_currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("quote_currency") REFERENCES "currencies" ("code");
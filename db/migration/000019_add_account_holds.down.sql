@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "account_holds";
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "accounts_held_amount_check";
+ALTER TABLE "accounts" DROP COLUMN "held_amount";
@@ -0,0 +1,43 @@
+ALTER TABLE "accounts" ADD COLUMN "held_amount" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_held_amount_check" CHECK ("held_amount" >= 0 AND "held_amount" <= "balance");
+
+COMMENT ON COLUMN "accounts"."held_amount" IS 'sum of the active holds, the available balance is balance - held_amount';
+
+CREATE TABLE "account_holds" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "captured_amount" bigint NOT NULL DEFAULT 0,
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "settled_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "account_holds_amount_check" CHECK ("amount" > 0),
+  CONSTRAINT "account_holds_captured_amount_check" CHECK ("captured_amount" >= 0 AND "captured_amount" <= "amount")
+);
+
+CREATE INDEX ON "account_holds" ("owner");
+
+CREATE INDEX ON "account_holds" ("account_id");
+
+CREATE INDEX ON "account_holds" ("status", "expires_at");
+
+COMMENT ON COLUMN "account_holds"."amount" IS 'reserved on account_id until the hold is captured, released or expires';
+
+COMMENT ON COLUMN "account_holds"."status" IS 'active, captured, released or expired';
+
+COMMENT ON COLUMN "account_holds"."captured_amount" IS 'transferred to to_account_id by the capture, the rest of the amount is released';
+
+ALTER TABLE "account_holds" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "account_holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_holds" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_holds" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "account_holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
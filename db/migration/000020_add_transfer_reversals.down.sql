@@ -0,0 +1,2 @@
+ALTER TABLE "transfers" DROP COLUMN "reason";
+ALTER TABLE "transfers" DROP COLUMN "reversal_of";
@@ -0,0 +1,10 @@
+ALTER TABLE "transfers" ADD COLUMN "reversal_of" bigint;
+ALTER TABLE "transfers" ADD COLUMN "reason" varchar NOT NULL DEFAULT '';
+
+CREATE INDEX ON "transfers" ("reversal_of");
+
+COMMENT ON COLUMN "transfers"."reversal_of" IS 'the transfer this one reverses, fully or partially';
+
+COMMENT ON COLUMN "transfers"."reason" IS 'why the transfer was reversed, required for reversals';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reversal_of") REFERENCES "transfers" ("id");
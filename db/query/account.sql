@@ -18,10 +18,11 @@ FOR NO KEY UPDATE;
 
 -- name: ListAccounts :many
 SELECT * FROM accounts
-WHERE owner = $1
-ORDER BY id
-LIMIT $2
-OFFSET $3;
+WHERE
+    owner = sqlc.arg(owner) AND
+    (sqlc.narg(cursor_id)::bigint IS NULL OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)))
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_size);
 
 -- name: UpdateAccount :one
 UPDATE accounts
@@ -38,3 +39,9 @@ RETURNING *;
 -- name: DeleteAccount :exec
 DELETE FROM accounts
 WHERE id = $1;
+
+-- name: AddAccountHeldAmount :one
+UPDATE accounts
+SET held_amount = held_amount + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -0,0 +1,38 @@
+-- name: CreateAccountHold :one
+INSERT INTO account_holds(
+    owner,
+    account_id,
+    to_account_id,
+    amount,
+    currency,
+    expires_at
+) VALUES(
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetAccountHold :one
+SELECT * FROM account_holds
+WHERE id = $1 LIMIT 1;
+
+-- name: GetAccountHoldForUpdate :one
+SELECT * FROM account_holds
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListExpiredAccountHolds :many
+SELECT * FROM account_holds
+WHERE
+    status = 'active' AND
+    expires_at <= now()
+ORDER BY expires_at
+LIMIT $1;
+
+-- name: SettleAccountHold :one
+UPDATE account_holds
+SET
+    status = @status,
+    captured_amount = @captured_amount,
+    transfer_id = sqlc.narg(transfer_id),
+    settled_at = now()
+WHERE id = @id
+RETURNING *;
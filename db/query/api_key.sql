@@ -0,0 +1,53 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys(
+    username,
+    name,
+    hashed_key,
+    scopes,
+    expired_at
+) VALUES(
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListAPIKeys :many
+SELECT * FROM api_keys
+WHERE username = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET
+    is_revoked = TRUE
+WHERE
+    id = @id AND
+    username = @username
+RETURNING *;
+
+-- name: RevokeUserAPIKeys :exec
+UPDATE api_keys
+SET
+    is_revoked = TRUE
+WHERE
+    username = @username AND
+    is_revoked = FALSE;
+
+-- name: GetAPIKeyAuthState :one
+SELECT
+    api_keys.id,
+    api_keys.username,
+    api_keys.scopes,
+    api_keys.is_revoked,
+    api_keys.expired_at,
+    users.role
+FROM api_keys
+JOIN users ON users.username = api_keys.username
+WHERE api_keys.hashed_key = $1
+LIMIT 1;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET
+    last_used_at = now()
+WHERE
+    id = @id AND
+    (last_used_at IS NULL OR last_used_at < now() - interval '1 minute');
@@ -12,7 +12,12 @@ WHERE id = $1 LIMIT 1;
 
 -- name: ListEntries :many
 SELECT * FROM entries
-WHERE account_id = $1
-ORDER BY id
-LIMIT $2
-OFFSET $3;
\ No newline at end of file
+WHERE
+    account_id = sqlc.arg(account_id) AND
+    (sqlc.narg(start_time)::timestamp IS NULL OR created_at >= sqlc.narg(start_time)) AND
+    (sqlc.narg(end_time)::timestamp IS NULL OR created_at < sqlc.narg(end_time)) AND
+    (sqlc.arg(direction)::varchar <> 'incoming' OR amount > 0) AND
+    (sqlc.arg(direction)::varchar <> 'outgoing' OR amount < 0) AND
+    (sqlc.narg(cursor_id)::bigint IS NULL OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
\ No newline at end of file
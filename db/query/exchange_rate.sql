@@ -0,0 +1,19 @@
+-- name: CreateExchangeRate :one
+INSERT INTO exchange_rates(
+    base_currency,
+    quote_currency,
+    rate,
+    source,
+    valid_from
+) VALUES(
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetLatestExchangeRate :one
+SELECT * FROM exchange_rates
+WHERE
+    base_currency = @base_currency AND
+    quote_currency = @quote_currency AND
+    valid_from <= now()
+ORDER BY valid_from DESC, id DESC
+LIMIT 1;
@@ -0,0 +1,33 @@
+-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys(
+    username,
+    idempotency_key,
+    request_hash,
+    expired_at
+) VALUES(
+    $1, $2, $3, $4
+)
+ON CONFLICT (username, idempotency_key) DO UPDATE
+SET
+    request_hash = EXCLUDED.request_hash,
+    transfer_id = NULL,
+    response = '{}',
+    created_at = now(),
+    expired_at = EXCLUDED.expired_at
+WHERE
+    idempotency_keys.expired_at <= now()
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE username = $1 AND idempotency_key = $2 LIMIT 1;
+
+-- name: UpdateIdempotencyKeyResponse :one
+UPDATE idempotency_keys
+SET
+    transfer_id = sqlc.arg(transfer_id),
+    response = sqlc.arg(response)
+WHERE
+    username = sqlc.arg(username) AND
+    idempotency_key = sqlc.arg(idempotency_key)
+RETURNING *;
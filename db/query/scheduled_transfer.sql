@@ -0,0 +1,65 @@
+-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers(
+    owner,
+    from_account_id,
+    to_account_id,
+    amount,
+    currency,
+    schedule,
+    starts_at,
+    next_run_at
+) VALUES(
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1;
+
+-- name: GetScheduledTransferForUpdate :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListScheduledTransfers :many
+SELECT * FROM scheduled_transfers
+WHERE owner = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: ListDueScheduledTransfers :many
+SELECT * FROM scheduled_transfers
+WHERE
+    status = 'active' AND
+    next_run_at <= now()
+ORDER BY next_run_at
+LIMIT $1;
+
+-- name: UpdateScheduledTransfer :one
+UPDATE scheduled_transfers
+SET
+    amount = COALESCE(sqlc.narg(amount), amount),
+    schedule = COALESCE(sqlc.narg(schedule), schedule),
+    starts_at = COALESCE(sqlc.narg(starts_at), starts_at),
+    next_run_at = COALESCE(sqlc.narg(next_run_at), next_run_at),
+    next_occurrence = COALESCE(sqlc.narg(next_occurrence), next_occurrence),
+    status = COALESCE(sqlc.narg(status), status),
+    failure_count = COALESCE(sqlc.narg(failure_count), failure_count)
+WHERE id = @id
+RETURNING *;
+
+-- name: CreateScheduledTransferRun :one
+INSERT INTO scheduled_transfer_runs(
+    scheduled_transfer_id,
+    scheduled_at,
+    status,
+    transfer_id,
+    error
+) VALUES(
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListScheduledTransferRuns :many
+SELECT * FROM scheduled_transfer_runs
+WHERE scheduled_transfer_id = $1
+ORDER BY scheduled_at DESC
+LIMIT $2;
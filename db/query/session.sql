@@ -6,11 +6,51 @@ INSERT INTO sessions(
     user_agent,
     client_ip,
     is_block,
-    expired_at
+    expired_at,
+    family_id
 ) VALUES(
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING *;
 
 -- name: GetSession :one
 SELECT * FROM sessions
 WHERE id = $1 LIMIT 1;
+
+-- name: BlockSession :one
+UPDATE sessions
+SET is_block = true
+WHERE id = $1
+RETURNING *;
+
+-- name: BlockUserSessions :many
+UPDATE sessions
+SET is_block = true
+WHERE username = $1 AND is_block = false
+RETURNING id;
+
+-- name: RotateSession :one
+UPDATE sessions
+SET is_rotated = true
+WHERE id = $1 AND is_rotated = false
+RETURNING *;
+
+-- name: BlockSessionFamily :many
+UPDATE sessions
+SET is_block = true
+WHERE family_id = $1 AND is_block = false
+RETURNING id;
+
+-- name: ListActiveSessions :many
+SELECT * FROM sessions
+WHERE
+    username = $1 AND
+    is_block = false AND
+    is_rotated = false AND
+    expired_at > now()
+ORDER BY created_at DESC;
+
+-- name: GetSessionAuthState :one
+SELECT sessions.is_block, users.password_changed_at
+FROM sessions
+JOIN users ON users.username = sessions.username
+WHERE sessions.id = $1 LIMIT 1;
@@ -0,0 +1,81 @@
+-- name: UpsertUserTOTP :one
+INSERT INTO user_totps(
+    username,
+    secret
+) VALUES(
+    $1, $2
+) ON CONFLICT (username) DO UPDATE
+SET secret = EXCLUDED.secret,
+    is_confirmed = false,
+    last_used_step = 0,
+    created_at = now(),
+    confirmed_at = NULL
+WHERE user_totps.is_confirmed = false
+RETURNING *;
+
+-- name: GetUserTOTP :one
+SELECT * FROM user_totps
+WHERE username = $1 LIMIT 1;
+
+-- name: ConfirmUserTOTP :one
+UPDATE user_totps
+SET
+    is_confirmed = true,
+    confirmed_at = now(),
+    last_used_step = sqlc.arg(step)
+WHERE username = sqlc.arg(username) AND is_confirmed = false
+RETURNING *;
+
+-- name: UseTOTPStep :one
+UPDATE user_totps
+SET last_used_step = sqlc.arg(step)
+WHERE username = sqlc.arg(username)
+    AND is_confirmed = true
+    AND last_used_step < sqlc.arg(step)
+RETURNING *;
+
+-- name: CreateRecoveryCode :one
+INSERT INTO recovery_codes(
+    username,
+    hashed_code
+) VALUES(
+    $1, $2
+) RETURNING *;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE username = $1;
+
+-- name: UseRecoveryCode :one
+UPDATE recovery_codes
+SET used_at = now()
+WHERE username = $1 AND hashed_code = $2 AND used_at IS NULL
+RETURNING *;
+
+-- name: CreateMFAChallenge :one
+INSERT INTO mfa_challenges(
+    id,
+    username,
+    expired_at
+) VALUES(
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetMFAChallenge :one
+SELECT * FROM mfa_challenges
+WHERE id = $1 LIMIT 1;
+
+-- name: IncrementMFAChallengeAttempts :one
+UPDATE mfa_challenges
+SET attempts = attempts + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: UseMFAChallenge :one
+UPDATE mfa_challenges
+SET is_used = true
+WHERE id = sqlc.arg(id)
+    AND is_used = false
+    AND attempts < sqlc.arg(max_attempts)
+    AND expired_at > now()
+RETURNING *;
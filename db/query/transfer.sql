@@ -2,18 +2,41 @@
 INSERT INTO transfers(
     from_account_id,
     to_account_id,
-    amount
+    amount,
+    credit_amount,
+    exchange_rate,
+    quote_id,
+    reversal_of,
+    reason
 ) VALUES(
-    $1, $2, $3
+    $1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING *;
 
 -- name: GetTransfer :one
 SELECT * FROM transfers
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetTransferReversedAmount :one
+SELECT
+    COALESCE(SUM(amount), 0)::bigint AS debited_amount,
+    COALESCE(SUM(credit_amount), 0)::bigint AS refunded_amount
+FROM transfers
+WHERE reversal_of = sqlc.arg(transfer_id)::bigint;
+
 -- name: ListTransfers :many
 SELECT * FROM transfers
-WHERE from_account_id = $1 OR to_account_id = $2
-ORDER BY id
-LIMIT $3
-OFFSET $4;
\ No newline at end of file
+WHERE
+    (
+        (sqlc.arg(direction)::varchar <> 'outgoing' AND to_account_id = sqlc.arg(account_id)) OR
+        (sqlc.arg(direction)::varchar <> 'incoming' AND from_account_id = sqlc.arg(account_id))
+    ) AND
+    (sqlc.narg(start_time)::timestamptz IS NULL OR created_at >= sqlc.narg(start_time)) AND
+    (sqlc.narg(end_time)::timestamptz IS NULL OR created_at < sqlc.narg(end_time)) AND
+    (sqlc.narg(cursor_id)::bigint IS NULL OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
\ No newline at end of file
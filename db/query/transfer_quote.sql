@@ -0,0 +1,25 @@
+-- name: CreateTransferQuote :one
+INSERT INTO transfer_quotes(
+    id,
+    username,
+    from_account_id,
+    to_account_id,
+    amount,
+    credit_amount,
+    exchange_rate_id,
+    exchange_rate,
+    expired_at
+) VALUES(
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING *;
+
+-- name: UseTransferQuote :one
+UPDATE transfer_quotes
+SET
+    is_used = TRUE
+WHERE
+    id = @id AND
+    username = @username AND
+    is_used = FALSE AND
+    expired_at > now()
+RETURNING *;
@@ -12,6 +12,15 @@ INSERT INTO users(
 SELECT * FROM users
 WHERE username = $1 LIMIT 1;
 
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE username = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = $1 LIMIT 1;
+
 -- name: UpdateUser :one
 UPDATE users 
 SET 
@@ -19,7 +28,32 @@ SET
     password_changed_at = COALESCE(sqlc.narg(password_changed_at), password_changed_at),
     full_name = COALESCE(sqlc.narg(full_name), full_name),
     email = COALESCE(sqlc.narg(email), email),
-    is_email_verified = COALESCE(sqlc.narg(is_email_verified), is_email_verified)
+    is_email_verified = COALESCE(sqlc.narg(is_email_verified), is_email_verified),
+    role = COALESCE(sqlc.narg(role), role)
 WHERE 
     username = sqlc.arg(username)
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+-- name: RecordFailedLogin :one
+UPDATE users
+SET
+    failed_login_count = failed_login_count + 1
+WHERE
+    username = $1
+RETURNING *;
+
+-- name: LockUser :one
+UPDATE users
+SET
+    locked_until = GREATEST(locked_until, sqlc.arg(locked_until))
+WHERE
+    username = sqlc.arg(username)
+RETURNING *;
+
+-- name: ResetFailedLogins :one
+UPDATE users
+SET
+    failed_login_count = 0,
+    locked_until = '0001-01-01 00:00:00Z'
+WHERE
+    username = $1
+RETURNING *;
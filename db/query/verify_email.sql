@@ -16,4 +16,12 @@ WHERE
     secret_code = @secret_code AND
     is_used = FALSE AND
     expired_at > now()
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: InvalidateVerifyEmails :exec
+UPDATE verify_emails
+SET
+    is_used = TRUE
+WHERE
+    username = @username AND
+    is_used = FALSE;
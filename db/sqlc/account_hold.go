@@ -0,0 +1,17 @@
+package db
+
+import "errors"
+
+// ErrHoldNotActive is returned when the hold was already captured, released or expired
+var ErrHoldNotActive = errors.New("hold is no longer active")
+
+// ErrHoldExpired is returned when the hold is past its expiry time and can no longer be captured
+var ErrHoldExpired = errors.New("hold has expired")
+
+// ErrCaptureExceedsHold is returned when the capture amount is more than the held amount
+var ErrCaptureExceedsHold = errors.New("capture amount exceeds the held amount")
+
+// AvailableBalance is the part of the balance that is not reserved by active holds
+func (account Account) AvailableBalance() int64 {
+	return account.Balance - account.HeldAmount
+}
@@ -0,0 +1,200 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.20.0
+// source: account_hold.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createAccountHold = `-- name: CreateAccountHold :one
+INSERT INTO account_holds(
+    owner,
+    account_id,
+    to_account_id,
+    amount,
+    currency,
+    expires_at
+) VALUES(
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, owner, account_id, to_account_id, amount, currency, status, captured_amount, transfer_id, expires_at, settled_at, created_at
+`
+
+type CreateAccountHoldParams struct {
+	Owner       string    `json:"owner"`
+	AccountID   int64     `json:"account_id"`
+	ToAccountID int64     `json:"to_account_id"`
+	Amount      int64     `json:"amount"`
+	Currency    string    `json:"currency"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateAccountHold(ctx context.Context, arg CreateAccountHoldParams) (AccountHold, error) {
+	row := q.db.QueryRowContext(ctx, createAccountHold,
+		arg.Owner,
+		arg.AccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Currency,
+		arg.ExpiresAt,
+	)
+	var i AccountHold
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.SettledAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAccountHold = `-- name: GetAccountHold :one
+SELECT id, owner, account_id, to_account_id, amount, currency, status, captured_amount, transfer_id, expires_at, settled_at, created_at FROM account_holds
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetAccountHold(ctx context.Context, id int64) (AccountHold, error) {
+	row := q.db.QueryRowContext(ctx, getAccountHold, id)
+	var i AccountHold
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.SettledAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAccountHoldForUpdate = `-- name: GetAccountHoldForUpdate :one
+SELECT id, owner, account_id, to_account_id, amount, currency, status, captured_amount, transfer_id, expires_at, settled_at, created_at FROM account_holds
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetAccountHoldForUpdate(ctx context.Context, id int64) (AccountHold, error) {
+	row := q.db.QueryRowContext(ctx, getAccountHoldForUpdate, id)
+	var i AccountHold
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.SettledAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listExpiredAccountHolds = `-- name: ListExpiredAccountHolds :many
+SELECT id, owner, account_id, to_account_id, amount, currency, status, captured_amount, transfer_id, expires_at, settled_at, created_at FROM account_holds
+WHERE
+    status = 'active' AND
+    expires_at <= now()
+ORDER BY expires_at
+LIMIT $1
+`
+
+func (q *Queries) ListExpiredAccountHolds(ctx context.Context, limit int32) ([]AccountHold, error) {
+	rows, err := q.db.QueryContext(ctx, listExpiredAccountHolds, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AccountHold{}
+	for rows.Next() {
+		var i AccountHold
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.AccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.Currency,
+			&i.Status,
+			&i.CapturedAmount,
+			&i.TransferID,
+			&i.ExpiresAt,
+			&i.SettledAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const settleAccountHold = `-- name: SettleAccountHold :one
+UPDATE account_holds
+SET
+    status = $1,
+    captured_amount = $2,
+    transfer_id = $3,
+    settled_at = now()
+WHERE id = $4
+RETURNING id, owner, account_id, to_account_id, amount, currency, status, captured_amount, transfer_id, expires_at, settled_at, created_at
+`
+
+type SettleAccountHoldParams struct {
+	Status         string        `json:"status"`
+	CapturedAmount int64         `json:"captured_amount"`
+	TransferID     sql.NullInt64 `json:"transfer_id"`
+	ID             int64         `json:"id"`
+}
+
+func (q *Queries) SettleAccountHold(ctx context.Context, arg SettleAccountHoldParams) (AccountHold, error) {
+	row := q.db.QueryRowContext(ctx, settleAccountHold,
+		arg.Status,
+		arg.CapturedAmount,
+		arg.TransferID,
+		arg.ID,
+	)
+	var i AccountHold
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.SettledAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
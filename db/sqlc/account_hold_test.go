@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomHold(t *testing.T, store Store, account1, account2 Account, amount int64) CreateHoldTxResult {
+	result, err := store.CreateHoldTx(context.Background(), CreateHoldTxParams{
+		Owner:       account1.Owner,
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      amount,
+		Currency:    account1.Currency,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.NotZero(t, result.Hold.ID)
+	require.Equal(t, util.HoldActive, result.Hold.Status)
+	require.Equal(t, amount, result.Hold.Amount)
+
+	return result
+}
+
+func TestCreateHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 1000)
+	account2 := createRandomAccount(t)
+
+	result := createRandomHold(t, store, account1, account2, 600)
+	require.Equal(t, int64(1000), result.Account.Balance)
+	require.Equal(t, int64(600), result.Account.HeldAmount)
+	require.Equal(t, int64(400), result.Account.AvailableBalance())
+
+	// the held amount is not available to other holds
+	_, err := store.CreateHoldTx(context.Background(), CreateHoldTxParams{
+		Owner:       account1.Owner,
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      500,
+		Currency:    account1.Currency,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// nor to transfers
+	_, err = store.TransferTx(context.Background(), TransferParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        500,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestCaptureHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 1000)
+	account2 := createRandomAccount(t)
+
+	hold := createRandomHold(t, store, account1, account2, 600).Hold
+
+	_, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID, Amount: 700})
+	require.ErrorIs(t, err, ErrCaptureExceedsHold)
+
+	result, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID, Amount: 200})
+	require.NoError(t, err)
+	require.Equal(t, util.HoldCaptured, result.Hold.Status)
+	require.Equal(t, int64(200), result.Hold.CapturedAmount)
+	require.Equal(t, result.Transfer.ID, result.Hold.TransferID.Int64)
+	require.True(t, result.Hold.SettledAt.Valid)
+
+	require.Equal(t, int64(200), result.Transfer.Amount)
+	require.Equal(t, int64(-200), result.FromEntry.Amount)
+	require.Equal(t, int64(200), result.ToEntry.Amount)
+
+	// the rest of the hold is released
+	require.Equal(t, int64(800), result.FromAccount.Balance)
+	require.Zero(t, result.FromAccount.HeldAmount)
+	require.Equal(t, account2.Balance+200, result.ToAccount.Balance)
+
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+}
+
+func TestReleaseHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 1000)
+	account2 := createRandomAccount(t)
+
+	hold := createRandomHold(t, store, account1, account2, 600).Hold
+
+	result, err := store.ReleaseHoldTx(context.Background(), ReleaseHoldTxParams{HoldID: hold.ID, Expire: true})
+	require.NoError(t, err)
+	require.Equal(t, util.HoldExpired, result.Hold.Status)
+	require.Zero(t, result.Hold.CapturedAmount)
+	require.Equal(t, int64(1000), result.Account.Balance)
+	require.Zero(t, result.Account.HeldAmount)
+
+	_, err = store.ReleaseHoldTx(context.Background(), ReleaseHoldTxParams{HoldID: hold.ID})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+}
@@ -81,23 +81,36 @@ func TestDeleteAccount(t *testing.T){
 }
 
 func TestListAccounts(t *testing.T){
-	var lastAccount Account
-	for i:= 0 ; i < 10 ; i++{
-		lastAccount = createRandomAccount(t)
+	owner := createRandomUser(t)
+	for _, currency := range []string{util.USD, util.EUR, util.CAD}{
+		_, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			Owner: owner.Username,
+			Balance: util.RandomMoney(),
+			Currency: currency,
+		})
+		require.NoError(t, err)
 	}
 
 	arg := ListAccountsParams{
-		Owner: lastAccount.Owner,
-		Limit: 5,
-		Offset: 0,
+		Owner: owner.Username,
+		PageSize: 2,
 	}
 
 	accounts, err := testQueries.ListAccounts(context.Background(), arg)
 	require.NoError(t, err)
-	require.NotEmpty(t, accounts)
+	require.Len(t, accounts, 2)
 
 	for _, account := range accounts{
 		require.NotEmpty(t, account)
-		require.Equal(t, lastAccount.Owner, account.Owner)
+		require.Equal(t, owner.Username, account.Owner)
 	}
+
+	// the next page continues right after the last account of this one
+	last := accounts[len(accounts)-1]
+	arg.CursorID = sql.NullInt64{Int64: last.ID, Valid: true}
+	arg.CursorCreatedAt = sql.NullTime{Time: last.CreatedAt, Valid: true}
+	nextAccounts, err := testQueries.ListAccounts(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, nextAccounts, 1)
+	require.NotContains(t, accounts, nextAccounts[0])
 }
\ No newline at end of file
@@ -0,0 +1,29 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// APIKeyAuthState returns what API key requests are checked against and records the use of a valid key,
+// an unknown key counts as revoked
+func APIKeyAuthState(ctx context.Context, q Querier, hashedKey string) (GetAPIKeyAuthStateRow, error) {
+	state, err := q.GetAPIKeyAuthState(ctx, hashedKey)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return GetAPIKeyAuthStateRow{IsRevoked: true}, nil
+		}
+		return GetAPIKeyAuthStateRow{}, err
+	}
+
+	if !state.IsRevoked && time.Now().Before(state.ExpiredAt) {
+		err = q.TouchAPIKey(ctx, state.ID)
+		if err != nil {
+			return GetAPIKeyAuthStateRow{}, err
+		}
+	}
+
+	return state, nil
+}
@@ -0,0 +1,192 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.20.0
+// source: api_key.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const createAPIKey = `-- name: CreateAPIKey :one
+INSERT INTO api_keys(
+    username,
+    name,
+    hashed_key,
+    scopes,
+    expired_at
+) VALUES(
+    $1, $2, $3, $4, $5
+) RETURNING id, username, name, hashed_key, scopes, is_revoked, last_used_at, expired_at, created_at
+`
+
+type CreateAPIKeyParams struct {
+	Username  string    `json:"username"`
+	Name      string    `json:"name"`
+	HashedKey string    `json:"hashed_key"`
+	Scopes    []string  `json:"scopes"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error) {
+	row := q.db.QueryRowContext(ctx, createAPIKey,
+		arg.Username,
+		arg.Name,
+		arg.HashedKey,
+		pq.Array(arg.Scopes),
+		arg.ExpiredAt,
+	)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Name,
+		&i.HashedKey,
+		pq.Array(&i.Scopes),
+		&i.IsRevoked,
+		&i.LastUsedAt,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAPIKeyAuthState = `-- name: GetAPIKeyAuthState :one
+SELECT
+    api_keys.id,
+    api_keys.username,
+    api_keys.scopes,
+    api_keys.is_revoked,
+    api_keys.expired_at,
+    users.role
+FROM api_keys
+JOIN users ON users.username = api_keys.username
+WHERE api_keys.hashed_key = $1
+LIMIT 1
+`
+
+type GetAPIKeyAuthStateRow struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	Scopes    []string  `json:"scopes"`
+	IsRevoked bool      `json:"is_revoked"`
+	ExpiredAt time.Time `json:"expired_at"`
+	Role      string    `json:"role"`
+}
+
+func (q *Queries) GetAPIKeyAuthState(ctx context.Context, hashedKey string) (GetAPIKeyAuthStateRow, error) {
+	row := q.db.QueryRowContext(ctx, getAPIKeyAuthState, hashedKey)
+	var i GetAPIKeyAuthStateRow
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		pq.Array(&i.Scopes),
+		&i.IsRevoked,
+		&i.ExpiredAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const listAPIKeys = `-- name: ListAPIKeys :many
+SELECT id, username, name, hashed_key, scopes, is_revoked, last_used_at, expired_at, created_at FROM api_keys
+WHERE username = $1
+ORDER BY created_at DESC, id DESC
+`
+
+func (q *Queries) ListAPIKeys(ctx context.Context, username string) ([]ApiKey, error) {
+	rows, err := q.db.QueryContext(ctx, listAPIKeys, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ApiKey{}
+	for rows.Next() {
+		var i ApiKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Name,
+			&i.HashedKey,
+			pq.Array(&i.Scopes),
+			&i.IsRevoked,
+			&i.LastUsedAt,
+			&i.ExpiredAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeAPIKey = `-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET
+    is_revoked = TRUE
+WHERE
+    id = $1 AND
+    username = $2
+RETURNING id, username, name, hashed_key, scopes, is_revoked, last_used_at, expired_at, created_at
+`
+
+type RevokeAPIKeyParams struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (ApiKey, error) {
+	row := q.db.QueryRowContext(ctx, revokeAPIKey, arg.ID, arg.Username)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Name,
+		&i.HashedKey,
+		pq.Array(&i.Scopes),
+		&i.IsRevoked,
+		&i.LastUsedAt,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const revokeUserAPIKeys = `-- name: RevokeUserAPIKeys :exec
+UPDATE api_keys
+SET
+    is_revoked = TRUE
+WHERE
+    username = $1 AND
+    is_revoked = FALSE
+`
+
+func (q *Queries) RevokeUserAPIKeys(ctx context.Context, username string) error {
+	_, err := q.db.ExecContext(ctx, revokeUserAPIKeys, username)
+	return err
+}
+
+const touchAPIKey = `-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET
+    last_used_at = now()
+WHERE
+    id = $1 AND
+    (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')
+`
+
+func (q *Queries) TouchAPIKey(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, touchAPIKey, id)
+	return err
+}
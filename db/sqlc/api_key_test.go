@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomAPIKey(t *testing.T, user User) ApiKey {
+	key, err := util.GenerateAPIKey()
+	require.NoError(t, err)
+
+	arg := CreateAPIKeyParams{
+		Username:  user.Username,
+		Name:      util.RandomString(10),
+		HashedKey: util.HashAPIKey(key),
+		Scopes:    []string{util.AccountsReadScope, util.TransfersReadScope},
+		ExpiredAt: time.Now().Add(time.Hour),
+	}
+
+	apiKey, err := testQueries.CreateAPIKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, apiKey.ID)
+	require.Equal(t, arg.Username, apiKey.Username)
+	require.Equal(t, arg.Name, apiKey.Name)
+	require.Equal(t, arg.HashedKey, apiKey.HashedKey)
+	require.Equal(t, arg.Scopes, apiKey.Scopes)
+	require.False(t, apiKey.IsRevoked)
+	require.False(t, apiKey.LastUsedAt.Valid)
+	require.WithinDuration(t, arg.ExpiredAt, apiKey.ExpiredAt, time.Second)
+
+	return apiKey
+}
+
+func TestListAPIKeys(t *testing.T) {
+	user := createRandomUser(t)
+	apiKey1 := createRandomAPIKey(t, user)
+	apiKey2 := createRandomAPIKey(t, user)
+	createRandomAPIKey(t, createRandomUser(t))
+
+	apiKeys, err := testQueries.ListAPIKeys(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, apiKeys, 2)
+	require.Equal(t, apiKey2.ID, apiKeys[0].ID)
+	require.Equal(t, apiKey1.ID, apiKeys[1].ID)
+}
+
+func TestAPIKeyAuthState(t *testing.T) {
+	user := createRandomUser(t)
+	apiKey := createRandomAPIKey(t, user)
+
+	state, err := APIKeyAuthState(context.Background(), testQueries, apiKey.HashedKey)
+	require.NoError(t, err)
+	require.Equal(t, apiKey.ID, state.ID)
+	require.Equal(t, user.Username, state.Username)
+	require.Equal(t, user.Role, state.Role)
+	require.Equal(t, apiKey.Scopes, state.Scopes)
+	require.False(t, state.IsRevoked)
+
+	apiKeys, err := testQueries.ListAPIKeys(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.True(t, apiKeys[0].LastUsedAt.Valid)
+
+	// another user can't revoke the key
+	_, err = testQueries.RevokeAPIKey(context.Background(), RevokeAPIKeyParams{
+		ID:       apiKey.ID,
+		Username: createRandomUser(t).Username,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	revoked, err := testQueries.RevokeAPIKey(context.Background(), RevokeAPIKeyParams{
+		ID:       apiKey.ID,
+		Username: user.Username,
+	})
+	require.NoError(t, err)
+	require.True(t, revoked.IsRevoked)
+
+	state, err = APIKeyAuthState(context.Background(), testQueries, apiKey.HashedKey)
+	require.NoError(t, err)
+	require.True(t, state.IsRevoked)
+
+	// an unknown key counts as revoked
+	state, err = APIKeyAuthState(context.Background(), testQueries, util.HashAPIKey(util.RandomString(32)))
+	require.NoError(t, err)
+	require.True(t, state.IsRevoked)
+}
@@ -0,0 +1,26 @@
+package db
+
+import (
+	"context"
+
+	"github.com/sangketkit01/simple-bank/util"
+)
+
+// LoadCurrencies reads the currencies table into the currency registry used for validation
+func LoadCurrencies(ctx context.Context, q Querier) error {
+	rows, err := q.ListCurrencies(ctx)
+	if err != nil {
+		return err
+	}
+
+	currencies := make([]util.Currency, len(rows))
+	for i, row := range rows {
+		currencies[i] = util.Currency{
+			Code:      row.Code,
+			MinorUnit: row.MinorUnit,
+			Enabled:   row.IsEnabled,
+		}
+	}
+
+	return util.SetCurrencies(currencies)
+}
@@ -0,0 +1,88 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.20.0
+// source: currency.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createCurrency = `-- name: CreateCurrency :one
+INSERT INTO currencies(
+    code,
+    minor_unit,
+    is_enabled
+) VALUES(
+    $1, $2, $3
+) RETURNING code, minor_unit, is_enabled, created_at
+`
+
+type CreateCurrencyParams struct {
+	Code      string `json:"code"`
+	MinorUnit int32  `json:"minor_unit"`
+	IsEnabled bool   `json:"is_enabled"`
+}
+
+func (q *Queries) CreateCurrency(ctx context.Context, arg CreateCurrencyParams) (Currency, error) {
+	row := q.db.QueryRowContext(ctx, createCurrency, arg.Code, arg.MinorUnit, arg.IsEnabled)
+	var i Currency
+	err := row.Scan(
+		&i.Code,
+		&i.MinorUnit,
+		&i.IsEnabled,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getCurrency = `-- name: GetCurrency :one
+SELECT code, minor_unit, is_enabled, created_at FROM currencies
+WHERE code = $1 LIMIT 1
+`
+
+func (q *Queries) GetCurrency(ctx context.Context, code string) (Currency, error) {
+	row := q.db.QueryRowContext(ctx, getCurrency, code)
+	var i Currency
+	err := row.Scan(
+		&i.Code,
+		&i.MinorUnit,
+		&i.IsEnabled,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listCurrencies = `-- name: ListCurrencies :many
+SELECT code, minor_unit, is_enabled, created_at FROM currencies
+ORDER BY code
+`
+
+func (q *Queries) ListCurrencies(ctx context.Context) ([]Currency, error) {
+	rows, err := q.db.QueryContext(ctx, listCurrencies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Currency{}
+	for rows.Next() {
+		var i Currency
+		if err := rows.Scan(
+			&i.Code,
+			&i.MinorUnit,
+			&i.IsEnabled,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
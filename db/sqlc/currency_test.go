@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomCurrency(t *testing.T) Currency {
+	arg := CreateCurrencyParams{
+		Code:      strings.ToUpper(util.RandomString(3)),
+		MinorUnit: int32(util.RandomInt(0, 2)),
+		IsEnabled: true,
+	}
+
+	currency, err := testQueries.CreateCurrency(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Code, currency.Code)
+	require.Equal(t, arg.MinorUnit, currency.MinorUnit)
+	require.Equal(t, arg.IsEnabled, currency.IsEnabled)
+	require.NotZero(t, currency.CreatedAt)
+
+	return currency
+}
+
+func TestLoadCurrencies(t *testing.T) {
+	currency := createRandomCurrency(t)
+
+	require.NoError(t, LoadCurrencies(context.Background(), testQueries))
+	require.True(t, util.IsSupportedCurrency(currency.Code))
+	require.True(t, util.IsSupportedCurrency("JPY"))
+	require.Error(t, util.CheckAmountPrecision(1050, "JPY"))
+}
@@ -0,0 +1,8 @@
+package db
+
+// Directions accepted by ListEntries and ListTransfers, seen from the listed account.
+// An empty direction lists both.
+const (
+	DirectionIncoming = "incoming"
+	DirectionOutgoing = "outgoing"
+)
@@ -55,20 +55,37 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 
 const listEntries = `-- name: ListEntries :many
 SELECT id, account_id, amount, created_at FROM entries
-WHERE account_id = $1
-ORDER BY id
-LIMIT $2
-OFFSET $3
+WHERE
+    account_id = $1 AND
+    ($2::timestamp IS NULL OR created_at >= $2) AND
+    ($3::timestamp IS NULL OR created_at < $3) AND
+    ($4::varchar <> 'incoming' OR amount > 0) AND
+    ($4::varchar <> 'outgoing' OR amount < 0) AND
+    ($5::bigint IS NULL OR (created_at, id) < ($6::timestamp, $5))
+ORDER BY created_at DESC, id DESC
+LIMIT $7
 `
 
 type ListEntriesParams struct {
-	AccountID sql.NullInt64 `json:"account_id"`
-	Limit     int32         `json:"limit"`
-	Offset    int32         `json:"offset"`
+	AccountID       sql.NullInt64 `json:"account_id"`
+	StartTime       sql.NullTime  `json:"start_time"`
+	EndTime         sql.NullTime  `json:"end_time"`
+	Direction       string        `json:"direction"`
+	CursorID        sql.NullInt64 `json:"cursor_id"`
+	CursorCreatedAt sql.NullTime  `json:"cursor_created_at"`
+	PageSize        int32         `json:"page_size"`
 }
 
 func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
-	rows, err := q.db.QueryContext(ctx, listEntries, arg.AccountID, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listEntries,
+		arg.AccountID,
+		arg.StartTime,
+		arg.EndTime,
+		arg.Direction,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
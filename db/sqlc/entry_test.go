@@ -53,9 +53,8 @@ func TestListsEntry(t *testing.T) {
 	}
 
 	arg := ListEntriesParams{
-		AccountID: sql.NullInt64{Int64: account.ID,Valid: true},
-		Limit:     5,
-		Offset:    5,
+		AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+		PageSize:  5,
 	}
 
 	entries, err := testQueries.ListEntries(context.Background(), arg)
@@ -65,4 +64,46 @@ func TestListsEntry(t *testing.T) {
 	for _, entry := range entries {
 		require.NotEmpty(t, entry)
 	}
+
+	// the next page continues right after the last entry of this one
+	last := entries[len(entries)-1]
+	arg.CursorID = sql.NullInt64{Int64: last.ID, Valid: true}
+	arg.CursorCreatedAt = sql.NullTime{Time: last.CreatedAt, Valid: true}
+	nextEntries, err := testQueries.ListEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, nextEntries, 5)
+	require.Less(t, nextEntries[0].ID, last.ID)
+}
+
+func TestListEntriesByDirection(t *testing.T) {
+	account := createRandomAccount(t)
+	for _, amount := range []int64{10, -10, 20, -20} {
+		_, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{
+			AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+			Amount:    amount,
+		})
+		require.NoError(t, err)
+	}
+
+	incoming, err := testQueries.ListEntries(context.Background(), ListEntriesParams{
+		AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+		Direction: DirectionIncoming,
+		PageSize:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, incoming, 2)
+	for _, entry := range incoming {
+		require.Positive(t, entry.Amount)
+	}
+
+	outgoing, err := testQueries.ListEntries(context.Background(), ListEntriesParams{
+		AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+		Direction: DirectionOutgoing,
+		PageSize:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, outgoing, 2)
+	for _, entry := range outgoing {
+		require.Negative(t, entry.Amount)
+	}
 }
@@ -5,15 +5,20 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
 )
 
 const (
 	ForeignKeyViolation = "23503"
 	UniqueViolation     = "23505"
+	CheckViolation      = "23514"
 )
 
 var ErrRecordNotFound = pgx.ErrNoRows
 
+// ErrInsufficientFunds is returned when a transfer would overdraw the source account
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
 var ErrUniqueViolation = &pgconn.PgError{
 	Code: UniqueViolation,
 }
@@ -23,5 +28,10 @@ func ErrorCode(err error) string {
 	if errors.As(err, &pgErr) {
 		return pgErr.Code
 	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return string(pqErr.Code)
+	}
 	return ""
 }
\ No newline at end of file
@@ -0,0 +1,82 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.20.0
+// source: exchange_rate.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const createExchangeRate = `-- name: CreateExchangeRate :one
+INSERT INTO exchange_rates(
+    base_currency,
+    quote_currency,
+    rate,
+    source,
+    valid_from
+) VALUES(
+    $1, $2, $3, $4, $5
+) RETURNING id, base_currency, quote_currency, rate, source, valid_from, created_at
+`
+
+type CreateExchangeRateParams struct {
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	Rate          string    `json:"rate"`
+	Source        string    `json:"source"`
+	ValidFrom     time.Time `json:"valid_from"`
+}
+
+func (q *Queries) CreateExchangeRate(ctx context.Context, arg CreateExchangeRateParams) (ExchangeRate, error) {
+	row := q.db.QueryRowContext(ctx, createExchangeRate,
+		arg.BaseCurrency,
+		arg.QuoteCurrency,
+		arg.Rate,
+		arg.Source,
+		arg.ValidFrom,
+	)
+	var i ExchangeRate
+	err := row.Scan(
+		&i.ID,
+		&i.BaseCurrency,
+		&i.QuoteCurrency,
+		&i.Rate,
+		&i.Source,
+		&i.ValidFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLatestExchangeRate = `-- name: GetLatestExchangeRate :one
+SELECT id, base_currency, quote_currency, rate, source, valid_from, created_at FROM exchange_rates
+WHERE
+    base_currency = $1 AND
+    quote_currency = $2 AND
+    valid_from <= now()
+ORDER BY valid_from DESC, id DESC
+LIMIT 1
+`
+
+type GetLatestExchangeRateParams struct {
+	BaseCurrency  string `json:"base_currency"`
+	QuoteCurrency string `json:"quote_currency"`
+}
+
+func (q *Queries) GetLatestExchangeRate(ctx context.Context, arg GetLatestExchangeRateParams) (ExchangeRate, error) {
+	row := q.db.QueryRowContext(ctx, getLatestExchangeRate, arg.BaseCurrency, arg.QuoteCurrency)
+	var i ExchangeRate
+	err := row.Scan(
+		&i.ID,
+		&i.BaseCurrency,
+		&i.QuoteCurrency,
+		&i.Rate,
+		&i.Source,
+		&i.ValidFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomExchangeRate(t *testing.T, baseCurrency, quoteCurrency string, rate string, validFrom time.Time) ExchangeRate {
+	arg := CreateExchangeRateParams{
+		BaseCurrency:  baseCurrency,
+		QuoteCurrency: quoteCurrency,
+		Rate:          rate,
+		Source:        "test",
+		ValidFrom:     validFrom,
+	}
+
+	exchangeRate, err := testQueries.CreateExchangeRate(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, exchangeRate.ID)
+	require.Equal(t, arg.BaseCurrency, exchangeRate.BaseCurrency)
+	require.Equal(t, arg.QuoteCurrency, exchangeRate.QuoteCurrency)
+	require.Equal(t, arg.Source, exchangeRate.Source)
+
+	return exchangeRate
+}
+
+func TestGetLatestExchangeRate(t *testing.T) {
+	// a fresh pair, so rates of other tests don't interfere
+	baseCurrency := createRandomCurrency(t).Code
+	quoteCurrency := createRandomCurrency(t).Code
+
+	createRandomExchangeRate(t, baseCurrency, quoteCurrency, "1.1000000000", time.Now().Add(-time.Hour))
+	current := createRandomExchangeRate(t, baseCurrency, quoteCurrency, "1.2000000000", time.Now().Add(-time.Minute))
+	createRandomExchangeRate(t, baseCurrency, quoteCurrency, "1.3000000000", time.Now().Add(time.Hour))
+
+	exchangeRate, err := testQueries.GetLatestExchangeRate(context.Background(), GetLatestExchangeRateParams{
+		BaseCurrency:  baseCurrency,
+		QuoteCurrency: quoteCurrency,
+	})
+	require.NoError(t, err)
+	require.Equal(t, current.ID, exchangeRate.ID)
+}
+
+func TestTransferTxWithQuote(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 1000)
+	account2 := createRandomAccount(t)
+	exchangeRate := createRandomExchangeRate(t, account1.Currency, account2.Currency, "1.5000000000", time.Now().Add(-time.Minute))
+
+	quote, err := testQueries.CreateTransferQuote(context.Background(), CreateTransferQuoteParams{
+		ID:             uuid.New(),
+		Username:       account1.Owner,
+		FromAccountID:  account1.ID,
+		ToAccountID:    account2.ID,
+		Amount:         100,
+		CreditAmount:   150,
+		ExchangeRateID: exchangeRate.ID,
+		ExchangeRate:   exchangeRate.Rate,
+		ExpiredAt:      time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	arg := TransferParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+		QuoteID:       uuid.NullUUID{UUID: quote.ID, Valid: true},
+		Username:      account1.Owner,
+	}
+
+	// a quote made for another amount is rejected and stays usable
+	mismatch := arg
+	mismatch.Amount = 99
+	_, err = store.TransferTx(context.Background(), mismatch)
+	require.ErrorIs(t, err, ErrQuoteMismatch)
+
+	result, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), result.Transfer.Amount)
+	require.Equal(t, int64(150), result.Transfer.CreditAmount)
+	require.Equal(t, quote.ID, result.Transfer.QuoteID.UUID)
+	require.Equal(t, int64(-100), result.FromEntry.Amount)
+	require.Equal(t, int64(150), result.ToEntry.Amount)
+	require.Equal(t, account1.Balance-100, result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+150, result.ToAccount.Balance)
+
+	// a quote can only be used once
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInvalidQuote)
+}
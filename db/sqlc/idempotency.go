@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultIdempotencyKeyDuration is how long an idempotency key is remembered
+// when TransferParams doesn't specify a duration
+const DefaultIdempotencyKeyDuration = 24 * time.Hour
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is replayed with a different request
+var ErrIdempotencyKeyReused = errors.New("idempotency key has already been used for a different request")
+
+// transferRequestHash fingerprints the parts of a transfer that must match on replay.
+// The quote is only part of it when set, so keys of same currency transfers keep their hash.
+func transferRequestHash(arg TransferParams) string {
+	request := fmt.Sprintf("%d:%d:%d", arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	if arg.QuoteID.Valid {
+		request += ":" + arg.QuoteID.UUID.String()
+	}
+
+	sum := sha256.Sum256([]byte(request))
+	return hex.EncodeToString(sum[:])
+}
+
+// reserveIdempotencyKey reserves the idempotency key of the transfer for the current transaction.
+// If the key was already used by a completed transfer it loads the original result into
+// result and reports replayed = true.
+func reserveIdempotencyKey(ctx context.Context, q *Queries, arg TransferParams, result *TransferTxResult) (replayed bool, err error) {
+	duration := arg.IdempotencyKeyDuration
+	if duration <= 0 {
+		duration = DefaultIdempotencyKeyDuration
+	}
+
+	requestHash := transferRequestHash(arg)
+	_, err = q.ClaimIdempotencyKey(ctx, ClaimIdempotencyKeyParams{
+		Username:       arg.Username,
+		IdempotencyKey: arg.IdempotencyKey,
+		RequestHash:    requestHash,
+		ExpiredAt:      time.Now().Add(duration),
+	})
+	if err == nil {
+		return false, nil
+	}
+	if err != sql.ErrNoRows {
+		return false, err
+	}
+
+	// the key is still within its retention window: replay the stored result
+	key, err := q.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{
+		Username:       arg.Username,
+		IdempotencyKey: arg.IdempotencyKey,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if key.RequestHash != requestHash {
+		return false, ErrIdempotencyKeyReused
+	}
+
+	if err := json.Unmarshal(key.Response, result); err != nil {
+		return false, fmt.Errorf("cannot decode stored transfer result: %w", err)
+	}
+	return true, nil
+}
+
+// storeIdempotencyKeyResponse stores the result of the transfer with its idempotency key
+func storeIdempotencyKeyResponse(ctx context.Context, q *Queries, arg TransferParams, result TransferTxResult) error {
+	response, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("cannot encode transfer result: %w", err)
+	}
+
+	_, err = q.UpdateIdempotencyKeyResponse(ctx, UpdateIdempotencyKeyResponseParams{
+		Username:       arg.Username,
+		IdempotencyKey: arg.IdempotencyKey,
+		TransferID:     sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+		Response:       response,
+	})
+	return err
+}
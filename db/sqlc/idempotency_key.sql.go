@@ -0,0 +1,124 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.20.0
+// source: idempotency_key.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+const claimIdempotencyKey = `-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys(
+    username,
+    idempotency_key,
+    request_hash,
+    expired_at
+) VALUES(
+    $1, $2, $3, $4
+)
+ON CONFLICT (username, idempotency_key) DO UPDATE
+SET
+    request_hash = EXCLUDED.request_hash,
+    transfer_id = NULL,
+    response = '{}',
+    created_at = now(),
+    expired_at = EXCLUDED.expired_at
+WHERE
+    idempotency_keys.expired_at <= now()
+RETURNING username, idempotency_key, request_hash, transfer_id, response, created_at, expired_at
+`
+
+type ClaimIdempotencyKeyParams struct {
+	Username       string    `json:"username"`
+	IdempotencyKey string    `json:"idempotency_key"`
+	RequestHash    string    `json:"request_hash"`
+	ExpiredAt      time.Time `json:"expired_at"`
+}
+
+func (q *Queries) ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, claimIdempotencyKey,
+		arg.Username,
+		arg.IdempotencyKey,
+		arg.RequestHash,
+		arg.ExpiredAt,
+	)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Username,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+		&i.TransferID,
+		&i.Response,
+		&i.CreatedAt,
+		&i.ExpiredAt,
+	)
+	return i, err
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT username, idempotency_key, request_hash, transfer_id, response, created_at, expired_at FROM idempotency_keys
+WHERE username = $1 AND idempotency_key = $2 LIMIT 1
+`
+
+type GetIdempotencyKeyParams struct {
+	Username       string `json:"username"`
+	IdempotencyKey string `json:"idempotency_key"`
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, getIdempotencyKey, arg.Username, arg.IdempotencyKey)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Username,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+		&i.TransferID,
+		&i.Response,
+		&i.CreatedAt,
+		&i.ExpiredAt,
+	)
+	return i, err
+}
+
+const updateIdempotencyKeyResponse = `-- name: UpdateIdempotencyKeyResponse :one
+UPDATE idempotency_keys
+SET
+    transfer_id = $1,
+    response = $2
+WHERE
+    username = $3 AND
+    idempotency_key = $4
+RETURNING username, idempotency_key, request_hash, transfer_id, response, created_at, expired_at
+`
+
+type UpdateIdempotencyKeyResponseParams struct {
+	TransferID     sql.NullInt64   `json:"transfer_id"`
+	Response       json.RawMessage `json:"response"`
+	Username       string          `json:"username"`
+	IdempotencyKey string          `json:"idempotency_key"`
+}
+
+func (q *Queries) UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, updateIdempotencyKeyResponse,
+		arg.TransferID,
+		arg.Response,
+		arg.Username,
+		arg.IdempotencyKey,
+	)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Username,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+		&i.TransferID,
+		&i.Response,
+		&i.CreatedAt,
+		&i.ExpiredAt,
+	)
+	return i, err
+}
@@ -6,6 +6,7 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +18,47 @@ type Account struct {
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
+	// sum of the active holds, the available balance is balance - held_amount
+	HeldAmount int64 `json:"held_amount"`
+}
+
+type AccountHold struct {
+	ID          int64  `json:"id"`
+	Owner       string `json:"owner"`
+	AccountID   int64  `json:"account_id"`
+	ToAccountID int64  `json:"to_account_id"`
+	// reserved on account_id until the hold is captured, released or expires
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	// active, captured, released or expired
+	Status string `json:"status"`
+	// transferred to to_account_id by the capture, the rest of the amount is released
+	CapturedAmount int64         `json:"captured_amount"`
+	TransferID     sql.NullInt64 `json:"transfer_id"`
+	ExpiresAt      time.Time     `json:"expires_at"`
+	SettledAt      sql.NullTime  `json:"settled_at"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+type ApiKey struct {
+	ID         int64        `json:"id"`
+	Username   string       `json:"username"`
+	Name       string       `json:"name"`
+	HashedKey  string       `json:"hashed_key"`
+	Scopes     []string     `json:"scopes"`
+	IsRevoked  bool         `json:"is_revoked"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	ExpiredAt  time.Time    `json:"expired_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type Currency struct {
+	// ISO 4217 currency code
+	Code string `json:"code"`
+	// number of decimal places of the currency, amounts are stored in hundredths so it is at most 2
+	MinorUnit int32     `json:"minor_unit"`
+	IsEnabled bool      `json:"is_enabled"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Entry struct {
@@ -27,6 +69,85 @@ type Entry struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type ExchangeRate struct {
+	ID            int64     `json:"id"`
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	Rate          string    `json:"rate"`
+	Source        string    `json:"source"`
+	ValidFrom     time.Time `json:"valid_from"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type IdempotencyKey struct {
+	Username       string          `json:"username"`
+	IdempotencyKey string          `json:"idempotency_key"`
+	RequestHash    string          `json:"request_hash"`
+	TransferID     sql.NullInt64   `json:"transfer_id"`
+	Response       json.RawMessage `json:"response"`
+	CreatedAt      time.Time       `json:"created_at"`
+	ExpiredAt      time.Time       `json:"expired_at"`
+}
+
+type MfaChallenge struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	Attempts  int32     `json:"attempts"`
+	IsUsed    bool      `json:"is_used"`
+	ExpiredAt time.Time `json:"expired_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PasswordReset struct {
+	ID         int64     `json:"id"`
+	Username   string    `json:"username"`
+	Email      string    `json:"email"`
+	SecretCode string    `json:"secret_code"`
+	IsUsed     bool      `json:"is_used"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiredAt  time.Time `json:"expired_at"`
+}
+
+type RecoveryCode struct {
+	ID         int64        `json:"id"`
+	Username   string       `json:"username"`
+	HashedCode string       `json:"hashed_code"`
+	UsedAt     sql.NullTime `json:"used_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type ScheduledTransfer struct {
+	ID            int64  `json:"id"`
+	Owner         string `json:"owner"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	// must be positive
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	// RRULE subset: FREQ=DAILY|WEEKLY|MONTHLY with optional INTERVAL, and COUNT or UNTIL
+	Schedule  string    `json:"schedule"`
+	StartsAt  time.Time `json:"starts_at"`
+	NextRunAt time.Time `json:"next_run_at"`
+	// index of next_run_at among the occurrences of the schedule
+	NextOccurrence int32 `json:"next_occurrence"`
+	// active, paused, suspended, completed or cancelled
+	Status string `json:"status"`
+	// runs in a row that failed for insufficient funds
+	FailureCount int32     `json:"failure_count"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ScheduledTransferRun struct {
+	ID                  int64     `json:"id"`
+	ScheduledTransferID int64     `json:"scheduled_transfer_id"`
+	ScheduledAt         time.Time `json:"scheduled_at"`
+	// succeeded or failed
+	Status     string        `json:"status"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	Error      string        `json:"error"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
 type Session struct {
 	ID           uuid.UUID `json:"id"`
 	Username     string    `json:"username"`
@@ -36,6 +157,8 @@ type Session struct {
 	IsBlock      bool      `json:"is_block"`
 	ExpiredAt    time.Time `json:"expired_at"`
 	CreatedAt    time.Time `json:"created_at"`
+	FamilyID     uuid.UUID `json:"family_id"`
+	IsRotated    bool      `json:"is_rotated"`
 }
 
 type Transfer struct {
@@ -43,8 +166,29 @@ type Transfer struct {
 	FromAccountID sql.NullInt64 `json:"from_account_id"`
 	ToAccountID   sql.NullInt64 `json:"to_account_id"`
 	// It must be positive
-	Amount    int64     `json:"amount"`
-	CreatedAt time.Time `json:"created_at"`
+	Amount       int64         `json:"amount"`
+	CreatedAt    time.Time     `json:"created_at"`
+	CreditAmount int64         `json:"credit_amount"`
+	ExchangeRate string        `json:"exchange_rate"`
+	QuoteID      uuid.NullUUID `json:"quote_id"`
+	// the transfer this one reverses, fully or partially
+	ReversalOf sql.NullInt64 `json:"reversal_of"`
+	// why the transfer was reversed, required for reversals
+	Reason string `json:"reason"`
+}
+
+type TransferQuote struct {
+	ID             uuid.UUID `json:"id"`
+	Username       string    `json:"username"`
+	FromAccountID  int64     `json:"from_account_id"`
+	ToAccountID    int64     `json:"to_account_id"`
+	Amount         int64     `json:"amount"`
+	CreditAmount   int64     `json:"credit_amount"`
+	ExchangeRateID int64     `json:"exchange_rate_id"`
+	ExchangeRate   string    `json:"exchange_rate"`
+	IsUsed         bool      `json:"is_used"`
+	ExpiredAt      time.Time `json:"expired_at"`
+	CreatedAt      time.Time `json:"created_at"`
 }
 
 type User struct {
@@ -55,6 +199,18 @@ type User struct {
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
 	IsEmailVerified   bool      `json:"is_email_verified"`
+	Role              string    `json:"role"`
+	FailedLoginCount  int32     `json:"failed_login_count"`
+	LockedUntil       time.Time `json:"locked_until"`
+}
+
+type UserTotp struct {
+	Username     string       `json:"username"`
+	Secret       string       `json:"secret"`
+	IsConfirmed  bool         `json:"is_confirmed"`
+	LastUsedStep int64        `json:"last_used_step"`
+	CreatedAt    time.Time    `json:"created_at"`
+	ConfirmedAt  sql.NullTime `json:"confirmed_at"`
 }
 
 type VerifyEmail struct {
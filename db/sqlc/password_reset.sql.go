@@ -0,0 +1,87 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.20.0
+// source: password_reset.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createPasswordReset = `-- name: CreatePasswordReset :one
+INSERT INTO password_resets(
+    username,
+    email,
+    secret_code
+) VALUES(
+    $1, $2, $3
+) RETURNING id, username, email, secret_code, is_used, created_at, expired_at
+`
+
+type CreatePasswordResetParams struct {
+	Username   string `json:"username"`
+	Email      string `json:"email"`
+	SecretCode string `json:"secret_code"`
+}
+
+func (q *Queries) CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error) {
+	row := q.db.QueryRowContext(ctx, createPasswordReset, arg.Username, arg.Email, arg.SecretCode)
+	var i PasswordReset
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.SecretCode,
+		&i.IsUsed,
+		&i.CreatedAt,
+		&i.ExpiredAt,
+	)
+	return i, err
+}
+
+const invalidatePasswordResets = `-- name: InvalidatePasswordResets :exec
+UPDATE password_resets
+SET
+    is_used = TRUE
+WHERE
+    username = $1 AND
+    is_used = FALSE
+`
+
+func (q *Queries) InvalidatePasswordResets(ctx context.Context, username string) error {
+	_, err := q.db.ExecContext(ctx, invalidatePasswordResets, username)
+	return err
+}
+
+const usePasswordReset = `-- name: UsePasswordReset :one
+UPDATE password_resets
+SET 
+    is_used = TRUE
+WHERE 
+    id = $1 AND
+    secret_code = $2 AND
+    is_used = FALSE AND
+    expired_at > now()
+RETURNING id, username, email, secret_code, is_used, created_at, expired_at
+`
+
+type UsePasswordResetParams struct {
+	ID         int64  `json:"id"`
+	SecretCode string `json:"secret_code"`
+}
+
+func (q *Queries) UsePasswordReset(ctx context.Context, arg UsePasswordResetParams) (PasswordReset, error) {
+	row := q.db.QueryRowContext(ctx, usePasswordReset, arg.ID, arg.SecretCode)
+	var i PasswordReset
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.SecretCode,
+		&i.IsUsed,
+		&i.CreatedAt,
+		&i.ExpiredAt,
+	)
+	return i, err
+}
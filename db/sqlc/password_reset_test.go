@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPasswordReset(t *testing.T, user User) PasswordReset {
+	arg := CreatePasswordResetParams{
+		Username:   user.Username,
+		Email:      user.Email,
+		SecretCode: util.RandomString(32),
+	}
+
+	passwordReset, err := testQueries.CreatePasswordReset(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, passwordReset.Username)
+	require.Equal(t, arg.Email, passwordReset.Email)
+	require.Equal(t, arg.SecretCode, passwordReset.SecretCode)
+	require.False(t, passwordReset.IsUsed)
+	require.True(t, passwordReset.ExpiredAt.After(passwordReset.CreatedAt))
+
+	return passwordReset
+}
+
+func TestGetUserByEmail(t *testing.T) {
+	user := createRandomUser(t)
+
+	found, err := testQueries.GetUserByEmail(context.Background(), user.Email)
+	require.NoError(t, err)
+	require.Equal(t, user.Username, found.Username)
+
+	_, err = testQueries.GetUserByEmail(context.Background(), util.RandomEmail())
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestResetPasswordTx(t *testing.T) {
+	user := createRandomUser(t)
+	session := createRandomSession(t, user)
+	apiKey := createRandomAPIKey(t, user)
+	passwordReset := createRandomPasswordReset(t, user)
+	otherReset := createRandomPasswordReset(t, user)
+
+	store := NewStore(testDB)
+
+	_, err := testQueries.LockUser(context.Background(), LockUserParams{
+		Username:    user.Username,
+		LockedUntil: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	// a wrong secret code does not consume the reset
+	_, err = store.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+		ResetID:        passwordReset.ID,
+		SecretCode:     util.RandomString(32),
+		HashedPassword: "unused",
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+
+	result, err := store.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+		ResetID:        passwordReset.ID,
+		SecretCode:     passwordReset.SecretCode,
+		HashedPassword: hashedPassword,
+	})
+	require.NoError(t, err)
+	require.True(t, result.PasswordReset.IsUsed)
+	require.Equal(t, hashedPassword, result.User.HashedPassword)
+	require.True(t, result.User.PasswordChangedAt.After(user.PasswordChangedAt))
+	require.Contains(t, result.BlockedSessionIDs, session.ID)
+	require.Zero(t, result.User.FailedLoginCount)
+	require.True(t, result.User.LockedUntil.Before(time.Now()))
+
+	blockedSession, err := testQueries.GetSession(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.True(t, blockedSession.IsBlock)
+
+	apiKeys, err := testQueries.ListAPIKeys(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, apiKeys, 1)
+	require.Equal(t, apiKey.ID, apiKeys[0].ID)
+	require.True(t, apiKeys[0].IsRevoked)
+
+	// the reset is single use and other pending resets of the user are invalidated
+	_, err = store.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+		ResetID:        passwordReset.ID,
+		SecretCode:     passwordReset.SecretCode,
+		HashedPassword: hashedPassword,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = store.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+		ResetID:        otherReset.ID,
+		SecretCode:     otherReset.SecretCode,
+		HashedPassword: hashedPassword,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestCreatePasswordResetTx(t *testing.T) {
+	user := createRandomUser(t)
+	earlierReset := createRandomPasswordReset(t, user)
+
+	store := NewStore(testDB)
+	arg := CreatePasswordResetParams{
+		Username:   user.Username,
+		Email:      user.Email,
+		SecretCode: util.RandomString(32),
+	}
+	passwordReset, err := store.CreatePasswordResetTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, passwordReset.IsUsed)
+
+	// only the latest reset can be used
+	_, err = testQueries.UsePasswordReset(context.Background(), UsePasswordResetParams{
+		ID:         earlierReset.ID,
+		SecretCode: earlierReset.SecretCode,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = testQueries.UsePasswordReset(context.Background(), UsePasswordResetParams{
+		ID:         passwordReset.ID,
+		SecretCode: arg.SecretCode,
+	})
+	require.NoError(t, err)
+}
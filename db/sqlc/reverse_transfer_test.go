@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 1000)
+	account2 := fundAccount(t, createRandomAccount(t), 0)
+
+	original, err := store.TransferTx(context.Background(), TransferParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        600,
+	})
+	require.NoError(t, err)
+
+	// a partial refund
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.Transfer.ID,
+		Amount:     200,
+		Reason:     "damaged item",
+	})
+	require.NoError(t, err)
+	require.Equal(t, original.Transfer.ID, result.ReversedTransfer.ID)
+	require.Equal(t, original.Transfer.ID, result.Transfer.ReversalOf.Int64)
+	require.Equal(t, "damaged item", result.Transfer.Reason)
+	require.Equal(t, account2.ID, result.Transfer.FromAccountID.Int64)
+	require.Equal(t, account1.ID, result.Transfer.ToAccountID.Int64)
+	require.Equal(t, int64(200), result.Transfer.Amount)
+	require.Equal(t, int64(-200), result.FromEntry.Amount)
+	require.Equal(t, int64(200), result.ToEntry.Amount)
+	require.Equal(t, int64(600), result.ToAccount.Balance)
+	require.Equal(t, int64(400), result.FromAccount.Balance)
+
+	// more than what is left
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.Transfer.ID,
+		Amount:     500,
+		Reason:     "damaged item",
+	})
+	require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+
+	// the rest
+	result, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.Transfer.ID,
+		Reason:     "order cancelled",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(400), result.Transfer.Amount)
+	require.Equal(t, int64(1000), result.ToAccount.Balance)
+	require.Zero(t, result.FromAccount.Balance)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.Transfer.ID,
+		Reason:     "order cancelled",
+	})
+	require.ErrorIs(t, err, ErrTransferFullyReversed)
+
+	// a reversal cannot be reversed
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: result.Transfer.ID,
+		Reason:     "order cancelled",
+	})
+	require.ErrorIs(t, err, ErrReversalOfReversal)
+}
+
+func TestReverseTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 1000)
+	account2 := fundAccount(t, createRandomAccount(t), 0)
+	account3 := fundAccount(t, createRandomAccount(t), 0)
+
+	original, err := store.TransferTx(context.Background(), TransferParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        600,
+	})
+	require.NoError(t, err)
+
+	// the money has left the destination account meanwhile
+	_, err = store.TransferTx(context.Background(), TransferParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account3.ID,
+		Amount:        600,
+	})
+	require.NoError(t, err)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.Transfer.ID,
+		Reason:     "order cancelled",
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
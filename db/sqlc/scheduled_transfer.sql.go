@@ -0,0 +1,349 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.20.0
+// source: scheduled_transfer.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createScheduledTransfer = `-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers(
+    owner,
+    from_account_id,
+    to_account_id,
+    amount,
+    currency,
+    schedule,
+    starts_at,
+    next_run_at
+) VALUES(
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, owner, from_account_id, to_account_id, amount, currency, schedule, starts_at, next_run_at, next_occurrence, status, failure_count, created_at
+`
+
+type CreateScheduledTransferParams struct {
+	Owner         string    `json:"owner"`
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	Currency      string    `json:"currency"`
+	Schedule      string    `json:"schedule"`
+	StartsAt      time.Time `json:"starts_at"`
+	NextRunAt     time.Time `json:"next_run_at"`
+}
+
+func (q *Queries) CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error) {
+	row := q.db.QueryRowContext(ctx, createScheduledTransfer,
+		arg.Owner,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Currency,
+		arg.Schedule,
+		arg.StartsAt,
+		arg.NextRunAt,
+	)
+	var i ScheduledTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Schedule,
+		&i.StartsAt,
+		&i.NextRunAt,
+		&i.NextOccurrence,
+		&i.Status,
+		&i.FailureCount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createScheduledTransferRun = `-- name: CreateScheduledTransferRun :one
+INSERT INTO scheduled_transfer_runs(
+    scheduled_transfer_id,
+    scheduled_at,
+    status,
+    transfer_id,
+    error
+) VALUES(
+    $1, $2, $3, $4, $5
+) RETURNING id, scheduled_transfer_id, scheduled_at, status, transfer_id, error, created_at
+`
+
+type CreateScheduledTransferRunParams struct {
+	ScheduledTransferID int64         `json:"scheduled_transfer_id"`
+	ScheduledAt         time.Time     `json:"scheduled_at"`
+	Status              string        `json:"status"`
+	TransferID          sql.NullInt64 `json:"transfer_id"`
+	Error               string        `json:"error"`
+}
+
+func (q *Queries) CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRun, error) {
+	row := q.db.QueryRowContext(ctx, createScheduledTransferRun,
+		arg.ScheduledTransferID,
+		arg.ScheduledAt,
+		arg.Status,
+		arg.TransferID,
+		arg.Error,
+	)
+	var i ScheduledTransferRun
+	err := row.Scan(
+		&i.ID,
+		&i.ScheduledTransferID,
+		&i.ScheduledAt,
+		&i.Status,
+		&i.TransferID,
+		&i.Error,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getScheduledTransfer = `-- name: GetScheduledTransfer :one
+SELECT id, owner, from_account_id, to_account_id, amount, currency, schedule, starts_at, next_run_at, next_occurrence, status, failure_count, created_at FROM scheduled_transfers
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error) {
+	row := q.db.QueryRowContext(ctx, getScheduledTransfer, id)
+	var i ScheduledTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Schedule,
+		&i.StartsAt,
+		&i.NextRunAt,
+		&i.NextOccurrence,
+		&i.Status,
+		&i.FailureCount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getScheduledTransferForUpdate = `-- name: GetScheduledTransferForUpdate :one
+SELECT id, owner, from_account_id, to_account_id, amount, currency, schedule, starts_at, next_run_at, next_occurrence, status, failure_count, created_at FROM scheduled_transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetScheduledTransferForUpdate(ctx context.Context, id int64) (ScheduledTransfer, error) {
+	row := q.db.QueryRowContext(ctx, getScheduledTransferForUpdate, id)
+	var i ScheduledTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Schedule,
+		&i.StartsAt,
+		&i.NextRunAt,
+		&i.NextOccurrence,
+		&i.Status,
+		&i.FailureCount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listDueScheduledTransfers = `-- name: ListDueScheduledTransfers :many
+SELECT id, owner, from_account_id, to_account_id, amount, currency, schedule, starts_at, next_run_at, next_occurrence, status, failure_count, created_at FROM scheduled_transfers
+WHERE
+    status = 'active' AND
+    next_run_at <= now()
+ORDER BY next_run_at
+LIMIT $1
+`
+
+func (q *Queries) ListDueScheduledTransfers(ctx context.Context, limit int32) ([]ScheduledTransfer, error) {
+	rows, err := q.db.QueryContext(ctx, listDueScheduledTransfers, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ScheduledTransfer{}
+	for rows.Next() {
+		var i ScheduledTransfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.Currency,
+			&i.Schedule,
+			&i.StartsAt,
+			&i.NextRunAt,
+			&i.NextOccurrence,
+			&i.Status,
+			&i.FailureCount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScheduledTransferRuns = `-- name: ListScheduledTransferRuns :many
+SELECT id, scheduled_transfer_id, scheduled_at, status, transfer_id, error, created_at FROM scheduled_transfer_runs
+WHERE scheduled_transfer_id = $1
+ORDER BY scheduled_at DESC
+LIMIT $2
+`
+
+type ListScheduledTransferRunsParams struct {
+	ScheduledTransferID int64 `json:"scheduled_transfer_id"`
+	Limit               int32 `json:"limit"`
+}
+
+func (q *Queries) ListScheduledTransferRuns(ctx context.Context, arg ListScheduledTransferRunsParams) ([]ScheduledTransferRun, error) {
+	rows, err := q.db.QueryContext(ctx, listScheduledTransferRuns, arg.ScheduledTransferID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ScheduledTransferRun{}
+	for rows.Next() {
+		var i ScheduledTransferRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.ScheduledTransferID,
+			&i.ScheduledAt,
+			&i.Status,
+			&i.TransferID,
+			&i.Error,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScheduledTransfers = `-- name: ListScheduledTransfers :many
+SELECT id, owner, from_account_id, to_account_id, amount, currency, schedule, starts_at, next_run_at, next_occurrence, status, failure_count, created_at FROM scheduled_transfers
+WHERE owner = $1
+ORDER BY created_at DESC, id DESC
+`
+
+func (q *Queries) ListScheduledTransfers(ctx context.Context, owner string) ([]ScheduledTransfer, error) {
+	rows, err := q.db.QueryContext(ctx, listScheduledTransfers, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ScheduledTransfer{}
+	for rows.Next() {
+		var i ScheduledTransfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.Currency,
+			&i.Schedule,
+			&i.StartsAt,
+			&i.NextRunAt,
+			&i.NextOccurrence,
+			&i.Status,
+			&i.FailureCount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateScheduledTransfer = `-- name: UpdateScheduledTransfer :one
+UPDATE scheduled_transfers
+SET
+    amount = COALESCE($1, amount),
+    schedule = COALESCE($2, schedule),
+    starts_at = COALESCE($3, starts_at),
+    next_run_at = COALESCE($4, next_run_at),
+    next_occurrence = COALESCE($5, next_occurrence),
+    status = COALESCE($6, status),
+    failure_count = COALESCE($7, failure_count)
+WHERE id = $8
+RETURNING id, owner, from_account_id, to_account_id, amount, currency, schedule, starts_at, next_run_at, next_occurrence, status, failure_count, created_at
+`
+
+type UpdateScheduledTransferParams struct {
+	Amount         sql.NullInt64  `json:"amount"`
+	Schedule       sql.NullString `json:"schedule"`
+	StartsAt       sql.NullTime   `json:"starts_at"`
+	NextRunAt      sql.NullTime   `json:"next_run_at"`
+	NextOccurrence sql.NullInt32  `json:"next_occurrence"`
+	Status         sql.NullString `json:"status"`
+	FailureCount   sql.NullInt32  `json:"failure_count"`
+	ID             int64          `json:"id"`
+}
+
+func (q *Queries) UpdateScheduledTransfer(ctx context.Context, arg UpdateScheduledTransferParams) (ScheduledTransfer, error) {
+	row := q.db.QueryRowContext(ctx, updateScheduledTransfer,
+		arg.Amount,
+		arg.Schedule,
+		arg.StartsAt,
+		arg.NextRunAt,
+		arg.NextOccurrence,
+		arg.Status,
+		arg.FailureCount,
+		arg.ID,
+	)
+	var i ScheduledTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Schedule,
+		&i.StartsAt,
+		&i.NextRunAt,
+		&i.NextOccurrence,
+		&i.Status,
+		&i.FailureCount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomScheduledTransfer(t *testing.T, schedule string) ScheduledTransfer {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	startsAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Microsecond)
+	arg := CreateScheduledTransferParams{
+		Owner:         account1.Owner,
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+		Currency:      account1.Currency,
+		Schedule:      schedule,
+		StartsAt:      startsAt,
+		NextRunAt:     startsAt,
+	}
+
+	scheduledTransfer, err := testQueries.CreateScheduledTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, scheduledTransfer.ID)
+	require.Equal(t, arg.Owner, scheduledTransfer.Owner)
+	require.Equal(t, util.ScheduleActive, scheduledTransfer.Status)
+	require.Zero(t, scheduledTransfer.NextOccurrence)
+	require.Zero(t, scheduledTransfer.FailureCount)
+
+	return scheduledTransfer
+}
+
+func TestRecordScheduledTransferRunTx(t *testing.T) {
+	store := NewStore(testDB)
+	scheduledTransfer := createRandomScheduledTransfer(t, "FREQ=DAILY;COUNT=2")
+
+	result, err := store.RecordScheduledTransferRunTx(context.Background(), RecordScheduledTransferRunTxParams{
+		ScheduledTransferID: scheduledTransfer.ID,
+		ScheduledAt:         scheduledTransfer.NextRunAt,
+		Error:               "insufficient funds",
+		InsufficientFunds:   true,
+		MaxFailures:         3,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.RunFailed, result.Run.Status)
+	require.Equal(t, util.ScheduleActive, result.ScheduledTransfer.Status)
+	require.Equal(t, int32(1), result.ScheduledTransfer.FailureCount)
+	require.Equal(t, int32(1), result.ScheduledTransfer.NextOccurrence)
+	require.WithinDuration(t, scheduledTransfer.StartsAt.AddDate(0, 0, 1), result.ScheduledTransfer.NextRunAt, time.Second)
+
+	// the same run cannot be recorded twice
+	_, err = store.RecordScheduledTransferRunTx(context.Background(), RecordScheduledTransferRunTxParams{
+		ScheduledTransferID: scheduledTransfer.ID,
+		ScheduledAt:         scheduledTransfer.NextRunAt,
+		MaxFailures:         3,
+	})
+	require.ErrorIs(t, err, ErrScheduledRunStale)
+
+	account1, err := testQueries.GetAccount(context.Background(), scheduledTransfer.FromAccountID)
+	require.NoError(t, err)
+	account1 = fundAccount(t, account1, 1000)
+
+	arg := RecordScheduledTransferRunTxParams{
+		ScheduledTransferID: scheduledTransfer.ID,
+		ScheduledAt:         result.ScheduledTransfer.NextRunAt,
+		Transfer: &TransferParams{
+			FromAccountID: scheduledTransfer.FromAccountID,
+			ToAccountID:   scheduledTransfer.ToAccountID,
+			Amount:        scheduledTransfer.Amount,
+		},
+		MaxFailures: 3,
+	}
+	result, err = store.RecordScheduledTransferRunTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, util.RunSucceeded, result.Run.Status)
+	require.Equal(t, sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true}, result.Run.TransferID)
+	require.Equal(t, account1.Balance-scheduledTransfer.Amount, result.Transfer.FromAccount.Balance)
+	require.Equal(t, util.ScheduleCompleted, result.ScheduledTransfer.Status)
+	require.Zero(t, result.ScheduledTransfer.FailureCount)
+
+	// a stale run moves no money
+	_, err = store.RecordScheduledTransferRunTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrScheduledRunStale)
+
+	account1, err = testQueries.GetAccount(context.Background(), scheduledTransfer.FromAccountID)
+	require.NoError(t, err)
+	require.Equal(t, result.Transfer.FromAccount.Balance, account1.Balance)
+
+	runs, err := testQueries.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduledTransfer.ID,
+		Limit:               10,
+	})
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+}
+
+func TestRecordScheduledTransferRunTxSuspends(t *testing.T) {
+	store := NewStore(testDB)
+	scheduledTransfer := createRandomScheduledTransfer(t, "FREQ=WEEKLY")
+
+	result, err := store.RecordScheduledTransferRunTx(context.Background(), RecordScheduledTransferRunTxParams{
+		ScheduledTransferID: scheduledTransfer.ID,
+		ScheduledAt:         scheduledTransfer.NextRunAt,
+		Error:               "insufficient funds",
+		InsufficientFunds:   true,
+		MaxFailures:         1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.ScheduleSuspended, result.ScheduledTransfer.Status)
+	require.Equal(t, int32(1), result.ScheduledTransfer.FailureCount)
+}
+
+func TestRecordScheduledTransferRunTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+	scheduledTransfer := createRandomScheduledTransfer(t, "FREQ=WEEKLY")
+
+	account1, err := testQueries.GetAccount(context.Background(), scheduledTransfer.FromAccountID)
+	require.NoError(t, err)
+	fundAccount(t, account1, 0)
+
+	// the failed transfer rolls the run back, the schedule stays due
+	_, err = store.RecordScheduledTransferRunTx(context.Background(), RecordScheduledTransferRunTxParams{
+		ScheduledTransferID: scheduledTransfer.ID,
+		ScheduledAt:         scheduledTransfer.NextRunAt,
+		Transfer: &TransferParams{
+			FromAccountID: scheduledTransfer.FromAccountID,
+			ToAccountID:   scheduledTransfer.ToAccountID,
+			Amount:        scheduledTransfer.Amount,
+		},
+		MaxFailures: 3,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	runs, err := testQueries.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduledTransfer.ID,
+		Limit:               10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, runs)
+
+	unchanged, err := testQueries.GetScheduledTransfer(context.Background(), scheduledTransfer.ID)
+	require.NoError(t, err)
+	require.True(t, scheduledTransfer.NextRunAt.Equal(unchanged.NextRunAt))
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionAuthState returns what the tokens of the session are checked against, an unknown session counts as blocked
+func SessionAuthState(ctx context.Context, q Querier, sessionID uuid.UUID) (GetSessionAuthStateRow, error) {
+	state, err := q.GetSessionAuthState(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return GetSessionAuthStateRow{IsBlock: true}, nil
+		}
+		return GetSessionAuthStateRow{}, err
+	}
+
+	return state, nil
+}
+
+var (
+	ErrSessionBlocked       = errors.New("blocked session")
+	ErrSessionUserMismatch  = errors.New("incorrect session user")
+	ErrSessionTokenMismatch = errors.New("mismatched session token")
+	ErrSessionExpired       = errors.New("expired session")
+)
+
+type ValidRefreshSessionParams struct {
+	SessionID    uuid.UUID
+	Username     string
+	RefreshToken string
+}
+
+// ValidRefreshSession loads the session of a verified refresh token and checks the token against it.
+// A session that was already rotated means the token is used a second time, probably by whoever stole it:
+// the whole family is blocked and ErrRefreshTokenReused is returned with the blocked session ids, which the caller must revoke.
+// It returns sql.ErrNoRows if the session does not exist.
+func ValidRefreshSession(ctx context.Context, q Querier, arg ValidRefreshSessionParams) (Session, []uuid.UUID, error) {
+	session, err := q.GetSession(ctx, arg.SessionID)
+	if err != nil {
+		return session, nil, err
+	}
+
+	if session.IsBlock {
+		return session, nil, ErrSessionBlocked
+	}
+
+	if session.IsRotated {
+		blockedSessionIDs, err := q.BlockSessionFamily(ctx, session.FamilyID)
+		if err != nil {
+			return session, nil, err
+		}
+		return session, blockedSessionIDs, ErrRefreshTokenReused
+	}
+
+	if session.Username != arg.Username {
+		return session, nil, ErrSessionUserMismatch
+	}
+
+	if session.RefreshToken != arg.RefreshToken {
+		return session, nil, ErrSessionTokenMismatch
+	}
+
+	if time.Now().After(session.ExpiredAt) {
+		return session, nil, ErrSessionExpired
+	}
+
+	return session, nil, nil
+}
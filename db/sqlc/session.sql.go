@@ -12,6 +12,91 @@ import (
 	"github.com/google/uuid"
 )
 
+const blockSession = `-- name: BlockSession :one
+UPDATE sessions
+SET is_block = true
+WHERE id = $1
+RETURNING id, username, refresh_token, user_agent, client_ip, is_block, expired_at, created_at, family_id, is_rotated
+`
+
+func (q *Queries) BlockSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRowContext(ctx, blockSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlock,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.IsRotated,
+	)
+	return i, err
+}
+
+const blockSessionFamily = `-- name: BlockSessionFamily :many
+UPDATE sessions
+SET is_block = true
+WHERE family_id = $1 AND is_block = false
+RETURNING id
+`
+
+func (q *Queries) BlockSessionFamily(ctx context.Context, familyID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, blockSessionFamily, familyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const blockUserSessions = `-- name: BlockUserSessions :many
+UPDATE sessions
+SET is_block = true
+WHERE username = $1 AND is_block = false
+RETURNING id
+`
+
+func (q *Queries) BlockUserSessions(ctx context.Context, username string) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, blockUserSessions, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions(
     id,
@@ -20,10 +105,11 @@ INSERT INTO sessions(
     user_agent,
     client_ip,
     is_block,
-    expired_at
+    expired_at,
+    family_id
 ) VALUES(
-    $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, username, refresh_token, user_agent, client_ip, is_block, expired_at, created_at
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, username, refresh_token, user_agent, client_ip, is_block, expired_at, created_at, family_id, is_rotated
 `
 
 type CreateSessionParams struct {
@@ -34,6 +120,7 @@ type CreateSessionParams struct {
 	ClientIp     string    `json:"client_ip"`
 	IsBlock      bool      `json:"is_block"`
 	ExpiredAt    time.Time `json:"expired_at"`
+	FamilyID     uuid.UUID `json:"family_id"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
@@ -45,6 +132,7 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		arg.ClientIp,
 		arg.IsBlock,
 		arg.ExpiredAt,
+		arg.FamilyID,
 	)
 	var i Session
 	err := row.Scan(
@@ -56,12 +144,14 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.IsBlock,
 		&i.ExpiredAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.IsRotated,
 	)
 	return i, err
 }
 
 const getSession = `-- name: GetSession :one
-SELECT id, username, refresh_token, user_agent, client_ip, is_block, expired_at, created_at FROM sessions
+SELECT id, username, refresh_token, user_agent, client_ip, is_block, expired_at, created_at, family_id, is_rotated FROM sessions
 WHERE id = $1 LIMIT 1
 `
 
@@ -77,6 +167,96 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 		&i.IsBlock,
 		&i.ExpiredAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.IsRotated,
+	)
+	return i, err
+}
+
+const getSessionAuthState = `-- name: GetSessionAuthState :one
+SELECT sessions.is_block, users.password_changed_at
+FROM sessions
+JOIN users ON users.username = sessions.username
+WHERE sessions.id = $1 LIMIT 1
+`
+
+type GetSessionAuthStateRow struct {
+	IsBlock           bool      `json:"is_block"`
+	PasswordChangedAt time.Time `json:"password_changed_at"`
+}
+
+func (q *Queries) GetSessionAuthState(ctx context.Context, id uuid.UUID) (GetSessionAuthStateRow, error) {
+	row := q.db.QueryRowContext(ctx, getSessionAuthState, id)
+	var i GetSessionAuthStateRow
+	err := row.Scan(&i.IsBlock, &i.PasswordChangedAt)
+	return i, err
+}
+
+const listActiveSessions = `-- name: ListActiveSessions :many
+SELECT id, username, refresh_token, user_agent, client_ip, is_block, expired_at, created_at, family_id, is_rotated FROM sessions
+WHERE
+    username = $1 AND
+    is_block = false AND
+    is_rotated = false AND
+    expired_at > now()
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListActiveSessions(ctx context.Context, username string) ([]Session, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveSessions, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Session{}
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.RefreshToken,
+			&i.UserAgent,
+			&i.ClientIp,
+			&i.IsBlock,
+			&i.ExpiredAt,
+			&i.CreatedAt,
+			&i.FamilyID,
+			&i.IsRotated,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rotateSession = `-- name: RotateSession :one
+UPDATE sessions
+SET is_rotated = true
+WHERE id = $1 AND is_rotated = false
+RETURNING id, username, refresh_token, user_agent, client_ip, is_block, expired_at, created_at, family_id, is_rotated
+`
+
+func (q *Queries) RotateSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRowContext(ctx, rotateSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlock,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.IsRotated,
 	)
 	return i, err
 }
@@ -0,0 +1,201 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSession(t *testing.T, user User) Session {
+	id := uuid.New()
+	arg := CreateSessionParams{
+		ID:           id,
+		Username:     user.Username,
+		RefreshToken: util.RandomString(32),
+		UserAgent:    util.RandomString(10),
+		ClientIp:     "127.0.0.1",
+		IsBlock:      false,
+		ExpiredAt:    time.Now().Add(time.Hour),
+		FamilyID:     id,
+	}
+
+	session, err := testQueries.CreateSession(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, session)
+
+	require.Equal(t, arg.ID, session.ID)
+	require.Equal(t, arg.Username, session.Username)
+	require.Equal(t, arg.RefreshToken, session.RefreshToken)
+	require.Equal(t, arg.FamilyID, session.FamilyID)
+	require.False(t, session.IsBlock)
+	require.False(t, session.IsRotated)
+	require.NotZero(t, session.CreatedAt)
+
+	return session
+}
+
+func TestBlockSession(t *testing.T) {
+	user := createRandomUser(t)
+	session1 := createRandomSession(t, user)
+	session2 := createRandomSession(t, user)
+
+	blocked, err := testQueries.BlockSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.Equal(t, session1.ID, blocked.ID)
+	require.True(t, blocked.IsBlock)
+
+	other, err := testQueries.GetSession(context.Background(), session2.ID)
+	require.NoError(t, err)
+	require.False(t, other.IsBlock)
+}
+
+func TestBlockUserSessions(t *testing.T) {
+	user := createRandomUser(t)
+	otherUser := createRandomUser(t)
+
+	session1 := createRandomSession(t, user)
+	session2 := createRandomSession(t, user)
+	otherSession := createRandomSession(t, otherUser)
+
+	ids, err := testQueries.BlockUserSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uuid.UUID{session1.ID, session2.ID}, ids)
+
+	// already blocked sessions are not reported twice
+	ids, err = testQueries.BlockUserSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	session, err := testQueries.GetSession(context.Background(), otherSession.ID)
+	require.NoError(t, err)
+	require.False(t, session.IsBlock)
+}
+
+func TestRotateSessionTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+	session1 := createRandomSession(t, user)
+
+	newSession := func() CreateSessionParams {
+		return CreateSessionParams{
+			ID:           uuid.New(),
+			Username:     user.Username,
+			RefreshToken: util.RandomString(32),
+			UserAgent:    session1.UserAgent,
+			ClientIp:     session1.ClientIp,
+			ExpiredAt:    session1.ExpiredAt,
+		}
+	}
+
+	result, err := store.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		Session:    session1,
+		NewSession: newSession(),
+	})
+	require.NoError(t, err)
+	session2 := result.Session
+	require.Equal(t, session1.FamilyID, session2.FamilyID)
+	require.False(t, session2.IsRotated)
+	require.False(t, session2.IsBlock)
+
+	rotated, err := testQueries.GetSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.True(t, rotated.IsRotated)
+
+	// presenting the first refresh token again blocks the whole family
+	result, err = store.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		Session:    session1,
+		NewSession: newSession(),
+	})
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+	require.ElementsMatch(t, []uuid.UUID{session1.ID, session2.ID}, result.BlockedSessionIDs)
+
+	for _, id := range []uuid.UUID{session1.ID, session2.ID} {
+		session, err := testQueries.GetSession(context.Background(), id)
+		require.NoError(t, err)
+		require.True(t, session.IsBlock)
+	}
+}
+
+func TestListActiveSessions(t *testing.T) {
+	user := createRandomUser(t)
+	active := createRandomSession(t, user)
+	blocked := createRandomSession(t, user)
+
+	_, err := testQueries.BlockSession(context.Background(), blocked.ID)
+	require.NoError(t, err)
+
+	sessions, err := testQueries.ListActiveSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, active.ID, sessions[0].ID)
+}
+
+func TestSessionAuthState(t *testing.T) {
+	user := createRandomUser(t)
+	session := createRandomSession(t, user)
+
+	state, err := SessionAuthState(context.Background(), testQueries, session.ID)
+	require.NoError(t, err)
+	require.False(t, state.IsBlock)
+	require.WithinDuration(t, user.PasswordChangedAt, state.PasswordChangedAt, time.Second)
+
+	// an unknown session counts as blocked
+	state, err = SessionAuthState(context.Background(), testQueries, uuid.New())
+	require.NoError(t, err)
+	require.True(t, state.IsBlock)
+}
+
+func TestValidRefreshSession(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+	session1 := createRandomSession(t, user)
+
+	arg := ValidRefreshSessionParams{
+		SessionID:    session1.ID,
+		Username:     user.Username,
+		RefreshToken: session1.RefreshToken,
+	}
+	session, blockedSessionIDs, err := ValidRefreshSession(context.Background(), store, arg)
+	require.NoError(t, err)
+	require.Equal(t, session1.ID, session.ID)
+	require.Empty(t, blockedSessionIDs)
+
+	mismatched := arg
+	mismatched.RefreshToken = util.RandomString(32)
+	_, _, err = ValidRefreshSession(context.Background(), store, mismatched)
+	require.ErrorIs(t, err, ErrSessionTokenMismatch)
+
+	otherUser := arg
+	otherUser.Username = util.RandomOwner()
+	_, _, err = ValidRefreshSession(context.Background(), store, otherUser)
+	require.ErrorIs(t, err, ErrSessionUserMismatch)
+
+	result, err := store.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		Session: session1,
+		NewSession: CreateSessionParams{
+			ID:           uuid.New(),
+			Username:     user.Username,
+			RefreshToken: util.RandomString(32),
+			ExpiredAt:    session1.ExpiredAt,
+		},
+	})
+	require.NoError(t, err)
+
+	// the refresh token of the rotated session is a reuse, which blocks the whole family
+	_, blockedSessionIDs, err = ValidRefreshSession(context.Background(), store, arg)
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+	require.ElementsMatch(t, []uuid.UUID{session1.ID, result.Session.ID}, blockedSessionIDs)
+
+	_, _, err = ValidRefreshSession(context.Background(), store, ValidRefreshSessionParams{
+		SessionID:    result.Session.ID,
+		Username:     user.Username,
+		RefreshToken: result.Session.RefreshToken,
+	})
+	require.ErrorIs(t, err, ErrSessionBlocked)
+}
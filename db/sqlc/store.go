@@ -7,6 +7,8 @@ import (
 	"log"
 	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // Store provides all functions to execute db queries and transactions
@@ -15,6 +17,18 @@ type Store interface{
 	TransferTx(ctx context.Context, arg TransferParams) (TransferTxResult, error)
 	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
 	VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error)
+	RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error)
+	ConfirmTOTPTx(ctx context.Context, arg ConfirmTOTPTxParams) (ConfirmTOTPTxResult, error)
+	VerifyMFATx(ctx context.Context, arg VerifyMFATxParams) (MfaChallenge, error)
+	CreatePasswordResetTx(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error)
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error)
+	UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error)
+	RecordFailedLoginTx(ctx context.Context, arg RecordFailedLoginTxParams) (User, error)
+	RecordScheduledTransferRunTx(ctx context.Context, arg RecordScheduledTransferRunTxParams) (RecordScheduledTransferRunTxResult, error)
+	CreateHoldTx(ctx context.Context, arg CreateHoldTxParams) (CreateHoldTxResult, error)
+	CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error)
+	ReleaseHoldTx(ctx context.Context, arg ReleaseHoldTxParams) (ReleaseHoldTxResult, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error)
 }
 
 type SQLStore struct{
@@ -61,6 +75,15 @@ type TransferParams struct{
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID int64 `json:"to_account_id"`
 	Amount int64 `json:"amount"`
+	// QuoteID, when set, prices a transfer between accounts of different currencies:
+	// the quote of Username is used up and to_account_id is credited with its credit amount
+	QuoteID uuid.NullUUID `json:"quote_id"`
+
+	// Username and IdempotencyKey, when the key is set, make the transfer safe to retry:
+	// a replay within IdempotencyKeyDuration returns the original result instead of moving money again
+	Username string `json:"username"`
+	IdempotencyKey string `json:"idempotency_key"`
+	IdempotencyKeyDuration time.Duration `json:"idempotency_key_duration"`
 }
 
 // TransferTxResult is the result of the transfer transaction
@@ -81,48 +104,131 @@ func (store *SQLStore) TransferTx(ctx context.Context, arg TransferParams) (Tran
 	err := store.execTx(ctx, func (q *Queries) error  {
 		var err error
 
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: sql.NullInt64{Int64: arg.FromAccountID, Valid: true},
-			ToAccountID: sql.NullInt64{Int64: arg.ToAccountID, Valid: true},
-			Amount: arg.Amount,
-		})
-		if err != nil{
-			return err
+		if arg.IdempotencyKey != ""{
+			replayed, err := reserveIdempotencyKey(ctx, q, arg, &result)
+			if err != nil || replayed{
+				return err
+			}
 		}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: sql.NullInt64{Int64: arg.FromAccountID, Valid: true},
-			Amount: -arg.Amount,
-		})	
-
+		result, err = transfer(ctx, q, arg)
 		if err != nil{
 			return err
 		}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: sql.NullInt64{Int64: arg.ToAccountID, Valid: true},
-			Amount: arg.Amount,
-		})	
-
-		if err != nil{
-			return err
+		if arg.IdempotencyKey != ""{
+			return storeIdempotencyKeyResponse(ctx, q, arg, result)
 		}
 
-		// get account -> update its balance
-		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, result.ToAccount,err = addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
-		}else{
-			result.ToAccount, result.FromAccount,err = addMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
-		}
+		return nil
+	})
+
+	return result, err
+}
 
+// transfer moves the money of a transfer within the transaction of q:
+// it creates the transfer record and both entries, and updates the balances.
+// Other transactions use it to make a transfer as part of a larger change.
+func transfer(ctx context.Context, q *Queries, arg TransferParams) (TransferTxResult, error){
+	creditAmount, exchangeRate := arg.Amount, "1"
+	if arg.QuoteID.Valid{
+		quote, err := consumeTransferQuote(ctx, q, arg)
 		if err != nil{
-			return err
+			return TransferTxResult{}, err
 		}
+		creditAmount, exchangeRate = quote.CreditAmount, quote.ExchangeRate
+	}
 
-		return nil
+	return recordTransfer(ctx, q, CreateTransferParams{
+		FromAccountID: sql.NullInt64{Int64: arg.FromAccountID, Valid: true},
+		ToAccountID: sql.NullInt64{Int64: arg.ToAccountID, Valid: true},
+		Amount: arg.Amount,
+		CreditAmount: creditAmount,
+		ExchangeRate: exchangeRate,
+		QuoteID: arg.QuoteID,
 	})
+}
 
-	return result, err
+// recordTransfer debits arg.Amount from the source account and credits arg.CreditAmount to the destination account,
+// recording the transfer and both entries. The source account must have enough available balance.
+func recordTransfer(ctx context.Context, q *Queries, arg CreateTransferParams) (TransferTxResult, error){
+	var result TransferTxResult
+
+	fromAccountID, toAccountID := arg.FromAccountID.Int64, arg.ToAccountID.Int64
+
+	// lock both accounts in a consistent order before checking the balance,
+	// so that concurrent transfers between the same pair cannot deadlock
+	fromAccount, err := lockAccounts(ctx, q, fromAccountID, toAccountID)
+	if err != nil{
+		return result, err
+	}
+
+	if fromAccount.AvailableBalance() < arg.Amount{
+		return result, ErrInsufficientFunds
+	}
+
+	result.Transfer, err = q.CreateTransfer(ctx, arg)
+	if err != nil{
+		return result, err
+	}
+
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.FromAccountID,
+		Amount: -arg.Amount,
+	})	
+
+	if err != nil{
+		return result, err
+	}
+
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.ToAccountID,
+		Amount: arg.CreditAmount,
+	})	
+
+	if err != nil{
+		return result, err
+	}
+
+	// get account -> update its balance
+	if fromAccountID < toAccountID {
+		result.FromAccount, result.ToAccount,err = addMoney(ctx, q, fromAccountID, -arg.Amount, toAccountID, arg.CreditAmount)
+	}else{
+		result.ToAccount, result.FromAccount,err = addMoney(ctx, q, toAccountID, arg.CreditAmount, fromAccountID, -arg.Amount)
+	}
+
+	if err != nil{
+		if ErrorCode(err) == CheckViolation{
+			return result, ErrInsufficientFunds
+		}
+		return result, err
+	}
+
+	return result, nil
+}
+
+// lockAccounts locks both accounts of a transfer with SELECT ... FOR NO KEY UPDATE,
+// always taking the lower id first, and returns the source account.
+func lockAccounts(ctx context.Context, q *Queries, fromAccountID int64, toAccountID int64) (Account, error) {
+	firstID, secondID := fromAccountID, toAccountID
+	if firstID > secondID {
+		firstID, secondID = secondID, firstID
+	}
+
+	first, err := q.GetAccountForUpdate(ctx, firstID)
+	if err != nil {
+		return Account{}, err
+	}
+
+	second, err := q.GetAccountForUpdate(ctx, secondID)
+	if err != nil {
+		return Account{}, err
+	}
+
+	if first.ID == fromAccountID {
+		return first, nil
+	}
+	return second, nil
 }
 
 func addMoney(
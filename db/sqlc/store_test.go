@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/sangketkit01/simple-bank/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -13,11 +14,21 @@ func convertNullInt64ToInt64(data sql.NullInt64) int64{
 	return data.Int64
 }
 
+// fundAccount sets the balance of the account so transfers in a test cannot run out of money
+func fundAccount(t *testing.T, account Account, balance int64) Account {
+	account, err := testQueries.UpdateAccount(context.Background(), UpdateAccountParams{
+		ID:      account.ID,
+		Balance: balance,
+	})
+	require.NoError(t, err)
+	return account
+}
+
 func TestTransferTxDeadlock(t *testing.T){
 	store := NewStore(testDB)
 
-	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account1 := fundAccount(t, createRandomAccount(t), 1000)
+	account2 := fundAccount(t, createRandomAccount(t), 1000)
 	fmt.Println(">> before:",account1.Balance, account2.Balance)
 
 	// run n concurrent transfer transactions
@@ -71,7 +82,7 @@ func TestTransferTxDeadlock(t *testing.T){
 func TestTransferTx(t *testing.T){
 	store := NewStore(testDB)
 
-	account1 := createRandomAccount(t)
+	account1 := fundAccount(t, createRandomAccount(t), 1000)
 	account2 := createRandomAccount(t)
 	fmt.Println(">> before:",account1.Balance,account2.Balance)
 
@@ -172,4 +183,62 @@ func TestTransferTx(t *testing.T){
 	fmt.Println(">> after:",updateAccount1.Balance,updateAccount2.Balance)
 	require.Equal(t, account1.Balance - int64(n) * amount, updateAccount1.Balance)
 	require.Equal(t, account2.Balance + int64(n) * amount, updateAccount2.Balance)
-}
\ No newline at end of file
+}
+
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 10)
+	account2 := createRandomAccount(t)
+
+	result, err := store.TransferTx(context.Background(), TransferParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+	require.Empty(t, result.Transfer)
+
+	// nothing must have moved
+	updateAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updateAccount1.Balance)
+
+	updateAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, updateAccount2.Balance)
+}
+
+func TestTransferTxIdempotencyKey(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := fundAccount(t, createRandomAccount(t), 1000)
+	account2 := createRandomAccount(t)
+
+	arg := TransferParams{
+		FromAccountID:  account1.ID,
+		ToAccountID:    account2.ID,
+		Amount:         10,
+		Username:       account1.Owner,
+		IdempotencyKey: util.RandomString(16),
+	}
+
+	result1, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, result1.Transfer.ID)
+
+	// a retry with the same key returns the original result without moving money again
+	result2, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, result1.Transfer.ID, result2.Transfer.ID)
+	require.Equal(t, result1.FromAccount.Balance, result2.FromAccount.Balance)
+
+	updateAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-arg.Amount, updateAccount1.Balance)
+
+	// the same key with a different body is rejected
+	arg.Amount = 20
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrIdempotencyKeyReused)
+}
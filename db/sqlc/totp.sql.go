@@ -0,0 +1,285 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.20.0
+// source: totp.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const confirmUserTOTP = `-- name: ConfirmUserTOTP :one
+UPDATE user_totps
+SET
+    is_confirmed = true,
+    confirmed_at = now(),
+    last_used_step = $1
+WHERE username = $2 AND is_confirmed = false
+RETURNING username, secret, is_confirmed, last_used_step, created_at, confirmed_at
+`
+
+type ConfirmUserTOTPParams struct {
+	Step     int64  `json:"step"`
+	Username string `json:"username"`
+}
+
+func (q *Queries) ConfirmUserTOTP(ctx context.Context, arg ConfirmUserTOTPParams) (UserTotp, error) {
+	row := q.db.QueryRowContext(ctx, confirmUserTOTP, arg.Step, arg.Username)
+	var i UserTotp
+	err := row.Scan(
+		&i.Username,
+		&i.Secret,
+		&i.IsConfirmed,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+		&i.ConfirmedAt,
+	)
+	return i, err
+}
+
+const createMFAChallenge = `-- name: CreateMFAChallenge :one
+INSERT INTO mfa_challenges(
+    id,
+    username,
+    expired_at
+) VALUES(
+    $1, $2, $3
+) RETURNING id, username, attempts, is_used, expired_at, created_at
+`
+
+type CreateMFAChallengeParams struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+func (q *Queries) CreateMFAChallenge(ctx context.Context, arg CreateMFAChallengeParams) (MfaChallenge, error) {
+	row := q.db.QueryRowContext(ctx, createMFAChallenge, arg.ID, arg.Username, arg.ExpiredAt)
+	var i MfaChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Attempts,
+		&i.IsUsed,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createRecoveryCode = `-- name: CreateRecoveryCode :one
+INSERT INTO recovery_codes(
+    username,
+    hashed_code
+) VALUES(
+    $1, $2
+) RETURNING id, username, hashed_code, used_at, created_at
+`
+
+type CreateRecoveryCodeParams struct {
+	Username   string `json:"username"`
+	HashedCode string `json:"hashed_code"`
+}
+
+func (q *Queries) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) (RecoveryCode, error) {
+	row := q.db.QueryRowContext(ctx, createRecoveryCode, arg.Username, arg.HashedCode)
+	var i RecoveryCode
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedCode,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteRecoveryCodes = `-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE username = $1
+`
+
+func (q *Queries) DeleteRecoveryCodes(ctx context.Context, username string) error {
+	_, err := q.db.ExecContext(ctx, deleteRecoveryCodes, username)
+	return err
+}
+
+const getMFAChallenge = `-- name: GetMFAChallenge :one
+SELECT id, username, attempts, is_used, expired_at, created_at FROM mfa_challenges
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetMFAChallenge(ctx context.Context, id uuid.UUID) (MfaChallenge, error) {
+	row := q.db.QueryRowContext(ctx, getMFAChallenge, id)
+	var i MfaChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Attempts,
+		&i.IsUsed,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserTOTP = `-- name: GetUserTOTP :one
+SELECT username, secret, is_confirmed, last_used_step, created_at, confirmed_at FROM user_totps
+WHERE username = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserTOTP(ctx context.Context, username string) (UserTotp, error) {
+	row := q.db.QueryRowContext(ctx, getUserTOTP, username)
+	var i UserTotp
+	err := row.Scan(
+		&i.Username,
+		&i.Secret,
+		&i.IsConfirmed,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+		&i.ConfirmedAt,
+	)
+	return i, err
+}
+
+const incrementMFAChallengeAttempts = `-- name: IncrementMFAChallengeAttempts :one
+UPDATE mfa_challenges
+SET attempts = attempts + 1
+WHERE id = $1
+RETURNING id, username, attempts, is_used, expired_at, created_at
+`
+
+func (q *Queries) IncrementMFAChallengeAttempts(ctx context.Context, id uuid.UUID) (MfaChallenge, error) {
+	row := q.db.QueryRowContext(ctx, incrementMFAChallengeAttempts, id)
+	var i MfaChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Attempts,
+		&i.IsUsed,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const upsertUserTOTP = `-- name: UpsertUserTOTP :one
+INSERT INTO user_totps(
+    username,
+    secret
+) VALUES(
+    $1, $2
+) ON CONFLICT (username) DO UPDATE
+SET secret = EXCLUDED.secret,
+    is_confirmed = false,
+    last_used_step = 0,
+    created_at = now(),
+    confirmed_at = NULL
+WHERE user_totps.is_confirmed = false
+RETURNING username, secret, is_confirmed, last_used_step, created_at, confirmed_at
+`
+
+type UpsertUserTOTPParams struct {
+	Username string `json:"username"`
+	Secret   string `json:"secret"`
+}
+
+func (q *Queries) UpsertUserTOTP(ctx context.Context, arg UpsertUserTOTPParams) (UserTotp, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserTOTP, arg.Username, arg.Secret)
+	var i UserTotp
+	err := row.Scan(
+		&i.Username,
+		&i.Secret,
+		&i.IsConfirmed,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+		&i.ConfirmedAt,
+	)
+	return i, err
+}
+
+const useMFAChallenge = `-- name: UseMFAChallenge :one
+UPDATE mfa_challenges
+SET is_used = true
+WHERE id = $1
+    AND is_used = false
+    AND attempts < $2
+    AND expired_at > now()
+RETURNING id, username, attempts, is_used, expired_at, created_at
+`
+
+type UseMFAChallengeParams struct {
+	ID          uuid.UUID `json:"id"`
+	MaxAttempts int32     `json:"max_attempts"`
+}
+
+func (q *Queries) UseMFAChallenge(ctx context.Context, arg UseMFAChallengeParams) (MfaChallenge, error) {
+	row := q.db.QueryRowContext(ctx, useMFAChallenge, arg.ID, arg.MaxAttempts)
+	var i MfaChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Attempts,
+		&i.IsUsed,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useRecoveryCode = `-- name: UseRecoveryCode :one
+UPDATE recovery_codes
+SET used_at = now()
+WHERE username = $1 AND hashed_code = $2 AND used_at IS NULL
+RETURNING id, username, hashed_code, used_at, created_at
+`
+
+type UseRecoveryCodeParams struct {
+	Username   string `json:"username"`
+	HashedCode string `json:"hashed_code"`
+}
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (RecoveryCode, error) {
+	row := q.db.QueryRowContext(ctx, useRecoveryCode, arg.Username, arg.HashedCode)
+	var i RecoveryCode
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedCode,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useTOTPStep = `-- name: UseTOTPStep :one
+UPDATE user_totps
+SET last_used_step = $1
+WHERE username = $2
+    AND is_confirmed = true
+    AND last_used_step < $1
+RETURNING username, secret, is_confirmed, last_used_step, created_at, confirmed_at
+`
+
+type UseTOTPStepParams struct {
+	Step     int64  `json:"step"`
+	Username string `json:"username"`
+}
+
+func (q *Queries) UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (UserTotp, error) {
+	row := q.db.QueryRowContext(ctx, useTOTPStep, arg.Step, arg.Username)
+	var i UserTotp
+	err := row.Scan(
+		&i.Username,
+		&i.Secret,
+		&i.IsConfirmed,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+		&i.ConfirmedAt,
+	)
+	return i, err
+}
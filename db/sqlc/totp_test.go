@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmTOTPTx(t *testing.T) {
+	user := createRandomUser(t)
+
+	secret, err := util.GenerateTOTPSecret()
+	require.NoError(t, err)
+
+	userTOTP, err := testQueries.UpsertUserTOTP(context.Background(), UpsertUserTOTPParams{
+		Username: user.Username,
+		Secret:   secret,
+	})
+	require.NoError(t, err)
+	require.False(t, userTOTP.IsConfirmed)
+
+	store := NewStore(testDB)
+	step := util.TOTPStep(time.Now())
+	result, err := store.ConfirmTOTPTx(context.Background(), ConfirmTOTPTxParams{
+		Username:            user.Username,
+		Step:                step,
+		HashedRecoveryCodes: []string{util.HashRecoveryCode("aaaaa-aaaaa"), util.HashRecoveryCode("bbbbb-bbbbb")},
+	})
+	require.NoError(t, err)
+	require.True(t, result.UserTOTP.IsConfirmed)
+	require.True(t, result.UserTOTP.ConfirmedAt.Valid)
+	require.Len(t, result.RecoveryCodes, 2)
+
+	// a confirmed secret is neither replaced nor confirmed again
+	_, err = testQueries.UpsertUserTOTP(context.Background(), UpsertUserTOTPParams{
+		Username: user.Username,
+		Secret:   secret,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = store.ConfirmTOTPTx(context.Background(), ConfirmTOTPTxParams{Username: user.Username, Step: step})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// the confirming code cannot be used to sign in
+	_, err = testQueries.UseTOTPStep(context.Background(), UseTOTPStepParams{Username: user.Username, Step: step})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = testQueries.UseTOTPStep(context.Background(), UseTOTPStepParams{Username: user.Username, Step: step + 1})
+	require.NoError(t, err)
+
+	// recovery codes are single use
+	_, err = testQueries.UseRecoveryCode(context.Background(), UseRecoveryCodeParams{
+		Username:   user.Username,
+		HashedCode: util.HashRecoveryCode("AAAAAAAAAA"),
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.UseRecoveryCode(context.Background(), UseRecoveryCodeParams{
+		Username:   user.Username,
+		HashedCode: util.HashRecoveryCode("aaaaa-aaaaa"),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUseMFAChallenge(t *testing.T) {
+	user := createRandomUser(t)
+
+	challenge, err := testQueries.CreateMFAChallenge(context.Background(), CreateMFAChallengeParams{
+		ID:        uuid.New(),
+		Username:  user.Username,
+		ExpiredAt: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Zero(t, challenge.Attempts)
+
+	challenge, err = testQueries.IncrementMFAChallengeAttempts(context.Background(), challenge.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), challenge.Attempts)
+
+	_, err = testQueries.UseMFAChallenge(context.Background(), UseMFAChallengeParams{ID: challenge.ID, MaxAttempts: 1})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	used, err := testQueries.UseMFAChallenge(context.Background(), UseMFAChallengeParams{ID: challenge.ID, MaxAttempts: 5})
+	require.NoError(t, err)
+	require.True(t, used.IsUsed)
+
+	_, err = testQueries.UseMFAChallenge(context.Background(), UseMFAChallengeParams{ID: challenge.ID, MaxAttempts: 5})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestVerifyMFATx(t *testing.T) {
+	user := createRandomUser(t)
+	store := NewStore(testDB)
+
+	hashedCode := util.HashRecoveryCode("aaaaa-aaaaa")
+	_, err := testQueries.CreateRecoveryCode(context.Background(), CreateRecoveryCodeParams{
+		Username:   user.Username,
+		HashedCode: hashedCode,
+	})
+	require.NoError(t, err)
+
+	createChallenge := func() MfaChallenge {
+		challenge, err := testQueries.CreateMFAChallenge(context.Background(), CreateMFAChallengeParams{
+			ID:        uuid.New(),
+			Username:  user.Username,
+			ExpiredAt: time.Now().Add(time.Minute),
+		})
+		require.NoError(t, err)
+		return challenge
+	}
+
+	// a rejected code leaves the challenge usable
+	challenge := createChallenge()
+	_, err = store.VerifyMFATx(context.Background(), VerifyMFATxParams{
+		ChallengeID:        challenge.ID,
+		MaxAttempts:        5,
+		Username:           user.Username,
+		HashedRecoveryCode: util.HashRecoveryCode("bbbbb-bbbbb"),
+	})
+	require.ErrorIs(t, err, ErrSecondFactorRejected)
+
+	used, err := store.VerifyMFATx(context.Background(), VerifyMFATxParams{
+		ChallengeID:        challenge.ID,
+		MaxAttempts:        5,
+		Username:           user.Username,
+		HashedRecoveryCode: hashedCode,
+	})
+	require.NoError(t, err)
+	require.True(t, used.IsUsed)
+
+	// neither a used challenge nor a used recovery code can be answered with again
+	_, err = store.VerifyMFATx(context.Background(), VerifyMFATxParams{
+		ChallengeID:        challenge.ID,
+		MaxAttempts:        5,
+		Username:           user.Username,
+		HashedRecoveryCode: hashedCode,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = store.VerifyMFATx(context.Background(), VerifyMFATxParams{
+		ChallengeID:        createChallenge().ID,
+		MaxAttempts:        5,
+		Username:           user.Username,
+		HashedRecoveryCode: hashedCode,
+	})
+	require.ErrorIs(t, err, ErrSecondFactorRejected)
+}
@@ -8,26 +8,47 @@ package db
 import (
 	"context"
 	"database/sql"
+
+	"github.com/google/uuid"
 )
 
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers(
     from_account_id,
     to_account_id,
-    amount
+    amount,
+    credit_amount,
+    exchange_rate,
+    quote_id,
+    reversal_of,
+    reason
 ) VALUES(
-    $1, $2, $3
-) RETURNING id, from_account_id, to_account_id, amount, created_at
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, from_account_id, to_account_id, amount, created_at, credit_amount, exchange_rate, quote_id, reversal_of, reason
 `
 
 type CreateTransferParams struct {
 	FromAccountID sql.NullInt64 `json:"from_account_id"`
 	ToAccountID   sql.NullInt64 `json:"to_account_id"`
 	Amount        int64         `json:"amount"`
+	CreditAmount  int64         `json:"credit_amount"`
+	ExchangeRate  string        `json:"exchange_rate"`
+	QuoteID       uuid.NullUUID `json:"quote_id"`
+	ReversalOf    sql.NullInt64 `json:"reversal_of"`
+	Reason        string        `json:"reason"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.CreditAmount,
+		arg.ExchangeRate,
+		arg.QuoteID,
+		arg.ReversalOf,
+		arg.Reason,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -35,12 +56,17 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.CreditAmount,
+		&i.ExchangeRate,
+		&i.QuoteID,
+		&i.ReversalOf,
+		&i.Reason,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, credit_amount, exchange_rate, quote_id, reversal_of, reason FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -53,31 +79,92 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.CreditAmount,
+		&i.ExchangeRate,
+		&i.QuoteID,
+		&i.ReversalOf,
+		&i.Reason,
 	)
 	return i, err
 }
 
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, credit_amount, exchange_rate, quote_id, reversal_of, reason FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.CreditAmount,
+		&i.ExchangeRate,
+		&i.QuoteID,
+		&i.ReversalOf,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const getTransferReversedAmount = `-- name: GetTransferReversedAmount :one
+SELECT
+    COALESCE(SUM(amount), 0)::bigint AS debited_amount,
+    COALESCE(SUM(credit_amount), 0)::bigint AS refunded_amount
+FROM transfers
+WHERE reversal_of = $1::bigint
+`
+
+type GetTransferReversedAmountRow struct {
+	DebitedAmount  int64 `json:"debited_amount"`
+	RefundedAmount int64 `json:"refunded_amount"`
+}
+
+func (q *Queries) GetTransferReversedAmount(ctx context.Context, transferID int64) (GetTransferReversedAmountRow, error) {
+	row := q.db.QueryRowContext(ctx, getTransferReversedAmount, transferID)
+	var i GetTransferReversedAmountRow
+	err := row.Scan(&i.DebitedAmount, &i.RefundedAmount)
+	return i, err
+}
+
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
-WHERE from_account_id = $1 OR to_account_id = $2
-ORDER BY id
-LIMIT $3
-OFFSET $4
+SELECT id, from_account_id, to_account_id, amount, created_at, credit_amount, exchange_rate, quote_id, reversal_of, reason FROM transfers
+WHERE
+    (
+        ($1::varchar <> 'outgoing' AND to_account_id = $2) OR
+        ($1::varchar <> 'incoming' AND from_account_id = $2)
+    ) AND
+    ($3::timestamptz IS NULL OR created_at >= $3) AND
+    ($4::timestamptz IS NULL OR created_at < $4) AND
+    ($5::bigint IS NULL OR (created_at, id) < ($6::timestamptz, $5))
+ORDER BY created_at DESC, id DESC
+LIMIT $7
 `
 
 type ListTransfersParams struct {
-	FromAccountID sql.NullInt64 `json:"from_account_id"`
-	ToAccountID   sql.NullInt64 `json:"to_account_id"`
-	Limit         int32         `json:"limit"`
-	Offset        int32         `json:"offset"`
+	Direction       string        `json:"direction"`
+	AccountID       sql.NullInt64 `json:"account_id"`
+	StartTime       sql.NullTime  `json:"start_time"`
+	EndTime         sql.NullTime  `json:"end_time"`
+	CursorID        sql.NullInt64 `json:"cursor_id"`
+	CursorCreatedAt sql.NullTime  `json:"cursor_created_at"`
+	PageSize        int32         `json:"page_size"`
 }
 
 func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
 	rows, err := q.db.QueryContext(ctx, listTransfers,
-		arg.FromAccountID,
-		arg.ToAccountID,
-		arg.Limit,
-		arg.Offset,
+		arg.Direction,
+		arg.AccountID,
+		arg.StartTime,
+		arg.EndTime,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageSize,
 	)
 	if err != nil {
 		return nil, err
@@ -92,6 +179,11 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.CreditAmount,
+			&i.ExchangeRate,
+			&i.QuoteID,
+			&i.ReversalOf,
+			&i.Reason,
 		); err != nil {
 			return nil, err
 		}
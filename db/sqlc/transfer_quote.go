@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ErrInvalidQuote is returned when the quote of a transfer is unknown, expired or already used
+var ErrInvalidQuote = errors.New("transfer quote is invalid, expired or already used")
+
+// ErrQuoteMismatch is returned when the quote was made for other accounts or another amount
+var ErrQuoteMismatch = errors.New("transfer quote doesn't match the transfer")
+
+// consumeTransferQuote marks the quote of the transfer as used and returns it,
+// checking that it was made by the same user for the same accounts and amount
+func consumeTransferQuote(ctx context.Context, q *Queries, arg TransferParams) (TransferQuote, error) {
+	quote, err := q.UseTransferQuote(ctx, UseTransferQuoteParams{
+		ID:       arg.QuoteID.UUID,
+		Username: arg.Username,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TransferQuote{}, ErrInvalidQuote
+		}
+		return TransferQuote{}, err
+	}
+
+	if quote.FromAccountID != arg.FromAccountID || quote.ToAccountID != arg.ToAccountID || quote.Amount != arg.Amount {
+		return TransferQuote{}, ErrQuoteMismatch
+	}
+
+	return quote, nil
+}
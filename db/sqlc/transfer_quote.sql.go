@@ -0,0 +1,106 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.20.0
+// source: transfer_quote.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createTransferQuote = `-- name: CreateTransferQuote :one
+INSERT INTO transfer_quotes(
+    id,
+    username,
+    from_account_id,
+    to_account_id,
+    amount,
+    credit_amount,
+    exchange_rate_id,
+    exchange_rate,
+    expired_at
+) VALUES(
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING id, username, from_account_id, to_account_id, amount, credit_amount, exchange_rate_id, exchange_rate, is_used, expired_at, created_at
+`
+
+type CreateTransferQuoteParams struct {
+	ID             uuid.UUID `json:"id"`
+	Username       string    `json:"username"`
+	FromAccountID  int64     `json:"from_account_id"`
+	ToAccountID    int64     `json:"to_account_id"`
+	Amount         int64     `json:"amount"`
+	CreditAmount   int64     `json:"credit_amount"`
+	ExchangeRateID int64     `json:"exchange_rate_id"`
+	ExchangeRate   string    `json:"exchange_rate"`
+	ExpiredAt      time.Time `json:"expired_at"`
+}
+
+func (q *Queries) CreateTransferQuote(ctx context.Context, arg CreateTransferQuoteParams) (TransferQuote, error) {
+	row := q.db.QueryRowContext(ctx, createTransferQuote,
+		arg.ID,
+		arg.Username,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.CreditAmount,
+		arg.ExchangeRateID,
+		arg.ExchangeRate,
+		arg.ExpiredAt,
+	)
+	var i TransferQuote
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreditAmount,
+		&i.ExchangeRateID,
+		&i.ExchangeRate,
+		&i.IsUsed,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useTransferQuote = `-- name: UseTransferQuote :one
+UPDATE transfer_quotes
+SET
+    is_used = TRUE
+WHERE
+    id = $1 AND
+    username = $2 AND
+    is_used = FALSE AND
+    expired_at > now()
+RETURNING id, username, from_account_id, to_account_id, amount, credit_amount, exchange_rate_id, exchange_rate, is_used, expired_at, created_at
+`
+
+type UseTransferQuoteParams struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+}
+
+func (q *Queries) UseTransferQuote(ctx context.Context, arg UseTransferQuoteParams) (TransferQuote, error) {
+	row := q.db.QueryRowContext(ctx, useTransferQuote, arg.ID, arg.Username)
+	var i TransferQuote
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreditAmount,
+		&i.ExchangeRateID,
+		&i.ExchangeRate,
+		&i.IsUsed,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
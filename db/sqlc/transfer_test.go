@@ -11,10 +11,13 @@ import (
 )
 
 func createRandomTransfer(t *testing.T, account1, account2 Account) Transfer {
+	amount := util.RandomMoney()
 	arg := CreateTransferParams{
 		FromAccountID: sql.NullInt64{Int64: account1.ID, Valid: true},
 		ToAccountID:   sql.NullInt64{Int64: account2.ID, Valid: true},
-		Amount:        util.RandomMoney(),
+		Amount:        amount,
+		CreditAmount:  amount,
+		ExchangeRate:  "1",
 	}
 
 	transfer, err := testQueries.CreateTransfer(context.Background(), arg)
@@ -24,6 +27,7 @@ func createRandomTransfer(t *testing.T, account1, account2 Account) Transfer {
 	require.Equal(t, arg.FromAccountID, transfer.FromAccountID)
 	require.Equal(t, arg.ToAccountID, transfer.ToAccountID)
 	require.Equal(t, arg.Amount, transfer.Amount)
+	require.Equal(t, arg.CreditAmount, transfer.CreditAmount)
 
 	require.NotZero(t, transfer.ID)
 	require.NotZero(t, transfer.CreatedAt)
@@ -63,10 +67,8 @@ func TestListTransfers(t *testing.T){
 	}
 
 	arg := ListTransfersParams{
-		FromAccountID: sql.NullInt64{Int64: account1.ID, Valid: true},
-		ToAccountID:   sql.NullInt64{Int64: account1.ID, Valid: true},
-		Limit:         5,
-		Offset:        5,
+		AccountID: sql.NullInt64{Int64: account1.ID, Valid: true},
+		PageSize:  5,
 	}
 
 	transfers, err := testQueries.ListTransfers(context.Background(), arg)
@@ -77,4 +79,20 @@ func TestListTransfers(t *testing.T){
 		require.NotEmpty(t, transfer)
 		require.True(t, transfer.FromAccountID.Int64 == account1.ID || transfer.ToAccountID.Int64 == account1.ID)
 	}
+
+	arg.Direction = DirectionOutgoing
+	arg.PageSize = 10
+	transfers, err = testQueries.ListTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, transfers, 5)
+
+	for _, transfer := range transfers {
+		require.Equal(t, account1.ID, transfer.FromAccountID.Int64)
+	}
+
+	arg.Direction = DirectionIncoming
+	arg.StartTime = sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true}
+	transfers, err = testQueries.ListTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, transfers)
 }
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sangketkit01/simple-bank/util"
+)
+
+// CaptureHoldTxParams contains the input parameters of the capture hold transaction
+type CaptureHoldTxParams struct {
+	HoldID int64
+	// Amount is transferred to the destination account of the hold, zero captures the whole hold
+	Amount int64
+}
+
+// CaptureHoldTxResult is the result of the capture hold transaction
+type CaptureHoldTxResult struct {
+	Hold AccountHold
+	TransferTxResult
+}
+
+// CaptureHoldTx settles an active hold: the captured amount is transferred to the destination account
+// of the hold with a normal transfer, and whatever is left of the hold is released.
+func (store *SQLStore) CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error) {
+	var result CaptureHoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		hold, err := q.GetAccountHoldForUpdate(ctx, arg.HoldID)
+		if err != nil {
+			return err
+		}
+
+		if hold.Status != util.HoldActive {
+			return ErrHoldNotActive
+		}
+		if !time.Now().Before(hold.ExpiresAt) {
+			return ErrHoldExpired
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = hold.Amount
+		}
+		if amount > hold.Amount {
+			return ErrCaptureExceedsHold
+		}
+
+		// take the account locks in the order of the transfer before releasing the held amount
+		_, err = lockAccounts(ctx, q, hold.AccountID, hold.ToAccountID)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+			ID:     hold.AccountID,
+			Amount: -hold.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.TransferTxResult, err = transfer(ctx, q, TransferParams{
+			FromAccountID: hold.AccountID,
+			ToAccountID:   hold.ToAccountID,
+			Amount:        amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.SettleAccountHold(ctx, SettleAccountHoldParams{
+			ID:             hold.ID,
+			Status:         util.HoldCaptured,
+			CapturedAmount: amount,
+			TransferID:     sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
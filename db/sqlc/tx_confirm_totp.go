@@ -0,0 +1,55 @@
+package db
+
+import "context"
+
+type ConfirmTOTPTxParams struct {
+	Username string
+	// Step is the time step of the code that confirmed the enrollment, it cannot be used again
+	Step int64
+	// HashedRecoveryCodes replace any recovery code the user had before
+	HashedRecoveryCodes []string
+}
+
+type ConfirmTOTPTxResult struct {
+	UserTOTP      UserTotp
+	RecoveryCodes []RecoveryCode
+}
+
+// ConfirmTOTPTx enables the pending TOTP secret of the user and stores a new set of recovery codes.
+// It returns sql.ErrNoRows if the user has no pending enrollment.
+func (store *SQLStore) ConfirmTOTPTx(ctx context.Context, arg ConfirmTOTPTxParams) (ConfirmTOTPTxResult, error) {
+	var result ConfirmTOTPTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.UserTOTP, err = q.ConfirmUserTOTP(ctx, ConfirmUserTOTPParams{
+			Username: arg.Username,
+			Step:     arg.Step,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteRecoveryCodes(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		result.RecoveryCodes = make([]RecoveryCode, 0, len(arg.HashedRecoveryCodes))
+		for _, hashedCode := range arg.HashedRecoveryCodes {
+			recoveryCode, err := q.CreateRecoveryCode(ctx, CreateRecoveryCodeParams{
+				Username:   arg.Username,
+				HashedCode: hashedCode,
+			})
+			if err != nil {
+				return err
+			}
+			result.RecoveryCodes = append(result.RecoveryCodes, recoveryCode)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
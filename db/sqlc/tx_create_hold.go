@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// CreateHoldTxParams contains the input parameters of the create hold transaction
+type CreateHoldTxParams struct {
+	Owner       string
+	AccountID   int64
+	ToAccountID int64
+	Amount      int64
+	Currency    string
+	ExpiresAt   time.Time
+}
+
+// CreateHoldTxResult is the result of the create hold transaction
+type CreateHoldTxResult struct {
+	Hold    AccountHold
+	Account Account
+}
+
+// CreateHoldTx reserves the amount on the account until the hold is captured, released or expires.
+// The balance doesn't change, but the reserved amount is no longer available for transfers and other holds.
+func (store *SQLStore) CreateHoldTx(ctx context.Context, arg CreateHoldTxParams) (CreateHoldTxResult, error) {
+	var result CreateHoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if account.AvailableBalance() < arg.Amount {
+			return ErrInsufficientFunds
+		}
+
+		result.Account, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+			ID:     arg.AccountID,
+			Amount: arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.CreateAccountHold(ctx, CreateAccountHoldParams{
+			Owner:       arg.Owner,
+			AccountID:   arg.AccountID,
+			ToAccountID: arg.ToAccountID,
+			Amount:      arg.Amount,
+			Currency:    arg.Currency,
+			ExpiresAt:   arg.ExpiresAt,
+		})
+		return err
+	})
+
+	return result, err
+}
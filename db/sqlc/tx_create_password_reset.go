@@ -0,0 +1,21 @@
+package db
+
+import "context"
+
+// CreatePasswordResetTx creates a password reset and invalidates every earlier unused reset of the user,
+// so only the link of the latest email works, also when sending an earlier one failed and was retried.
+func (store *SQLStore) CreatePasswordResetTx(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error) {
+	var passwordReset PasswordReset
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := q.InvalidatePasswordResets(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		passwordReset, err = q.CreatePasswordReset(ctx, arg)
+		return err
+	})
+
+	return passwordReset, err
+}
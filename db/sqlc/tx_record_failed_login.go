@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/sangketkit01/simple-bank/util"
+)
+
+type RecordFailedLoginTxParams struct {
+	Username string
+	// LockoutThreshold failed logins in a row lock the user for LockoutDuration,
+	// every further failure doubles the lockout up to MaxLockoutDuration
+	LockoutThreshold   int32
+	LockoutDuration    time.Duration
+	MaxLockoutDuration time.Duration
+}
+
+// RecordFailedLoginTx counts a failed login of the user and locks the user once the lockout threshold is reached.
+// It returns sql.ErrNoRows if the user does not exist.
+func (store *SQLStore) RecordFailedLoginTx(ctx context.Context, arg RecordFailedLoginTxParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		user, err = q.RecordFailedLogin(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		lockout := util.LockoutDuration(user.FailedLoginCount, arg.LockoutThreshold, arg.LockoutDuration, arg.MaxLockoutDuration)
+		if lockout <= 0 {
+			return nil
+		}
+
+		user, err = q.LockUser(ctx, LockUserParams{
+			Username:    arg.Username,
+			LockedUntil: time.Now().Add(lockout),
+		})
+		return err
+	})
+
+	return user, err
+}
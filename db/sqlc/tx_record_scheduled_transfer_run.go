@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/sangketkit01/simple-bank/util"
+)
+
+// ErrScheduledRunStale is returned when the scheduled transfer is no longer due at the recorded time,
+// because the run was already recorded or the schedule was paused, changed or cancelled meanwhile
+var ErrScheduledRunStale = errors.New("scheduled transfer is no longer due at this time")
+
+// RecordScheduledTransferRunTxParams contains the input parameters of the record scheduled transfer run transaction
+type RecordScheduledTransferRunTxParams struct {
+	ScheduledTransferID int64
+	// ScheduledAt is the next_run_at of the scheduled transfer the run was made for
+	ScheduledAt time.Time
+	// Transfer is made in the same transaction as the run is recorded, so a transfer is never left without its run.
+	// When it is nil the run failed, Error tells why.
+	Transfer *TransferParams
+	Error    string
+	// InsufficientFunds failures count towards MaxFailures, once reached the schedule is suspended
+	InsufficientFunds bool
+	MaxFailures       int32
+	// Suspend suspends the schedule right away, for failures that won't go away by themselves
+	Suspend bool
+}
+
+// RecordScheduledTransferRunTxResult is the result of the record scheduled transfer run transaction
+type RecordScheduledTransferRunTxResult struct {
+	ScheduledTransfer ScheduledTransfer
+	Run               ScheduledTransferRun
+	// Transfer is the result of the transfer of a successful run
+	Transfer TransferTxResult
+}
+
+// RecordScheduledTransferRunTx makes the transfer of a run, when there is one, records the run in the history
+// of the scheduled transfer and moves the schedule to its next occurrence, completing it after the last one.
+// A stale run returns ErrScheduledRunStale before any money is moved, and a transfer that fails
+// (e.g. with ErrInsufficientFunds) rolls the whole transaction back.
+func (store *SQLStore) RecordScheduledTransferRunTx(ctx context.Context, arg RecordScheduledTransferRunTxParams) (RecordScheduledTransferRunTxResult, error) {
+	var result RecordScheduledTransferRunTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		scheduledTransfer, err := q.GetScheduledTransferForUpdate(ctx, arg.ScheduledTransferID)
+		if err != nil {
+			return err
+		}
+
+		if scheduledTransfer.Status != util.ScheduleActive || !scheduledTransfer.NextRunAt.Equal(arg.ScheduledAt) {
+			return ErrScheduledRunStale
+		}
+
+		runStatus := util.RunFailed
+		var transferID sql.NullInt64
+		if arg.Transfer != nil {
+			result.Transfer, err = transfer(ctx, q, *arg.Transfer)
+			if err != nil {
+				return err
+			}
+
+			runStatus = util.RunSucceeded
+			transferID = sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true}
+		}
+
+		result.Run, err = q.CreateScheduledTransferRun(ctx, CreateScheduledTransferRunParams{
+			ScheduledTransferID: arg.ScheduledTransferID,
+			ScheduledAt:         arg.ScheduledAt,
+			Status:              runStatus,
+			TransferID:          transferID,
+			Error:               arg.Error,
+		})
+		if err != nil {
+			return err
+		}
+
+		failureCount := int32(0)
+		if arg.InsufficientFunds {
+			failureCount = scheduledTransfer.FailureCount + 1
+		}
+
+		update := UpdateScheduledTransferParams{
+			ID:           arg.ScheduledTransferID,
+			FailureCount: sql.NullInt32{Int32: failureCount, Valid: true},
+		}
+
+		schedule, err := util.ParseSchedule(scheduledTransfer.Schedule)
+		if err != nil {
+			return err
+		}
+
+		next := int(scheduledTransfer.NextOccurrence) + 1
+		nextRunAt, ok := schedule.Occurrence(scheduledTransfer.StartsAt, next)
+		switch {
+		case arg.Suspend || (arg.InsufficientFunds && failureCount >= arg.MaxFailures):
+			update.Status = sql.NullString{String: util.ScheduleSuspended, Valid: true}
+		case !ok:
+			update.Status = sql.NullString{String: util.ScheduleCompleted, Valid: true}
+		}
+		if ok {
+			update.NextRunAt = sql.NullTime{Time: nextRunAt, Valid: true}
+			update.NextOccurrence = sql.NullInt32{Int32: int32(next), Valid: true}
+		}
+
+		result.ScheduledTransfer, err = q.UpdateScheduledTransfer(ctx, update)
+		return err
+	})
+
+	return result, err
+}
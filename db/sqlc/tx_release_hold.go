@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+
+	"github.com/sangketkit01/simple-bank/util"
+)
+
+// ReleaseHoldTxParams contains the input parameters of the release hold transaction
+type ReleaseHoldTxParams struct {
+	HoldID int64
+	// Expire marks the hold as expired instead of released, when it is released for being past its expiry time
+	Expire bool
+}
+
+// ReleaseHoldTxResult is the result of the release hold transaction
+type ReleaseHoldTxResult struct {
+	Hold    AccountHold
+	Account Account
+}
+
+// ReleaseHoldTx settles an active hold without moving money, the held amount becomes available again
+func (store *SQLStore) ReleaseHoldTx(ctx context.Context, arg ReleaseHoldTxParams) (ReleaseHoldTxResult, error) {
+	var result ReleaseHoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		hold, err := q.GetAccountHoldForUpdate(ctx, arg.HoldID)
+		if err != nil {
+			return err
+		}
+
+		if hold.Status != util.HoldActive {
+			return ErrHoldNotActive
+		}
+
+		result.Account, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+			ID:     hold.AccountID,
+			Amount: -hold.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		status := util.HoldReleased
+		if arg.Expire {
+			status = util.HoldExpired
+		}
+
+		result.Hold, err = q.SettleAccountHold(ctx, SettleAccountHoldParams{
+			ID:     hold.ID,
+			Status: status,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ResetPasswordTxParams struct {
+	ResetID        int64
+	SecretCode     string
+	HashedPassword string
+}
+
+type ResetPasswordTxResult struct {
+	User          User
+	PasswordReset PasswordReset
+	// BlockedSessionIDs are the sessions of the user that were signed out by the reset
+	BlockedSessionIDs []uuid.UUID
+}
+
+// ResetPasswordTx consumes a password reset, sets the new password of its user, unlocks the user,
+// blocks every session and revokes every API key of that user.
+// Other pending resets of the user are invalidated as well.
+// It returns sql.ErrNoRows if the reset does not exist, is already used or has expired.
+func (store *SQLStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error) {
+	var result ResetPasswordTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.PasswordReset, err = q.UsePasswordReset(ctx, UsePasswordResetParams{
+			ID:         arg.ResetID,
+			SecretCode: arg.SecretCode,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.InvalidatePasswordResets(ctx, result.PasswordReset.Username)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.UpdateUser(ctx, UpdateUserParams{
+			Username: result.PasswordReset.Username,
+			HashedPassword: sql.NullString{
+				String: arg.HashedPassword,
+				Valid:  true,
+			},
+			PasswordChangedAt: sql.NullTime{
+				Time:  time.Now(),
+				Valid: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		// proving access to the email is enough to lift a lockout caused by the forgotten password
+		result.User, err = q.ResetFailedLogins(ctx, result.PasswordReset.Username)
+		if err != nil {
+			return err
+		}
+
+		result.BlockedSessionIDs, err = q.BlockUserSessions(ctx, result.PasswordReset.Username)
+		if err != nil {
+			return err
+		}
+
+		return q.RevokeUserAPIKeys(ctx, result.PasswordReset.Username)
+	})
+
+	return result, err
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/big"
+)
+
+// ErrReversalOfReversal is returned when the transfer to reverse is itself a reversal
+var ErrReversalOfReversal = errors.New("a reversal cannot be reversed")
+
+// ErrTransferFullyReversed is returned when the whole amount of the transfer has already been reversed
+var ErrTransferFullyReversed = errors.New("transfer has already been fully reversed")
+
+// ErrReversalExceedsTransfer is returned when the reversal amount is more than what is left to reverse of the transfer
+var ErrReversalExceedsTransfer = errors.New("reversal amount exceeds what is left to reverse of the transfer")
+
+// ErrReversalTooSmall is returned when a partial reversal of a cross-currency transfer converts to nothing
+var ErrReversalTooSmall = errors.New("reversal amount is too small to convert")
+
+// ReverseTransferTxParams contains the input parameters of the reverse transfer transaction
+type ReverseTransferTxParams struct {
+	TransferID int64
+	// Amount is refunded to the source account of the transfer, in the currency of its amount.
+	// Zero reverses whatever is left of the transfer.
+	Amount int64
+	Reason string
+}
+
+// ReverseTransferTxResult is the result of the reverse transfer transaction,
+// the embedded TransferTxResult holds the compensating transfer and its entries
+type ReverseTransferTxResult struct {
+	ReversedTransfer Transfer
+	TransferTxResult
+}
+
+// ReverseTransferTx undoes a transfer, fully or partially, with a compensating transfer back to its source account
+// that is linked to it through reversal_of. A transfer can be reversed several times until its whole amount is refunded.
+// The part of a cross-currency transfer taken back from its destination account is proportional to the refund.
+func (store *SQLStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// the lock serializes the reversals of the transfer, so together they never refund more than its amount
+		original, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		result.ReversedTransfer = original
+
+		if original.ReversalOf.Valid {
+			return ErrReversalOfReversal
+		}
+
+		reversed, err := q.GetTransferReversedAmount(ctx, original.ID)
+		if err != nil {
+			return err
+		}
+
+		remaining := original.Amount - reversed.RefundedAmount
+		if remaining <= 0 {
+			return ErrTransferFullyReversed
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = remaining
+		}
+		if amount > remaining {
+			return ErrReversalExceedsTransfer
+		}
+
+		// the last reversal takes back exactly what is left of the credit, earlier ones their share rounded down
+		debitAmount := original.CreditAmount - reversed.DebitedAmount
+		if amount < remaining {
+			debitAmount = proportionalAmount(original.CreditAmount, amount, original.Amount)
+		}
+		if debitAmount <= 0 {
+			return ErrReversalTooSmall
+		}
+
+		exchangeRate := "1"
+		if original.CreditAmount != original.Amount {
+			exchangeRate = new(big.Rat).SetFrac64(original.Amount, original.CreditAmount).FloatString(10)
+		}
+
+		result.TransferTxResult, err = recordTransfer(ctx, q, CreateTransferParams{
+			FromAccountID: original.ToAccountID,
+			ToAccountID:   original.FromAccountID,
+			Amount:        debitAmount,
+			CreditAmount:  amount,
+			ExchangeRate:  exchangeRate,
+			ReversalOf:    sql.NullInt64{Int64: original.ID, Valid: true},
+			Reason:        arg.Reason,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// proportionalAmount returns total * part / whole rounded down, without overflowing
+func proportionalAmount(total int64, part int64, whole int64) int64 {
+	result := new(big.Int).Mul(big.NewInt(total), big.NewInt(part))
+	return result.Quo(result, big.NewInt(whole)).Int64()
+}
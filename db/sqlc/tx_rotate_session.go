@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrRefreshTokenReused is returned when a refresh token that was already rotated is presented again.
+// Every session of its family has been blocked by the time it is returned.
+var ErrRefreshTokenReused = errors.New("refresh token reused")
+
+type RotateSessionTxParams struct {
+	// Session is the session whose refresh token is being exchanged
+	Session Session
+	// NewSession holds the new refresh token, it joins the family of Session
+	NewSession CreateSessionParams
+}
+
+type RotateSessionTxResult struct {
+	Session Session
+	// BlockedSessionIDs lists the sessions blocked because of a reuse
+	BlockedSessionIDs []uuid.UUID
+}
+
+// RotateSessionTx marks the session as rotated and creates the next session of its family.
+// If the session was already rotated, the whole family is blocked and ErrRefreshTokenReused is returned.
+func (store *SQLStore) RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error) {
+	var result RotateSessionTxResult
+	reused := false
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		_, err := q.RotateSession(ctx, arg.Session.ID)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+
+			// the token was rotated before, block the family and keep it blocked
+			reused = true
+			result.BlockedSessionIDs, err = q.BlockSessionFamily(ctx, arg.Session.FamilyID)
+			return err
+		}
+
+		newSession := arg.NewSession
+		newSession.FamilyID = arg.Session.FamilyID
+		result.Session, err = q.CreateSession(ctx, newSession)
+		return err
+	})
+	if err == nil && reused {
+		err = ErrRefreshTokenReused
+	}
+
+	return result, err
+}
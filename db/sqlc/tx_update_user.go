@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+type UpdateUserTxParams struct {
+	UpdateUserParams
+	// BlockSessions signs the user out of every session in the same transaction as the update
+	BlockSessions bool
+}
+
+type UpdateUserTxResult struct {
+	User              User
+	BlockedSessionIDs []uuid.UUID
+	// EmailChanged tells the caller to send a verification email to the new address once the update is committed
+	EmailChanged bool
+}
+
+// UpdateUserTx updates the user and, when asked to, blocks all of the user's sessions.
+// A new password revokes every API key of the user. A new email is unverified,
+// and verification links sent for the old one stop working.
+// It returns sql.ErrNoRows if the user does not exist.
+func (store *SQLStore) UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error) {
+	var result UpdateUserTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		emailChanged := false
+		if arg.Email.Valid {
+			user, err := q.GetUserForUpdate(ctx, arg.Username)
+			if err != nil {
+				return err
+			}
+
+			emailChanged = user.Email != arg.Email.String
+		}
+
+		updateArg := arg.UpdateUserParams
+		if emailChanged {
+			updateArg.IsEmailVerified = sql.NullBool{
+				Bool:  false,
+				Valid: true,
+			}
+		}
+
+		var err error
+		result.User, err = q.UpdateUser(ctx, updateArg)
+		if err != nil {
+			return err
+		}
+
+		if arg.BlockSessions {
+			result.BlockedSessionIDs, err = q.BlockUserSessions(ctx, result.User.Username)
+			if err != nil {
+				return err
+			}
+		}
+
+		// keys made by whoever knew the old password must not outlive it
+		if arg.HashedPassword.Valid {
+			err = q.RevokeUserAPIKeys(ctx, result.User.Username)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !emailChanged {
+			return nil
+		}
+
+		result.EmailChanged = true
+		return q.InvalidateVerifyEmails(ctx, result.User.Username)
+	})
+
+	return result, err
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrSecondFactorRejected is returned when the recovery code or the TOTP step was already used or does not exist
+var ErrSecondFactorRejected = errors.New("second factor was rejected")
+
+type VerifyMFATxParams struct {
+	ChallengeID uuid.UUID
+	MaxAttempts int32
+	Username    string
+	// HashedRecoveryCode is consumed when it is set, otherwise the TOTP Step is
+	HashedRecoveryCode string
+	Step               int64
+}
+
+// VerifyMFATx consumes the mfa challenge together with the second factor that answered it,
+// so neither is used up unless both are.
+// It returns sql.ErrNoRows if the challenge is no longer valid and ErrSecondFactorRejected if the second factor is.
+func (store *SQLStore) VerifyMFATx(ctx context.Context, arg VerifyMFATxParams) (MfaChallenge, error) {
+	var challenge MfaChallenge
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		challenge, err = q.UseMFAChallenge(ctx, UseMFAChallengeParams{
+			ID:          arg.ChallengeID,
+			MaxAttempts: arg.MaxAttempts,
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.HashedRecoveryCode != "" {
+			_, err = q.UseRecoveryCode(ctx, UseRecoveryCodeParams{
+				Username:   arg.Username,
+				HashedCode: arg.HashedRecoveryCode,
+			})
+		} else {
+			_, err = q.UseTOTPStep(ctx, UseTOTPStepParams{
+				Username: arg.Username,
+				Step:     arg.Step,
+			})
+		}
+		if err == sql.ErrNoRows {
+			return ErrSecondFactorRejected
+		}
+		return err
+	})
+
+	return challenge, err
+}
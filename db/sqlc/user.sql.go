@@ -8,6 +8,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const createUser = `-- name: CreateUser :one
@@ -18,7 +19,7 @@ INSERT INTO users(
     email
 ) VALUES(
     $1, $2, $3, $4
-) RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified
+) RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, failed_login_count, locked_until
 `
 
 type CreateUserParams struct {
@@ -44,12 +45,15 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
 		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginCount,
+		&i.LockedUntil,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified FROM users
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, failed_login_count, locked_until FROM users
 WHERE username = $1 LIMIT 1
 `
 
@@ -64,6 +68,143 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
 		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginCount,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, failed_login_count, locked_until FROM users
+WHERE email = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginCount,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const getUserForUpdate = `-- name: GetUserForUpdate :one
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, failed_login_count, locked_until FROM users
+WHERE username = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetUserForUpdate(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserForUpdate, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginCount,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const lockUser = `-- name: LockUser :one
+UPDATE users
+SET
+    locked_until = GREATEST(locked_until, $1)
+WHERE
+    username = $2
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, failed_login_count, locked_until
+`
+
+type LockUserParams struct {
+	LockedUntil time.Time `json:"locked_until"`
+	Username    string    `json:"username"`
+}
+
+func (q *Queries) LockUser(ctx context.Context, arg LockUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, lockUser, arg.LockedUntil, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginCount,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const recordFailedLogin = `-- name: RecordFailedLogin :one
+UPDATE users
+SET
+    failed_login_count = failed_login_count + 1
+WHERE
+    username = $1
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, failed_login_count, locked_until
+`
+
+func (q *Queries) RecordFailedLogin(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRowContext(ctx, recordFailedLogin, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginCount,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const resetFailedLogins = `-- name: ResetFailedLogins :one
+UPDATE users
+SET
+    failed_login_count = 0,
+    locked_until = '0001-01-01 00:00:00Z'
+WHERE
+    username = $1
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, failed_login_count, locked_until
+`
+
+func (q *Queries) ResetFailedLogins(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRowContext(ctx, resetFailedLogins, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginCount,
+		&i.LockedUntil,
 	)
 	return i, err
 }
@@ -75,10 +216,11 @@ SET
     password_changed_at = COALESCE($2, password_changed_at),
     full_name = COALESCE($3, full_name),
     email = COALESCE($4, email),
-    is_email_verified = COALESCE($5, is_email_verified)
+    is_email_verified = COALESCE($5, is_email_verified),
+    role = COALESCE($6, role)
 WHERE 
-    username = $6
-RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified
+    username = $7
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, is_email_verified, role, failed_login_count, locked_until
 `
 
 type UpdateUserParams struct {
@@ -87,6 +229,7 @@ type UpdateUserParams struct {
 	FullName          sql.NullString `json:"full_name"`
 	Email             sql.NullString `json:"email"`
 	IsEmailVerified   sql.NullBool   `json:"is_email_verified"`
+	Role              sql.NullString `json:"role"`
 	Username          string         `json:"username"`
 }
 
@@ -97,6 +240,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.FullName,
 		arg.Email,
 		arg.IsEmailVerified,
+		arg.Role,
 		arg.Username,
 	)
 	var i User
@@ -108,6 +252,9 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
 		&i.IsEmailVerified,
+		&i.Role,
+		&i.FailedLoginCount,
+		&i.LockedUntil,
 	)
 	return i, err
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sangketkit01/simple-bank/util"
 	"github.com/stretchr/testify/require"
 )
@@ -27,6 +28,7 @@ func createRandomUser(t *testing.T) User {
 	require.Equal(t, arg.HashedPassword, user.HashedPassword)
 	require.Equal(t, arg.FullName, user.FullName)
 	require.Equal(t, arg.Email, user.Email)
+	require.Equal(t, util.DepositorRole, user.Role)
 
 	require.True(t, user.PasswordChangedAt.IsZero())
 	require.NotZero(t, user.CreatedAt)
@@ -113,6 +115,24 @@ func TestUpdateUserOnlyPassword(t *testing.T){
 	require.Equal(t, oldUser.FullName, updatedUser.FullName)
 }
 
+func TestUpdateUserOnlyRole(t *testing.T){
+	oldUser := createRandomUser(t)
+
+	updatedUser, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username: oldUser.Username,
+		Role: sql.NullString{
+			String: util.BankerRole,
+			Valid: true,
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, util.BankerRole, updatedUser.Role)
+	require.Equal(t, oldUser.FullName, updatedUser.FullName)
+	require.Equal(t, oldUser.Email, updatedUser.Email)
+	require.Equal(t, oldUser.HashedPassword, updatedUser.HashedPassword)
+}
+
 func TestUpdateUserAllFields(t *testing.T){
 	oldUser := createRandomUser(t)
 
@@ -148,3 +168,135 @@ func TestUpdateUserAllFields(t *testing.T){
 	require.Equal(t, newEmail, updatedUser.Email)
 	require.Equal(t, newHashedPassword, updatedUser.HashedPassword)
 }
+
+func TestUpdateUserTxBlocksSessions(t *testing.T) {
+	user := createRandomUser(t)
+	session := createRandomSession(t, user)
+	apiKey := createRandomAPIKey(t, user)
+
+	newHashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+
+	store := NewStore(testDB)
+	result, err := store.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: user.Username,
+			HashedPassword: sql.NullString{
+				String: newHashedPassword,
+				Valid:  true,
+			},
+			PasswordChangedAt: sql.NullTime{
+				Time:  time.Now(),
+				Valid: true,
+			},
+		},
+		BlockSessions: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, newHashedPassword, result.User.HashedPassword)
+	require.Equal(t, []uuid.UUID{session.ID}, result.BlockedSessionIDs)
+
+	state, err := SessionAuthState(context.Background(), testQueries, session.ID)
+	require.NoError(t, err)
+	require.True(t, state.IsBlock)
+	require.True(t, state.PasswordChangedAt.After(user.PasswordChangedAt))
+
+	// a new password revokes the API keys of the user
+	apiKeys, err := testQueries.ListAPIKeys(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, apiKeys, 1)
+	require.Equal(t, apiKey.ID, apiKeys[0].ID)
+	require.True(t, apiKeys[0].IsRevoked)
+}
+
+func TestUpdateUserTxChangesEmail(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomAPIKey(t, user)
+
+	verifyEmail, err := testQueries.CreateVerifyEmail(context.Background(), CreateVerifyEmailParams{
+		Username:   user.Username,
+		Email:      user.Email,
+		SecretCode: util.RandomString(32),
+	})
+	require.NoError(t, err)
+
+	verifiedUser, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username:        user.Username,
+		IsEmailVerified: sql.NullBool{Bool: true, Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, verifiedUser.IsEmailVerified)
+
+	store := NewStore(testDB)
+
+	// the same email keeps its verification
+	result, err := store.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: user.Username,
+			Email:    sql.NullString{String: user.Email, Valid: true},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, result.User.IsEmailVerified)
+	require.False(t, result.EmailChanged)
+
+	newEmail := util.RandomEmail()
+	result, err = store.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: user.Username,
+			Email:    sql.NullString{String: newEmail, Valid: true},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, newEmail, result.User.Email)
+	require.False(t, result.User.IsEmailVerified)
+	require.True(t, result.EmailChanged)
+
+	// a link sent to the old email cannot verify the new one
+	_, err = testQueries.UpdateVerifyEmail(context.Background(), UpdateVerifyEmailParams{
+		ID:         verifyEmail.ID,
+		SecretCode: verifyEmail.SecretCode,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// only a new password revokes the API keys
+	apiKeys, err := testQueries.ListAPIKeys(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, apiKeys, 1)
+	require.False(t, apiKeys[0].IsRevoked)
+}
+
+func TestRecordFailedLoginTx(t *testing.T) {
+	user := createRandomUser(t)
+	store := NewStore(testDB)
+
+	arg := RecordFailedLoginTxParams{
+		Username:           user.Username,
+		LockoutThreshold:   2,
+		LockoutDuration:    time.Minute,
+		MaxLockoutDuration: time.Hour,
+	}
+
+	failedUser, err := store.RecordFailedLoginTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), failedUser.FailedLoginCount)
+	require.True(t, failedUser.LockedUntil.Before(time.Now()))
+
+	lockedUser, err := store.RecordFailedLoginTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), lockedUser.FailedLoginCount)
+	require.WithinDuration(t, time.Now().Add(time.Minute), lockedUser.LockedUntil, 5*time.Second)
+
+	// every further failure doubles the lockout
+	lockedUser, err = store.RecordFailedLoginTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(2*time.Minute), lockedUser.LockedUntil, 5*time.Second)
+
+	unlockedUser, err := testQueries.ResetFailedLogins(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Zero(t, unlockedUser.FailedLoginCount)
+	require.True(t, unlockedUser.LockedUntil.Before(time.Now()))
+
+	_, err = store.RecordFailedLoginTx(context.Background(), RecordFailedLoginTxParams{Username: util.RandomOwner()})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
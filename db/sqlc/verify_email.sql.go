@@ -40,6 +40,20 @@ func (q *Queries) CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailPa
 	return i, err
 }
 
+const invalidateVerifyEmails = `-- name: InvalidateVerifyEmails :exec
+UPDATE verify_emails
+SET
+    is_used = TRUE
+WHERE
+    username = $1 AND
+    is_used = FALSE
+`
+
+func (q *Queries) InvalidateVerifyEmails(ctx context.Context, username string) error {
+	_, err := q.db.ExecContext(ctx, invalidateVerifyEmails, username)
+	return err
+}
+
 const updateVerifyEmail = `-- name: UpdateVerifyEmail :one
 UPDATE verify_emails
 SET 
@@ -4,11 +4,14 @@
 
 CREATE TABLE "users" (
   "username" varchar PRIMARY KEY,
+  "role" varchar NOT NULL DEFAULT 'depositor',
   "hashed_password" varchar NOT NULL,
   "full_name" varchar NOT NULL,
   "email" varchar UNIQUE NOT NULL,
   "is_email_verified" bool NOT NULL DEFAULT false,
   "password_changed_at" timestamptz NOT NULL DEFAULT (0001-01-01 00:00:00Z),
+  "failed_login_count" int NOT NULL DEFAULT 0,
+  "locked_until" timestamptz NOT NULL DEFAULT (0001-01-01 00:00:00Z),
   "created_at" timestamptz NOT NULL DEFAULT (now())
 );
 
@@ -22,12 +25,31 @@ CREATE TABLE "verify_emails" (
   "expired_at" timestamptz NOT NULL DEFAULT (now() + interval '15 minutes')
 );
 
+CREATE TABLE "password_resets" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "email" varchar NOT NULL,
+  "secret_code" varchar NOT NULL,
+  "is_used" bool NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "expired_at" timestamptz NOT NULL DEFAULT (now() + interval '15 minutes')
+);
+
+CREATE TABLE "currencies" (
+  "code" varchar(3) PRIMARY KEY,
+  "minor_unit" int NOT NULL,
+  "is_enabled" bool NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
 CREATE TABLE "accounts" (
   "id" bigserial PRIMARY KEY,
   "owner" varchar NOT NULL,
-  "balance" bigint NOT NULL,
+  "balance" bigint NOT NULL CHECK ("balance" >= 0),
   "currency" varchar NOT NULL,
-  "created_at" timestamptz NOT NULL DEFAULT (now())
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "held_amount" bigint NOT NULL DEFAULT 0,
+  CHECK ("held_amount" >= 0 AND "held_amount" <= "balance")
 );
 
 CREATE TABLE "entries" (
@@ -42,7 +64,12 @@ CREATE TABLE "transfers" (
   "from_account_id" bigint,
   "to_account_id" bigint,
   "amount" bigint NOT NULL,
-  "created_at" timestamptz NOT NULL DEFAULT (now())
+  "credit_amount" bigint NOT NULL,
+  "exchange_rate" numeric(20,10) NOT NULL DEFAULT 1,
+  "quote_id" uuid UNIQUE,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "reversal_of" bigint,
+  "reason" varchar NOT NULL DEFAULT ''
 );
 
 CREATE TABLE "sessions" (
@@ -53,6 +80,122 @@ CREATE TABLE "sessions" (
   "client_ip" varchar NOT NULL,
   "is_block" boolean NOT NULL DEFAULT false,
   "expired_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "family_id" uuid NOT NULL,
+  "is_rotated" boolean NOT NULL DEFAULT false
+);
+
+CREATE TABLE "idempotency_keys" (
+  "username" varchar NOT NULL,
+  "idempotency_key" varchar NOT NULL,
+  "request_hash" varchar NOT NULL,
+  "transfer_id" bigint,
+  "response" jsonb NOT NULL DEFAULT '{}',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "expired_at" timestamptz NOT NULL,
+  PRIMARY KEY ("username", "idempotency_key")
+);
+
+CREATE TABLE "user_totps" (
+  "username" varchar PRIMARY KEY,
+  "secret" varchar NOT NULL,
+  "is_confirmed" boolean NOT NULL DEFAULT false,
+  "last_used_step" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "confirmed_at" timestamptz
+);
+
+CREATE TABLE "recovery_codes" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "hashed_code" varchar NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "mfa_challenges" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "attempts" int NOT NULL DEFAULT 0,
+  "is_used" boolean NOT NULL DEFAULT false,
+  "expired_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "api_keys" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "hashed_key" varchar UNIQUE NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "is_revoked" boolean NOT NULL DEFAULT false,
+  "last_used_at" timestamptz,
+  "expired_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "exchange_rates" (
+  "id" bigserial PRIMARY KEY,
+  "base_currency" varchar NOT NULL,
+  "quote_currency" varchar NOT NULL,
+  "rate" numeric(20,10) NOT NULL,
+  "source" varchar NOT NULL,
+  "valid_from" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "transfer_quotes" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "credit_amount" bigint NOT NULL,
+  "exchange_rate_id" bigint NOT NULL,
+  "exchange_rate" numeric(20,10) NOT NULL,
+  "is_used" boolean NOT NULL DEFAULT false,
+  "expired_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "scheduled_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "schedule" varchar NOT NULL,
+  "starts_at" timestamptz NOT NULL,
+  "next_run_at" timestamptz NOT NULL,
+  "next_occurrence" int NOT NULL DEFAULT 0,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "failure_count" int NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "scheduled_transfer_runs" (
+  "id" bigserial PRIMARY KEY,
+  "scheduled_transfer_id" bigint NOT NULL,
+  "scheduled_at" timestamptz NOT NULL,
+  "status" varchar NOT NULL,
+  "transfer_id" bigint,
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "account_holds" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "captured_amount" bigint NOT NULL DEFAULT 0,
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "settled_at" timestamptz,
   "created_at" timestamptz NOT NULL DEFAULT (now())
 );
 
@@ -68,14 +211,86 @@ CREATE INDEX ON "transfers" ("to_account_id");
 
 CREATE INDEX ON "transfers" ("from_account_id", "to_account_id");
 
+CREATE INDEX ON "accounts" ("owner", "created_at", "id");
+
+CREATE INDEX ON "entries" ("account_id", "created_at", "id");
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at", "id");
+
+CREATE INDEX ON "transfers" ("to_account_id", "created_at", "id");
+
+CREATE INDEX ON "transfers" ("reversal_of");
+
+CREATE INDEX ON "sessions" ("family_id");
+
+CREATE UNIQUE INDEX ON "recovery_codes" ("username", "hashed_code");
+
+CREATE INDEX ON "mfa_challenges" ("username");
+
+CREATE INDEX ON "password_resets" ("username");
+
+CREATE INDEX ON "api_keys" ("username");
+
+CREATE INDEX ON "exchange_rates" ("base_currency", "quote_currency", "valid_from");
+
+CREATE INDEX ON "transfer_quotes" ("username");
+
+CREATE INDEX ON "scheduled_transfers" ("owner");
+
+CREATE INDEX ON "scheduled_transfers" ("status", "next_run_at");
+
+CREATE UNIQUE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id", "scheduled_at");
+
+CREATE INDEX ON "account_holds" ("owner");
+
+CREATE INDEX ON "account_holds" ("account_id");
+
+CREATE INDEX ON "account_holds" ("status", "expires_at");
+
+COMMENT ON COLUMN "currencies"."code" IS 'ISO 4217 currency code';
+
+COMMENT ON COLUMN "currencies"."minor_unit" IS 'number of decimal places of the currency, amounts are stored in hundredths so it is at most 2';
+
+COMMENT ON COLUMN "accounts"."balance" IS 'must not be negative';
+
+COMMENT ON COLUMN "accounts"."held_amount" IS 'sum of the active holds, the available balance is balance - held_amount';
+
 COMMENT ON COLUMN "entries"."amount" IS 'can be negative or positive';
 
 COMMENT ON COLUMN "transfers"."amount" IS 'It must be positive';
 
+COMMENT ON COLUMN "transfers"."credit_amount" IS 'amount credited to to_account_id, in its currency';
+
+COMMENT ON COLUMN "transfers"."reversal_of" IS 'the transfer this one reverses, fully or partially';
+
+COMMENT ON COLUMN "transfers"."reason" IS 'why the transfer was reversed, required for reversals';
+
+COMMENT ON COLUMN "exchange_rates"."rate" IS 'units of quote_currency for one unit of base_currency, must be positive';
+
+COMMENT ON COLUMN "scheduled_transfers"."amount" IS 'must be positive';
+
+COMMENT ON COLUMN "scheduled_transfers"."schedule" IS 'RRULE subset: FREQ=DAILY|WEEKLY|MONTHLY with optional INTERVAL, and COUNT or UNTIL';
+
+COMMENT ON COLUMN "scheduled_transfers"."next_occurrence" IS 'index of next_run_at among the occurrences of the schedule';
+
+COMMENT ON COLUMN "scheduled_transfers"."status" IS 'active, paused, suspended, completed or cancelled';
+
+COMMENT ON COLUMN "scheduled_transfers"."failure_count" IS 'runs in a row that failed for insufficient funds';
+
+COMMENT ON COLUMN "scheduled_transfer_runs"."status" IS 'succeeded or failed';
+
+COMMENT ON COLUMN "account_holds"."amount" IS 'reserved on account_id until the hold is captured, released or expires';
+
+COMMENT ON COLUMN "account_holds"."status" IS 'active, captured, released or expired';
+
+COMMENT ON COLUMN "account_holds"."captured_amount" IS 'transferred to to_account_id by the capture, the rest of the amount is released';
+
 ALTER TABLE "verify_emails" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
 
 ALTER TABLE "accounts" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
 
+ALTER TABLE "accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
 ALTER TABLE "entries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
 
 ALTER TABLE "transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
@@ -83,3 +298,55 @@ ALTER TABLE "transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts
 ALTER TABLE "transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
 
 ALTER TABLE "sessions" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "user_totps" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "recovery_codes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "mfa_challenges" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "password_resets" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "api_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("base_currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("quote_currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "transfer_quotes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_quotes" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_quotes" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_quotes" ADD FOREIGN KEY ("exchange_rate_id") REFERENCES "exchange_rates" ("id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("quote_id") REFERENCES "transfer_quotes" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("scheduled_transfer_id") REFERENCES "scheduled_transfers" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "account_holds" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "account_holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_holds" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_holds" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "account_holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reversal_of") REFERENCES "transfers" ("id");
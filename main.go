@@ -6,6 +6,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog"
@@ -27,6 +28,7 @@ import (
 	"github.com/sangketkit01/simple-bank/util"
 	"github.com/sangketkit01/simple-bank/worker"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -50,14 +52,20 @@ func main() {
 	runDBMigration(config.MigrationUrl, config.DBSource)
 
 	store := db.NewStore(conn)
+	if err := db.LoadCurrencies(context.Background(), store); err != nil {
+		log.Fatal().Msgf("cannot load currencies: %s", err)
+	}
+	go runCurrencyRefresher(config, store)
+
 	redisOpt := asynq.RedisClientOpt{
 		Addr: config.RedisAddress,
 	}
 
 	taskDistributor := worker.NewRedisTaskDistributor(redisOpt)
 
-	go runTaskProcessor(config, redisOpt, store)
-	go runGatewayServer(config, store, taskDistributor)
+	go runTaskProcessor(config, redisOpt, store, taskDistributor)
+	go runTaskScheduler(config, redisOpt)
+	go runGatewayServer(config)
 	runGrpcServer(config, store, taskDistributor)
 
 }
@@ -75,9 +83,26 @@ func runDBMigration(migrationURL string, dbSource string) {
 	log.Info().Msg("db migrated successfully")
 }
 
-func runTaskProcessor(config util.Config, redisOpt asynq.RedisClientOpt, store db.Store) {
+// runCurrencyRefresher reloads the currencies table periodically,
+// so enabling a currency or adding a new one doesn't need a restart
+func runCurrencyRefresher(config util.Config, store db.Store) {
+	if config.CurrencyRefreshInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(config.CurrencyRefreshInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := db.LoadCurrencies(context.Background(), store); err != nil {
+			log.Error().Err(err).Msg("cannot refresh currencies")
+		}
+	}
+}
+
+func runTaskProcessor(config util.Config, redisOpt asynq.RedisClientOpt, store db.Store, taskDistributor worker.TaskDistributor) {
 	mailer := mail.NewGmailSender(config.EmailSenderName, config.EmailSenderAddress, config.EmailSenderPassword)
-	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, mailer)
+	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, mailer, taskDistributor, config.AppBaseURL)
 	log.Info().Msg("start task processor")
 	err := taskProcessor.Start()
 	if err != nil {
@@ -85,14 +110,30 @@ func runTaskProcessor(config util.Config, redisOpt asynq.RedisClientOpt, store d
 	}
 }
 
+// runTaskScheduler periodically enqueues the dispatch of due scheduled transfers and the expiry of stale holds
+func runTaskScheduler(config util.Config, redisOpt asynq.RedisClientOpt) {
+	scheduler, err := worker.NewTaskScheduler(redisOpt, config.ScheduledTransferDispatchInterval, config.HoldExpiryInterval)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create task scheduler")
+	}
+
+	log.Info().Msg("start task scheduler")
+	err = scheduler.Run()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to start task scheduler")
+	}
+}
+
 func runGrpcServer(config util.Config, store db.Store, taskDestributor worker.TaskDistributor) {
 	server, err := apigrpc.NewServer(config, store, taskDestributor)
 	if err != nil {
 		log.Error().Msgf("cannot create server: %s", err)
 	}
 
-	grpcLogger := grpc.UnaryInterceptor(apigrpc.GrpcLogger)
-	grpcServer := grpc.NewServer(grpcLogger)
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(apigrpc.GrpcLogger, server.UnaryAuthInterceptor),
+		grpc.ChainStreamInterceptor(server.StreamAuthInterceptor),
+	)
 
 	pb.RegisterSimpleBankServer(grpcServer, server)
 	reflection.Register(grpcServer)
@@ -111,12 +152,8 @@ func runGrpcServer(config util.Config, store db.Store, taskDestributor worker.Ta
 	log.Info().Msg("server started!")
 }
 
-func runGatewayServer(config util.Config, store db.Store, taskDestributor worker.TaskDistributor) {
-	server, err := apigrpc.NewServer(config, store, taskDestributor)
-	if err != nil {
-		log.Error().Msgf("cannot create server: %s", err)
-	}
-
+// runGatewayServer proxies HTTP requests to the gRPC server, so they pass through the same interceptors
+func runGatewayServer(config util.Config) {
 	jsonOption := runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
 		MarshalOptions: protojson.MarshalOptions{
 			UseProtoNames: true,
@@ -125,11 +162,13 @@ func runGatewayServer(config util.Config, store db.Store, taskDestributor worker
 			DiscardUnknown: true,
 		},
 	})
-	grpcMux := runtime.NewServeMux(jsonOption)
+	headerMatcher := runtime.WithIncomingHeaderMatcher(apigrpc.GatewayHeaderMatcher)
+	grpcMux := runtime.NewServeMux(jsonOption, headerMatcher)
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	err = pb.RegisterSimpleBankHandlerServer(ctx, grpcMux, server)
+	dialOptions := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	err := pb.RegisterSimpleBankHandlerFromEndpoint(ctx, grpcMux, config.GrpcServerAddress, dialOptions)
 	if err != nil {
 		log.Error().Msgf("cannot register handler server: %s", err)
 	}
@@ -0,0 +1,130 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.30.2
+// source: direction.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Direction of money movement, seen from the listed account
+type Direction int32
+
+const (
+	Direction_DIRECTION_UNSPECIFIED Direction = 0
+	Direction_DIRECTION_INCOMING    Direction = 1
+	Direction_DIRECTION_OUTGOING    Direction = 2
+)
+
+// Enum value maps for Direction.
+var (
+	Direction_name = map[int32]string{
+		0: "DIRECTION_UNSPECIFIED",
+		1: "DIRECTION_INCOMING",
+		2: "DIRECTION_OUTGOING",
+	}
+	Direction_value = map[string]int32{
+		"DIRECTION_UNSPECIFIED": 0,
+		"DIRECTION_INCOMING":    1,
+		"DIRECTION_OUTGOING":    2,
+	}
+)
+
+func (x Direction) Enum() *Direction {
+	p := new(Direction)
+	*p = x
+	return p
+}
+
+func (x Direction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Direction) Descriptor() protoreflect.EnumDescriptor {
+	return file_direction_proto_enumTypes[0].Descriptor()
+}
+
+func (Direction) Type() protoreflect.EnumType {
+	return &file_direction_proto_enumTypes[0]
+}
+
+func (x Direction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Direction.Descriptor instead.
+func (Direction) EnumDescriptor() ([]byte, []int) {
+	return file_direction_proto_rawDescGZIP(), []int{0}
+}
+
+var File_direction_proto protoreflect.FileDescriptor
+
+const file_direction_proto_rawDesc = "" +
+	"\n" +
+	"\x0fdirection.proto\x12\x02pb*V\n" +
+	"\tDirection\x12\x19\n" +
+	"\x15DIRECTION_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12DIRECTION_INCOMING\x10\x01\x12\x16\n" +
+	"\x12DIRECTION_OUTGOING\x10\x02B(Z&github.com/sangketkit01/simple-bank/pbb\x06proto3"
+
+var (
+	file_direction_proto_rawDescOnce sync.Once
+	file_direction_proto_rawDescData []byte
+)
+
+func file_direction_proto_rawDescGZIP() []byte {
+	file_direction_proto_rawDescOnce.Do(func() {
+		file_direction_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_direction_proto_rawDesc), len(file_direction_proto_rawDesc)))
+	})
+	return file_direction_proto_rawDescData
+}
+
+var file_direction_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_direction_proto_goTypes = []any{
+	(Direction)(0), // 0: pb.Direction
+}
+var file_direction_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_direction_proto_init() }
+func file_direction_proto_init() {
+	if File_direction_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_direction_proto_rawDesc), len(file_direction_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_direction_proto_goTypes,
+		DependencyIndexes: file_direction_proto_depIdxs,
+		EnumInfos:         file_direction_proto_enumTypes,
+	}.Build()
+	File_direction_proto = out.File
+	file_direction_proto_goTypes = nil
+	file_direction_proto_depIdxs = nil
+}
@@ -82,8 +82,13 @@ type LoginUserResponse struct {
 	RefreshToken        string                 `protobuf:"bytes,4,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
 	AccessTokenExpired  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=access_token_expired,json=accessTokenExpired,proto3" json:"access_token_expired,omitempty"`
 	RefreshTokenExpired *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=refresh_token_expired,json=refreshTokenExpired,proto3" json:"refresh_token_expired,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+	// when mfa_required is set no session is created yet, the mfa_token
+	// has to be exchanged for the tokens with VerifyMFA
+	MfaRequired     bool                   `protobuf:"varint,7,opt,name=mfa_required,json=mfaRequired,proto3" json:"mfa_required,omitempty"`
+	MfaToken        string                 `protobuf:"bytes,8,opt,name=mfa_token,json=mfaToken,proto3" json:"mfa_token,omitempty"`
+	MfaTokenExpired *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=mfa_token_expired,json=mfaTokenExpired,proto3" json:"mfa_token_expired,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *LoginUserResponse) Reset() {
@@ -158,6 +163,27 @@ func (x *LoginUserResponse) GetRefreshTokenExpired() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *LoginUserResponse) GetMfaRequired() bool {
+	if x != nil {
+		return x.MfaRequired
+	}
+	return false
+}
+
+func (x *LoginUserResponse) GetMfaToken() string {
+	if x != nil {
+		return x.MfaToken
+	}
+	return ""
+}
+
+func (x *LoginUserResponse) GetMfaTokenExpired() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MfaTokenExpired
+	}
+	return nil
+}
+
 var File_rpc_login_user_proto protoreflect.FileDescriptor
 
 const file_rpc_login_user_proto_rawDesc = "" +
@@ -166,7 +192,7 @@ const file_rpc_login_user_proto_rawDesc = "" +
 	"user.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"J\n" +
 	"\x10LoginUserRequest\x12\x1a\n" +
 	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"\xb6\x02\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"\xbe\x03\n" +
 	"\x11LoginUserResponse\x12\x1c\n" +
 	"\x04user\x18\x01 \x01(\v2\b.pb.UserR\x04user\x12\x1d\n" +
 	"\n" +
@@ -174,7 +200,10 @@ const file_rpc_login_user_proto_rawDesc = "" +
 	"\faccess_token\x18\x03 \x01(\tR\vaccessToken\x12#\n" +
 	"\rrefresh_token\x18\x04 \x01(\tR\frefreshToken\x12L\n" +
 	"\x14access_token_expired\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x12accessTokenExpired\x12N\n" +
-	"\x15refresh_token_expired\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x13refreshTokenExpiredB(Z&github.com/sangketkit01/simple-bank/pbb\x06proto3"
+	"\x15refresh_token_expired\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x13refreshTokenExpired\x12!\n" +
+	"\fmfa_required\x18\a \x01(\bR\vmfaRequired\x12\x1b\n" +
+	"\tmfa_token\x18\b \x01(\tR\bmfaToken\x12F\n" +
+	"\x11mfa_token_expired\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\x0fmfaTokenExpiredB(Z&github.com/sangketkit01/simple-bank/pbb\x06proto3"
 
 var (
 	file_rpc_login_user_proto_rawDescOnce sync.Once
@@ -199,11 +228,12 @@ var file_rpc_login_user_proto_depIdxs = []int32{
 	2, // 0: pb.LoginUserResponse.user:type_name -> pb.User
 	3, // 1: pb.LoginUserResponse.access_token_expired:type_name -> google.protobuf.Timestamp
 	3, // 2: pb.LoginUserResponse.refresh_token_expired:type_name -> google.protobuf.Timestamp
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	3, // 3: pb.LoginUserResponse.mfa_token_expired:type_name -> google.protobuf.Timestamp
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_rpc_login_user_proto_init() }
@@ -0,0 +1,151 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.30.2
+// source: rpc_resend_verify_email.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ResendVerifyEmailRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResendVerifyEmailRequest) Reset() {
+	*x = ResendVerifyEmailRequest{}
+	mi := &file_rpc_resend_verify_email_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResendVerifyEmailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResendVerifyEmailRequest) ProtoMessage() {}
+
+func (x *ResendVerifyEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_resend_verify_email_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResendVerifyEmailRequest.ProtoReflect.Descriptor instead.
+func (*ResendVerifyEmailRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_resend_verify_email_proto_rawDescGZIP(), []int{0}
+}
+
+type ResendVerifyEmailResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResendVerifyEmailResponse) Reset() {
+	*x = ResendVerifyEmailResponse{}
+	mi := &file_rpc_resend_verify_email_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResendVerifyEmailResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResendVerifyEmailResponse) ProtoMessage() {}
+
+func (x *ResendVerifyEmailResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_resend_verify_email_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResendVerifyEmailResponse.ProtoReflect.Descriptor instead.
+func (*ResendVerifyEmailResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_resend_verify_email_proto_rawDescGZIP(), []int{1}
+}
+
+var File_rpc_resend_verify_email_proto protoreflect.FileDescriptor
+
+const file_rpc_resend_verify_email_proto_rawDesc = "" +
+	"\n" +
+	"\x1drpc_resend_verify_email.proto\x12\x02pb\"\x1a\n" +
+	"\x18ResendVerifyEmailRequest\"\x1b\n" +
+	"\x19ResendVerifyEmailResponseB(Z&github.com/sangketkit01/simple-bank/pbb\x06proto3"
+
+var (
+	file_rpc_resend_verify_email_proto_rawDescOnce sync.Once
+	file_rpc_resend_verify_email_proto_rawDescData []byte
+)
+
+func file_rpc_resend_verify_email_proto_rawDescGZIP() []byte {
+	file_rpc_resend_verify_email_proto_rawDescOnce.Do(func() {
+		file_rpc_resend_verify_email_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_resend_verify_email_proto_rawDesc), len(file_rpc_resend_verify_email_proto_rawDesc)))
+	})
+	return file_rpc_resend_verify_email_proto_rawDescData
+}
+
+var file_rpc_resend_verify_email_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_resend_verify_email_proto_goTypes = []any{
+	(*ResendVerifyEmailRequest)(nil),  // 0: pb.ResendVerifyEmailRequest
+	(*ResendVerifyEmailResponse)(nil), // 1: pb.ResendVerifyEmailResponse
+}
+var file_rpc_resend_verify_email_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_rpc_resend_verify_email_proto_init() }
+func file_rpc_resend_verify_email_proto_init() {
+	if File_rpc_resend_verify_email_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_resend_verify_email_proto_rawDesc), len(file_rpc_resend_verify_email_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_resend_verify_email_proto_goTypes,
+		DependencyIndexes: file_rpc_resend_verify_email_proto_depIdxs,
+		MessageInfos:      file_rpc_resend_verify_email_proto_msgTypes,
+	}.Build()
+	File_rpc_resend_verify_email_proto = out.File
+	file_rpc_resend_verify_email_proto_goTypes = nil
+	file_rpc_resend_verify_email_proto_depIdxs = nil
+}
@@ -0,0 +1,242 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.30.2
+// source: rpc_verify_mfa.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type VerifyMFARequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	MfaToken string                 `protobuf:"bytes,1,opt,name=mfa_token,json=mfaToken,proto3" json:"mfa_token,omitempty"`
+	// exactly one of code and recovery_code must be set
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	RecoveryCode  string `protobuf:"bytes,3,opt,name=recovery_code,json=recoveryCode,proto3" json:"recovery_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyMFARequest) Reset() {
+	*x = VerifyMFARequest{}
+	mi := &file_rpc_verify_mfa_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyMFARequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyMFARequest) ProtoMessage() {}
+
+func (x *VerifyMFARequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_verify_mfa_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyMFARequest.ProtoReflect.Descriptor instead.
+func (*VerifyMFARequest) Descriptor() ([]byte, []int) {
+	return file_rpc_verify_mfa_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *VerifyMFARequest) GetMfaToken() string {
+	if x != nil {
+		return x.MfaToken
+	}
+	return ""
+}
+
+func (x *VerifyMFARequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *VerifyMFARequest) GetRecoveryCode() string {
+	if x != nil {
+		return x.RecoveryCode
+	}
+	return ""
+}
+
+type VerifyMFAResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	User                *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	SessionId           string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	AccessToken         string                 `protobuf:"bytes,3,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	RefreshToken        string                 `protobuf:"bytes,4,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	AccessTokenExpired  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=access_token_expired,json=accessTokenExpired,proto3" json:"access_token_expired,omitempty"`
+	RefreshTokenExpired *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=refresh_token_expired,json=refreshTokenExpired,proto3" json:"refresh_token_expired,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *VerifyMFAResponse) Reset() {
+	*x = VerifyMFAResponse{}
+	mi := &file_rpc_verify_mfa_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyMFAResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyMFAResponse) ProtoMessage() {}
+
+func (x *VerifyMFAResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_verify_mfa_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyMFAResponse.ProtoReflect.Descriptor instead.
+func (*VerifyMFAResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_verify_mfa_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VerifyMFAResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *VerifyMFAResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *VerifyMFAResponse) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *VerifyMFAResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *VerifyMFAResponse) GetAccessTokenExpired() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AccessTokenExpired
+	}
+	return nil
+}
+
+func (x *VerifyMFAResponse) GetRefreshTokenExpired() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RefreshTokenExpired
+	}
+	return nil
+}
+
+var File_rpc_verify_mfa_proto protoreflect.FileDescriptor
+
+const file_rpc_verify_mfa_proto_rawDesc = "" +
+	"\n" +
+	"\x14rpc_verify_mfa.proto\x12\x02pb\x1a\n" +
+	"user.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"h\n" +
+	"\x10VerifyMFARequest\x12\x1b\n" +
+	"\tmfa_token\x18\x01 \x01(\tR\bmfaToken\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12#\n" +
+	"\rrecovery_code\x18\x03 \x01(\tR\frecoveryCode\"\xb6\x02\n" +
+	"\x11VerifyMFAResponse\x12\x1c\n" +
+	"\x04user\x18\x01 \x01(\v2\b.pb.UserR\x04user\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12!\n" +
+	"\faccess_token\x18\x03 \x01(\tR\vaccessToken\x12#\n" +
+	"\rrefresh_token\x18\x04 \x01(\tR\frefreshToken\x12L\n" +
+	"\x14access_token_expired\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x12accessTokenExpired\x12N\n" +
+	"\x15refresh_token_expired\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x13refreshTokenExpiredB(Z&github.com/sangketkit01/simple-bank/pbb\x06proto3"
+
+var (
+	file_rpc_verify_mfa_proto_rawDescOnce sync.Once
+	file_rpc_verify_mfa_proto_rawDescData []byte
+)
+
+func file_rpc_verify_mfa_proto_rawDescGZIP() []byte {
+	file_rpc_verify_mfa_proto_rawDescOnce.Do(func() {
+		file_rpc_verify_mfa_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_verify_mfa_proto_rawDesc), len(file_rpc_verify_mfa_proto_rawDesc)))
+	})
+	return file_rpc_verify_mfa_proto_rawDescData
+}
+
+var file_rpc_verify_mfa_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_verify_mfa_proto_goTypes = []any{
+	(*VerifyMFARequest)(nil),      // 0: pb.VerifyMFARequest
+	(*VerifyMFAResponse)(nil),     // 1: pb.VerifyMFAResponse
+	(*User)(nil),                  // 2: pb.User
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+}
+var file_rpc_verify_mfa_proto_depIdxs = []int32{
+	2, // 0: pb.VerifyMFAResponse.user:type_name -> pb.User
+	3, // 1: pb.VerifyMFAResponse.access_token_expired:type_name -> google.protobuf.Timestamp
+	3, // 2: pb.VerifyMFAResponse.refresh_token_expired:type_name -> google.protobuf.Timestamp
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_rpc_verify_mfa_proto_init() }
+func file_rpc_verify_mfa_proto_init() {
+	if File_rpc_verify_mfa_proto != nil {
+		return
+	}
+	file_user_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_verify_mfa_proto_rawDesc), len(file_rpc_verify_mfa_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_verify_mfa_proto_goTypes,
+		DependencyIndexes: file_rpc_verify_mfa_proto_depIdxs,
+		MessageInfos:      file_rpc_verify_mfa_proto_msgTypes,
+	}.Build()
+	File_rpc_verify_mfa_proto = out.File
+	file_rpc_verify_mfa_proto_goTypes = nil
+	file_rpc_verify_mfa_proto_depIdxs = nil
+}
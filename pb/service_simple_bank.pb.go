@@ -26,7 +26,7 @@ var File_service_simple_bank_proto protoreflect.FileDescriptor
 
 const file_service_simple_bank_proto_rawDesc = "" +
 	"\n" +
-	"\x19service_simple_bank.proto\x12\x02pb\x1a\x15rpc_create_user.proto\x1a\x14rpc_login_user.proto\x1a\x15rpc_update_user.proto\x1a\x16rpc_verify_email.proto\x1a\x1cgoogle/api/annotations.proto\x1a.protoc-gen-openapiv2/options/annotations.proto2\xed\x02\n" +
+	"\x19service_simple_bank.proto\x12\x02pb\x1a\x15rpc_create_user.proto\x1a\x14rpc_login_user.proto\x1a\x15rpc_update_user.proto\x1a\x16rpc_verify_email.proto\x1a\x18rpc_create_account.proto\x1a\x15rpc_get_account.proto\x1a\x17rpc_list_accounts.proto\x1a\x19rpc_create_transfer.proto\x1a\x18rpc_quote_transfer.proto\x1a\x16rpc_list_entries.proto\x1a\x18rpc_list_transfers.proto\x1a\x1crpc_renew_access_token.proto\x1a\x10rpc_logout.proto\x1a\x1drpc_logout_all_sessions.proto\x1a\x17rpc_list_sessions.proto\x1a\x18rpc_revoke_session.proto\x1a\x15rpc_enroll_totp.proto\x1a\x16rpc_confirm_totp.proto\x1a\x14rpc_verify_mfa.proto\x1a rpc_request_password_reset.proto\x1a\x18rpc_reset_password.proto\x1a\x15rpc_unlock_user.proto\x1a\x19rpc_get_public_keys.proto\x1a\x18rpc_create_api_key.proto\x1a\x17rpc_list_api_keys.proto\x1a\x18rpc_revoke_api_key.proto\x1a\x1drpc_resend_verify_email.proto\x1a#rpc_create_scheduled_transfer.proto\x1a\"rpc_list_scheduled_transfers.proto\x1a rpc_get_scheduled_transfer.proto\x1a#rpc_update_scheduled_transfer.proto\x1a#rpc_delete_scheduled_transfer.proto\x1a\x15rpc_create_hold.proto\x1a\x16rpc_capture_hold.proto\x1a\x16rpc_release_hold.proto\x1a\x1arpc_reverse_transfer.proto\x1a\x1cgoogle/api/annotations.proto\x1a.protoc-gen-openapiv2/options/annotations.proto2\xfb\x1c\n" +
 	"\n" +
 	"SimpleBank\x12W\n" +
 	"\n" +
@@ -34,34 +34,200 @@ const file_service_simple_bank_proto_rawDesc = "" +
 	"\n" +
 	"UpdateUser\x12\x15.pb.UpdateUserRequest\x1a\x16.pb.UpdateUserResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*2\x0f/v1/update_user\x12S\n" +
 	"\tLoginUser\x12\x14.pb.LoginUserRequest\x1a\x15.pb.LoginUserResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/login_user\x12X\n" +
-	"\vVerifyEmail\x12\x16.pb.VerifyEmailRequest\x1a\x17.pb.VerifyEmailResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/verify_emailB\x91\x01\x92Af\x12d\n" +
+	"\vVerifyEmail\x12\x16.pb.VerifyEmailRequest\x1a\x17.pb.VerifyEmailResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/verify_email\x12]\n" +
+	"\rCreateAccount\x12\x18.pb.CreateAccountRequest\x1a\x19.pb.CreateAccountResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/accounts\x12V\n" +
+	"\n" +
+	"GetAccount\x12\x15.pb.GetAccountRequest\x1a\x16.pb.GetAccountResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/accounts/{id}\x12W\n" +
+	"\fListAccounts\x12\x17.pb.ListAccountsRequest\x1a\x18.pb.ListAccountsResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/accounts\x12a\n" +
+	"\x0eCreateTransfer\x12\x19.pb.CreateTransferRequest\x1a\x1a.pb.CreateTransferResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/transfers\x12d\n" +
+	"\rQuoteTransfer\x12\x18.pb.QuoteTransferRequest\x1a\x19.pb.QuoteTransferResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/transfers/quote\x12i\n" +
+	"\vListEntries\x12\x16.pb.ListEntriesRequest\x1a\x17.pb.ListEntriesResponse\")\x82\xd3\xe4\x93\x02#\x12!/v1/accounts/{account_id}/entries\x12q\n" +
+	"\rListTransfers\x12\x18.pb.ListTransfersRequest\x1a\x19.pb.ListTransfersResponse\"+\x82\xd3\xe4\x93\x02%\x12#/v1/accounts/{account_id}/transfers\x12q\n" +
+	"\x10RenewAccessToken\x12\x1b.pb.RenewAccessTokenRequest\x1a\x1c.pb.RenewAccessTokenResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/tokens/renew_access\x12F\n" +
+	"\x06Logout\x12\x11.pb.LogoutRequest\x1a\x12.pb.LogoutResponse\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
+	"/v1/logout\x12t\n" +
+	"\x11LogoutAllSessions\x12\x1c.pb.LogoutAllSessionsRequest\x1a\x1d.pb.LogoutAllSessionsResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/logout_all_sessions\x12W\n" +
+	"\fListSessions\x12\x17.pb.ListSessionsRequest\x1a\x18.pb.ListSessionsResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/sessions\x12g\n" +
+	"\rRevokeSession\x12\x18.pb.RevokeSessionRequest\x1a\x19.pb.RevokeSessionResponse\"!\x82\xd3\xe4\x93\x02\x1b*\x19/v1/sessions/{session_id}\x12W\n" +
+	"\n" +
+	"EnrollTOTP\x12\x15.pb.EnrollTOTPRequest\x1a\x16.pb.EnrollTOTPResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/totp/enroll\x12[\n" +
+	"\vConfirmTOTP\x12\x16.pb.ConfirmTOTPRequest\x1a\x17.pb.ConfirmTOTPResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/totp/confirm\x12S\n" +
+	"\tVerifyMFA\x12\x14.pb.VerifyMFARequest\x1a\x15.pb.VerifyMFAResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/verify_mfa\x12\x80\x01\n" +
+	"\x14RequestPasswordReset\x12\x1f.pb.RequestPasswordResetRequest\x1a .pb.RequestPasswordResetResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/password_reset/request\x12k\n" +
+	"\rResetPassword\x12\x18.pb.ResetPasswordRequest\x1a\x19.pb.ResetPasswordResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/password_reset/confirm\x12W\n" +
+	"\n" +
+	"UnlockUser\x12\x15.pb.UnlockUserRequest\x1a\x16.pb.UnlockUserResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/unlock_user\x12V\n" +
+	"\rGetPublicKeys\x12\x18.pb.GetPublicKeysRequest\x1a\x19.pb.GetPublicKeysResponse\"\x10\x82\xd3\xe4\x93\x02\n" +
+	"\x12\b/v1/keys\x12Z\n" +
+	"\fCreateAPIKey\x12\x17.pb.CreateAPIKeyRequest\x1a\x18.pb.CreateAPIKeyResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/api_keys\x12T\n" +
+	"\vListAPIKeys\x12\x16.pb.ListAPIKeysRequest\x1a\x17.pb.ListAPIKeysResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/api_keys\x12\\\n" +
+	"\fRevokeAPIKey\x12\x17.pb.RevokeAPIKeyRequest\x1a\x18.pb.RevokeAPIKeyResponse\"\x19\x82\xd3\xe4\x93\x02\x13*\x11/v1/api_keys/{id}\x12t\n" +
+	"\x11ResendVerifyEmail\x12\x1c.pb.ResendVerifyEmailRequest\x1a\x1d.pb.ResendVerifyEmailResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/resend_verify_email\x12\x86\x01\n" +
+	"\x17CreateScheduledTransfer\x12\".pb.CreateScheduledTransferRequest\x1a#.pb.CreateScheduledTransferResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/scheduled_transfers\x12\x80\x01\n" +
+	"\x16ListScheduledTransfers\x12!.pb.ListScheduledTransfersRequest\x1a\".pb.ListScheduledTransfersResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/scheduled_transfers\x12\x7f\n" +
+	"\x14GetScheduledTransfer\x12\x1f.pb.GetScheduledTransferRequest\x1a .pb.GetScheduledTransferResponse\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/v1/scheduled_transfers/{id}\x12\x8b\x01\n" +
+	"\x17UpdateScheduledTransfer\x12\".pb.UpdateScheduledTransferRequest\x1a#.pb.UpdateScheduledTransferResponse\"'\x82\xd3\xe4\x93\x02!:\x01*2\x1c/v1/scheduled_transfers/{id}\x12\x88\x01\n" +
+	"\x17DeleteScheduledTransfer\x12\".pb.DeleteScheduledTransferRequest\x1a#.pb.DeleteScheduledTransferResponse\"$\x82\xd3\xe4\x93\x02\x1e*\x1c/v1/scheduled_transfers/{id}\x12Q\n" +
+	"\n" +
+	"CreateHold\x12\x15.pb.CreateHoldRequest\x1a\x16.pb.CreateHoldResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/holds\x12a\n" +
+	"\vCaptureHold\x12\x16.pb.CaptureHoldRequest\x1a\x17.pb.CaptureHoldResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/holds/{id}/capture\x12a\n" +
+	"\vReleaseHold\x12\x16.pb.ReleaseHoldRequest\x1a\x17.pb.ReleaseHoldResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/holds/{id}/release\x12q\n" +
+	"\x0fReverseTransfer\x12\x1a.pb.ReverseTransferRequest\x1a\x1b.pb.ReverseTransferResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/transfers/{id}/reverseB\x91\x01\x92Af\x12d\n" +
 	"\x0fSimple Bank API\"L\n" +
 	"\x0eThiraphatDotSa\x12\x1fhttps://github.com/sangketkit01\x1a\x19thiraphat_120@hotmail.com2\x031.1Z&github.com/sangketkit01/simple-bank/pbb\x06proto3"
 
 var file_service_simple_bank_proto_goTypes = []any{
-	(*CreateUserRequest)(nil),   // 0: pb.CreateUserRequest
-	(*UpdateUserRequest)(nil),   // 1: pb.UpdateUserRequest
-	(*LoginUserRequest)(nil),    // 2: pb.LoginUserRequest
-	(*VerifyEmailRequest)(nil),  // 3: pb.VerifyEmailRequest
-	(*CreateUserResponse)(nil),  // 4: pb.CreateUserResponse
-	(*UpdateUserResponse)(nil),  // 5: pb.UpdateUserResponse
-	(*LoginUserResponse)(nil),   // 6: pb.LoginUserResponse
-	(*VerifyEmailResponse)(nil), // 7: pb.VerifyEmailResponse
+	(*CreateUserRequest)(nil),               // 0: pb.CreateUserRequest
+	(*UpdateUserRequest)(nil),               // 1: pb.UpdateUserRequest
+	(*LoginUserRequest)(nil),                // 2: pb.LoginUserRequest
+	(*VerifyEmailRequest)(nil),              // 3: pb.VerifyEmailRequest
+	(*CreateAccountRequest)(nil),            // 4: pb.CreateAccountRequest
+	(*GetAccountRequest)(nil),               // 5: pb.GetAccountRequest
+	(*ListAccountsRequest)(nil),             // 6: pb.ListAccountsRequest
+	(*CreateTransferRequest)(nil),           // 7: pb.CreateTransferRequest
+	(*QuoteTransferRequest)(nil),            // 8: pb.QuoteTransferRequest
+	(*ListEntriesRequest)(nil),              // 9: pb.ListEntriesRequest
+	(*ListTransfersRequest)(nil),            // 10: pb.ListTransfersRequest
+	(*RenewAccessTokenRequest)(nil),         // 11: pb.RenewAccessTokenRequest
+	(*LogoutRequest)(nil),                   // 12: pb.LogoutRequest
+	(*LogoutAllSessionsRequest)(nil),        // 13: pb.LogoutAllSessionsRequest
+	(*ListSessionsRequest)(nil),             // 14: pb.ListSessionsRequest
+	(*RevokeSessionRequest)(nil),            // 15: pb.RevokeSessionRequest
+	(*EnrollTOTPRequest)(nil),               // 16: pb.EnrollTOTPRequest
+	(*ConfirmTOTPRequest)(nil),              // 17: pb.ConfirmTOTPRequest
+	(*VerifyMFARequest)(nil),                // 18: pb.VerifyMFARequest
+	(*RequestPasswordResetRequest)(nil),     // 19: pb.RequestPasswordResetRequest
+	(*ResetPasswordRequest)(nil),            // 20: pb.ResetPasswordRequest
+	(*UnlockUserRequest)(nil),               // 21: pb.UnlockUserRequest
+	(*GetPublicKeysRequest)(nil),            // 22: pb.GetPublicKeysRequest
+	(*CreateAPIKeyRequest)(nil),             // 23: pb.CreateAPIKeyRequest
+	(*ListAPIKeysRequest)(nil),              // 24: pb.ListAPIKeysRequest
+	(*RevokeAPIKeyRequest)(nil),             // 25: pb.RevokeAPIKeyRequest
+	(*ResendVerifyEmailRequest)(nil),        // 26: pb.ResendVerifyEmailRequest
+	(*CreateScheduledTransferRequest)(nil),  // 27: pb.CreateScheduledTransferRequest
+	(*ListScheduledTransfersRequest)(nil),   // 28: pb.ListScheduledTransfersRequest
+	(*GetScheduledTransferRequest)(nil),     // 29: pb.GetScheduledTransferRequest
+	(*UpdateScheduledTransferRequest)(nil),  // 30: pb.UpdateScheduledTransferRequest
+	(*DeleteScheduledTransferRequest)(nil),  // 31: pb.DeleteScheduledTransferRequest
+	(*CreateHoldRequest)(nil),               // 32: pb.CreateHoldRequest
+	(*CaptureHoldRequest)(nil),              // 33: pb.CaptureHoldRequest
+	(*ReleaseHoldRequest)(nil),              // 34: pb.ReleaseHoldRequest
+	(*ReverseTransferRequest)(nil),          // 35: pb.ReverseTransferRequest
+	(*CreateUserResponse)(nil),              // 36: pb.CreateUserResponse
+	(*UpdateUserResponse)(nil),              // 37: pb.UpdateUserResponse
+	(*LoginUserResponse)(nil),               // 38: pb.LoginUserResponse
+	(*VerifyEmailResponse)(nil),             // 39: pb.VerifyEmailResponse
+	(*CreateAccountResponse)(nil),           // 40: pb.CreateAccountResponse
+	(*GetAccountResponse)(nil),              // 41: pb.GetAccountResponse
+	(*ListAccountsResponse)(nil),            // 42: pb.ListAccountsResponse
+	(*CreateTransferResponse)(nil),          // 43: pb.CreateTransferResponse
+	(*QuoteTransferResponse)(nil),           // 44: pb.QuoteTransferResponse
+	(*ListEntriesResponse)(nil),             // 45: pb.ListEntriesResponse
+	(*ListTransfersResponse)(nil),           // 46: pb.ListTransfersResponse
+	(*RenewAccessTokenResponse)(nil),        // 47: pb.RenewAccessTokenResponse
+	(*LogoutResponse)(nil),                  // 48: pb.LogoutResponse
+	(*LogoutAllSessionsResponse)(nil),       // 49: pb.LogoutAllSessionsResponse
+	(*ListSessionsResponse)(nil),            // 50: pb.ListSessionsResponse
+	(*RevokeSessionResponse)(nil),           // 51: pb.RevokeSessionResponse
+	(*EnrollTOTPResponse)(nil),              // 52: pb.EnrollTOTPResponse
+	(*ConfirmTOTPResponse)(nil),             // 53: pb.ConfirmTOTPResponse
+	(*VerifyMFAResponse)(nil),               // 54: pb.VerifyMFAResponse
+	(*RequestPasswordResetResponse)(nil),    // 55: pb.RequestPasswordResetResponse
+	(*ResetPasswordResponse)(nil),           // 56: pb.ResetPasswordResponse
+	(*UnlockUserResponse)(nil),              // 57: pb.UnlockUserResponse
+	(*GetPublicKeysResponse)(nil),           // 58: pb.GetPublicKeysResponse
+	(*CreateAPIKeyResponse)(nil),            // 59: pb.CreateAPIKeyResponse
+	(*ListAPIKeysResponse)(nil),             // 60: pb.ListAPIKeysResponse
+	(*RevokeAPIKeyResponse)(nil),            // 61: pb.RevokeAPIKeyResponse
+	(*ResendVerifyEmailResponse)(nil),       // 62: pb.ResendVerifyEmailResponse
+	(*CreateScheduledTransferResponse)(nil), // 63: pb.CreateScheduledTransferResponse
+	(*ListScheduledTransfersResponse)(nil),  // 64: pb.ListScheduledTransfersResponse
+	(*GetScheduledTransferResponse)(nil),    // 65: pb.GetScheduledTransferResponse
+	(*UpdateScheduledTransferResponse)(nil), // 66: pb.UpdateScheduledTransferResponse
+	(*DeleteScheduledTransferResponse)(nil), // 67: pb.DeleteScheduledTransferResponse
+	(*CreateHoldResponse)(nil),              // 68: pb.CreateHoldResponse
+	(*CaptureHoldResponse)(nil),             // 69: pb.CaptureHoldResponse
+	(*ReleaseHoldResponse)(nil),             // 70: pb.ReleaseHoldResponse
+	(*ReverseTransferResponse)(nil),         // 71: pb.ReverseTransferResponse
 }
 var file_service_simple_bank_proto_depIdxs = []int32{
-	0, // 0: pb.SimpleBank.CreateUser:input_type -> pb.CreateUserRequest
-	1, // 1: pb.SimpleBank.UpdateUser:input_type -> pb.UpdateUserRequest
-	2, // 2: pb.SimpleBank.LoginUser:input_type -> pb.LoginUserRequest
-	3, // 3: pb.SimpleBank.VerifyEmail:input_type -> pb.VerifyEmailRequest
-	4, // 4: pb.SimpleBank.CreateUser:output_type -> pb.CreateUserResponse
-	5, // 5: pb.SimpleBank.UpdateUser:output_type -> pb.UpdateUserResponse
-	6, // 6: pb.SimpleBank.LoginUser:output_type -> pb.LoginUserResponse
-	7, // 7: pb.SimpleBank.VerifyEmail:output_type -> pb.VerifyEmailResponse
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: pb.SimpleBank.CreateUser:input_type -> pb.CreateUserRequest
+	1,  // 1: pb.SimpleBank.UpdateUser:input_type -> pb.UpdateUserRequest
+	2,  // 2: pb.SimpleBank.LoginUser:input_type -> pb.LoginUserRequest
+	3,  // 3: pb.SimpleBank.VerifyEmail:input_type -> pb.VerifyEmailRequest
+	4,  // 4: pb.SimpleBank.CreateAccount:input_type -> pb.CreateAccountRequest
+	5,  // 5: pb.SimpleBank.GetAccount:input_type -> pb.GetAccountRequest
+	6,  // 6: pb.SimpleBank.ListAccounts:input_type -> pb.ListAccountsRequest
+	7,  // 7: pb.SimpleBank.CreateTransfer:input_type -> pb.CreateTransferRequest
+	8,  // 8: pb.SimpleBank.QuoteTransfer:input_type -> pb.QuoteTransferRequest
+	9,  // 9: pb.SimpleBank.ListEntries:input_type -> pb.ListEntriesRequest
+	10, // 10: pb.SimpleBank.ListTransfers:input_type -> pb.ListTransfersRequest
+	11, // 11: pb.SimpleBank.RenewAccessToken:input_type -> pb.RenewAccessTokenRequest
+	12, // 12: pb.SimpleBank.Logout:input_type -> pb.LogoutRequest
+	13, // 13: pb.SimpleBank.LogoutAllSessions:input_type -> pb.LogoutAllSessionsRequest
+	14, // 14: pb.SimpleBank.ListSessions:input_type -> pb.ListSessionsRequest
+	15, // 15: pb.SimpleBank.RevokeSession:input_type -> pb.RevokeSessionRequest
+	16, // 16: pb.SimpleBank.EnrollTOTP:input_type -> pb.EnrollTOTPRequest
+	17, // 17: pb.SimpleBank.ConfirmTOTP:input_type -> pb.ConfirmTOTPRequest
+	18, // 18: pb.SimpleBank.VerifyMFA:input_type -> pb.VerifyMFARequest
+	19, // 19: pb.SimpleBank.RequestPasswordReset:input_type -> pb.RequestPasswordResetRequest
+	20, // 20: pb.SimpleBank.ResetPassword:input_type -> pb.ResetPasswordRequest
+	21, // 21: pb.SimpleBank.UnlockUser:input_type -> pb.UnlockUserRequest
+	22, // 22: pb.SimpleBank.GetPublicKeys:input_type -> pb.GetPublicKeysRequest
+	23, // 23: pb.SimpleBank.CreateAPIKey:input_type -> pb.CreateAPIKeyRequest
+	24, // 24: pb.SimpleBank.ListAPIKeys:input_type -> pb.ListAPIKeysRequest
+	25, // 25: pb.SimpleBank.RevokeAPIKey:input_type -> pb.RevokeAPIKeyRequest
+	26, // 26: pb.SimpleBank.ResendVerifyEmail:input_type -> pb.ResendVerifyEmailRequest
+	27, // 27: pb.SimpleBank.CreateScheduledTransfer:input_type -> pb.CreateScheduledTransferRequest
+	28, // 28: pb.SimpleBank.ListScheduledTransfers:input_type -> pb.ListScheduledTransfersRequest
+	29, // 29: pb.SimpleBank.GetScheduledTransfer:input_type -> pb.GetScheduledTransferRequest
+	30, // 30: pb.SimpleBank.UpdateScheduledTransfer:input_type -> pb.UpdateScheduledTransferRequest
+	31, // 31: pb.SimpleBank.DeleteScheduledTransfer:input_type -> pb.DeleteScheduledTransferRequest
+	32, // 32: pb.SimpleBank.CreateHold:input_type -> pb.CreateHoldRequest
+	33, // 33: pb.SimpleBank.CaptureHold:input_type -> pb.CaptureHoldRequest
+	34, // 34: pb.SimpleBank.ReleaseHold:input_type -> pb.ReleaseHoldRequest
+	35, // 35: pb.SimpleBank.ReverseTransfer:input_type -> pb.ReverseTransferRequest
+	36, // 36: pb.SimpleBank.CreateUser:output_type -> pb.CreateUserResponse
+	37, // 37: pb.SimpleBank.UpdateUser:output_type -> pb.UpdateUserResponse
+	38, // 38: pb.SimpleBank.LoginUser:output_type -> pb.LoginUserResponse
+	39, // 39: pb.SimpleBank.VerifyEmail:output_type -> pb.VerifyEmailResponse
+	40, // 40: pb.SimpleBank.CreateAccount:output_type -> pb.CreateAccountResponse
+	41, // 41: pb.SimpleBank.GetAccount:output_type -> pb.GetAccountResponse
+	42, // 42: pb.SimpleBank.ListAccounts:output_type -> pb.ListAccountsResponse
+	43, // 43: pb.SimpleBank.CreateTransfer:output_type -> pb.CreateTransferResponse
+	44, // 44: pb.SimpleBank.QuoteTransfer:output_type -> pb.QuoteTransferResponse
+	45, // 45: pb.SimpleBank.ListEntries:output_type -> pb.ListEntriesResponse
+	46, // 46: pb.SimpleBank.ListTransfers:output_type -> pb.ListTransfersResponse
+	47, // 47: pb.SimpleBank.RenewAccessToken:output_type -> pb.RenewAccessTokenResponse
+	48, // 48: pb.SimpleBank.Logout:output_type -> pb.LogoutResponse
+	49, // 49: pb.SimpleBank.LogoutAllSessions:output_type -> pb.LogoutAllSessionsResponse
+	50, // 50: pb.SimpleBank.ListSessions:output_type -> pb.ListSessionsResponse
+	51, // 51: pb.SimpleBank.RevokeSession:output_type -> pb.RevokeSessionResponse
+	52, // 52: pb.SimpleBank.EnrollTOTP:output_type -> pb.EnrollTOTPResponse
+	53, // 53: pb.SimpleBank.ConfirmTOTP:output_type -> pb.ConfirmTOTPResponse
+	54, // 54: pb.SimpleBank.VerifyMFA:output_type -> pb.VerifyMFAResponse
+	55, // 55: pb.SimpleBank.RequestPasswordReset:output_type -> pb.RequestPasswordResetResponse
+	56, // 56: pb.SimpleBank.ResetPassword:output_type -> pb.ResetPasswordResponse
+	57, // 57: pb.SimpleBank.UnlockUser:output_type -> pb.UnlockUserResponse
+	58, // 58: pb.SimpleBank.GetPublicKeys:output_type -> pb.GetPublicKeysResponse
+	59, // 59: pb.SimpleBank.CreateAPIKey:output_type -> pb.CreateAPIKeyResponse
+	60, // 60: pb.SimpleBank.ListAPIKeys:output_type -> pb.ListAPIKeysResponse
+	61, // 61: pb.SimpleBank.RevokeAPIKey:output_type -> pb.RevokeAPIKeyResponse
+	62, // 62: pb.SimpleBank.ResendVerifyEmail:output_type -> pb.ResendVerifyEmailResponse
+	63, // 63: pb.SimpleBank.CreateScheduledTransfer:output_type -> pb.CreateScheduledTransferResponse
+	64, // 64: pb.SimpleBank.ListScheduledTransfers:output_type -> pb.ListScheduledTransfersResponse
+	65, // 65: pb.SimpleBank.GetScheduledTransfer:output_type -> pb.GetScheduledTransferResponse
+	66, // 66: pb.SimpleBank.UpdateScheduledTransfer:output_type -> pb.UpdateScheduledTransferResponse
+	67, // 67: pb.SimpleBank.DeleteScheduledTransfer:output_type -> pb.DeleteScheduledTransferResponse
+	68, // 68: pb.SimpleBank.CreateHold:output_type -> pb.CreateHoldResponse
+	69, // 69: pb.SimpleBank.CaptureHold:output_type -> pb.CaptureHoldResponse
+	70, // 70: pb.SimpleBank.ReleaseHold:output_type -> pb.ReleaseHoldResponse
+	71, // 71: pb.SimpleBank.ReverseTransfer:output_type -> pb.ReverseTransferResponse
+	36, // [36:72] is the sub-list for method output_type
+	0,  // [0:36] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_service_simple_bank_proto_init() }
@@ -73,6 +239,38 @@ func file_service_simple_bank_proto_init() {
 	file_rpc_login_user_proto_init()
 	file_rpc_update_user_proto_init()
 	file_rpc_verify_email_proto_init()
+	file_rpc_create_account_proto_init()
+	file_rpc_get_account_proto_init()
+	file_rpc_list_accounts_proto_init()
+	file_rpc_create_transfer_proto_init()
+	file_rpc_quote_transfer_proto_init()
+	file_rpc_list_entries_proto_init()
+	file_rpc_list_transfers_proto_init()
+	file_rpc_renew_access_token_proto_init()
+	file_rpc_logout_proto_init()
+	file_rpc_logout_all_sessions_proto_init()
+	file_rpc_list_sessions_proto_init()
+	file_rpc_revoke_session_proto_init()
+	file_rpc_enroll_totp_proto_init()
+	file_rpc_confirm_totp_proto_init()
+	file_rpc_verify_mfa_proto_init()
+	file_rpc_request_password_reset_proto_init()
+	file_rpc_reset_password_proto_init()
+	file_rpc_unlock_user_proto_init()
+	file_rpc_get_public_keys_proto_init()
+	file_rpc_create_api_key_proto_init()
+	file_rpc_list_api_keys_proto_init()
+	file_rpc_revoke_api_key_proto_init()
+	file_rpc_resend_verify_email_proto_init()
+	file_rpc_create_scheduled_transfer_proto_init()
+	file_rpc_list_scheduled_transfers_proto_init()
+	file_rpc_get_scheduled_transfer_proto_init()
+	file_rpc_update_scheduled_transfer_proto_init()
+	file_rpc_delete_scheduled_transfer_proto_init()
+	file_rpc_create_hold_proto_init()
+	file_rpc_capture_hold_proto_init()
+	file_rpc_release_hold_proto_init()
+	file_rpc_reverse_transfer_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
@@ -0,0 +1,33 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sangketkit01/simple-bank/util"
+)
+
+// Limiter counts attempts per key within a fixed window
+type Limiter interface {
+	// Count returns the number of attempts recorded for key in the current window
+	Count(ctx context.Context, key string) (int64, error)
+
+	// Add records an attempt for key and returns the number of attempts in the current window
+	Add(ctx context.Context, key string) (int64, error)
+
+	// Reset forgets the attempts recorded for key
+	Reset(ctx context.Context, key string) error
+}
+
+// NewLimiter creates the limiter selected by the config:
+// attempts kept in process, or shared between instances through Redis with the in-process limiter as fallback
+func NewLimiter(config util.Config, window time.Duration) Limiter {
+	limiter := NewMemoryLimiter(window)
+	if config.RateLimitStore == "redis" {
+		client := redis.NewClient(&redis.Options{Addr: config.RedisAddress})
+		return NewRedisLimiter(client, limiter, window)
+	}
+
+	return limiter
+}
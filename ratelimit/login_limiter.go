@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/sangketkit01/simple-bank/util"
+)
+
+var ErrTooManyAttempts = errors.New("too many failed login attempts, try again later")
+
+// LoginLimiter tracks failed logins per username and per client ip.
+// Usernames are tracked whether or not they exist, so the limit tells nothing about registered users.
+type LoginLimiter struct {
+	limiter       Limiter
+	usernameLimit int64
+	ipLimit       int64
+}
+
+// NewLoginLimiter creates a LoginLimiter from the config.
+// A limit of zero or less disables that check.
+func NewLoginLimiter(config util.Config) *LoginLimiter {
+	return &LoginLimiter{
+		limiter:       NewLimiter(config, config.LoginAttemptWindow),
+		usernameLimit: int64(config.LoginUsernameAttemptLimit),
+		ipLimit:       int64(config.LoginIPAttemptLimit),
+	}
+}
+
+// Check returns ErrTooManyAttempts if the username or the client ip has used up its failed logins
+func (limiter *LoginLimiter) Check(ctx context.Context, username string, clientIP string) error {
+	exceeded, err := limiter.exceeded(ctx, usernameKey(username), limiter.usernameLimit)
+	if err != nil || exceeded {
+		return limitError(err, exceeded)
+	}
+
+	exceeded, err = limiter.exceeded(ctx, ipKey(clientIP), limiter.ipLimit)
+	return limitError(err, exceeded)
+}
+
+// Fail records a failed login of the username from the client ip
+func (limiter *LoginLimiter) Fail(ctx context.Context, username string, clientIP string) error {
+	if _, err := limiter.limiter.Add(ctx, usernameKey(username)); err != nil {
+		return err
+	}
+
+	_, err := limiter.limiter.Add(ctx, ipKey(clientIP))
+	return err
+}
+
+// Succeed forgets the failed logins of the username.
+// The client ip keeps its count, so one valid account cannot be used to keep guessing others.
+func (limiter *LoginLimiter) Succeed(ctx context.Context, username string) error {
+	return limiter.limiter.Reset(ctx, usernameKey(username))
+}
+
+func (limiter *LoginLimiter) exceeded(ctx context.Context, key string, limit int64) (bool, error) {
+	if limit <= 0 {
+		return false, nil
+	}
+
+	count, err := limiter.limiter.Count(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return count >= limit, nil
+}
+
+func limitError(err error, exceeded bool) error {
+	if err != nil {
+		return err
+	}
+	if exceeded {
+		return ErrTooManyAttempts
+	}
+	return nil
+}
+
+func usernameKey(username string) string {
+	return "login:username:" + username
+}
+
+func ipKey(clientIP string) string {
+	// direct gRPC clients are identified by their peer address, which includes the port
+	if host, _, err := net.SplitHostPort(clientIP); err == nil {
+		clientIP = host
+	}
+	return "login:ip:" + clientIP
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLoginLimiter() *LoginLimiter {
+	return NewLoginLimiter(util.Config{
+		LoginAttemptWindow:        time.Minute,
+		LoginUsernameAttemptLimit: 2,
+		LoginIPAttemptLimit:       3,
+	})
+}
+
+func TestLoginLimiterUsername(t *testing.T) {
+	limiter := newTestLoginLimiter()
+	ctx := context.Background()
+	username := util.RandomOwner()
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, limiter.Check(ctx, username, "10.0.0.1"))
+		require.NoError(t, limiter.Fail(ctx, username, "10.0.0.1"))
+	}
+
+	// the username is limited from every client ip
+	require.ErrorIs(t, limiter.Check(ctx, username, "10.0.0.2"), ErrTooManyAttempts)
+	require.NoError(t, limiter.Check(ctx, util.RandomOwner(), "10.0.0.2"))
+
+	require.NoError(t, limiter.Succeed(ctx, username))
+	require.NoError(t, limiter.Check(ctx, username, "10.0.0.2"))
+}
+
+func TestLoginLimiterClientIP(t *testing.T) {
+	limiter := newTestLoginLimiter()
+	ctx := context.Background()
+	clientIP := "10.0.0.1"
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, limiter.Fail(ctx, util.RandomOwner(), clientIP))
+	}
+
+	// guessing across many usernames is limited by the client ip
+	require.ErrorIs(t, limiter.Check(ctx, util.RandomOwner(), clientIP), ErrTooManyAttempts)
+	require.NoError(t, limiter.Check(ctx, util.RandomOwner(), "10.0.0.2"))
+
+	// the port of a peer address does not start a new count
+	require.ErrorIs(t, limiter.Check(ctx, util.RandomOwner(), "10.0.0.1:50000"), ErrTooManyAttempts)
+}
+
+func TestLoginLimiterDisabled(t *testing.T) {
+	limiter := NewLoginLimiter(util.Config{LoginAttemptWindow: time.Minute})
+	ctx := context.Background()
+	username := util.RandomOwner()
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, limiter.Fail(ctx, username, "10.0.0.1"))
+	}
+	require.NoError(t, limiter.Check(ctx, username, "10.0.0.1"))
+}
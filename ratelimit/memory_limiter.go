@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const maxMemoryEntries = 10000
+
+type memoryEntry struct {
+	count     int64
+	expiredAt time.Time
+}
+
+// MemoryLimiter keeps the attempts of every key in process
+type MemoryLimiter struct {
+	window time.Duration
+
+	mutex   sync.Mutex
+	entries map[string]memoryEntry
+}
+
+// NewMemoryLimiter creates a new MemoryLimiter
+func NewMemoryLimiter(window time.Duration) *MemoryLimiter {
+	return &MemoryLimiter{
+		window:  window,
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+// Count returns the number of attempts recorded for key in the current window
+func (limiter *MemoryLimiter) Count(ctx context.Context, key string) (int64, error) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	entry, ok := limiter.entries[key]
+	if !ok || !time.Now().Before(entry.expiredAt) {
+		return 0, nil
+	}
+	return entry.count, nil
+}
+
+// Add records an attempt for key and returns the number of attempts in the current window
+func (limiter *MemoryLimiter) Add(ctx context.Context, key string) (int64, error) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	now := time.Now()
+	entry, ok := limiter.entries[key]
+	if !ok || !now.Before(entry.expiredAt) {
+		// drop expired windows once the map grows, so it never keeps every key ever seen
+		if len(limiter.entries) >= maxMemoryEntries {
+			for k, e := range limiter.entries {
+				if !now.Before(e.expiredAt) {
+					delete(limiter.entries, k)
+				}
+			}
+		}
+		entry = memoryEntry{expiredAt: now.Add(limiter.window)}
+	}
+
+	entry.count++
+	limiter.entries[key] = entry
+	return entry.count, nil
+}
+
+// Reset forgets the attempts recorded for key
+func (limiter *MemoryLimiter) Reset(ctx context.Context, key string) error {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	delete(limiter.entries, key)
+	return nil
+}
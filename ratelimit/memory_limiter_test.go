@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	limiter := NewMemoryLimiter(time.Minute)
+
+	count, err := limiter.Count(context.Background(), "key")
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	for i := int64(1); i <= 3; i++ {
+		count, err = limiter.Add(context.Background(), "key")
+		require.NoError(t, err)
+		require.Equal(t, i, count)
+	}
+
+	count, err = limiter.Count(context.Background(), "other")
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	err = limiter.Reset(context.Background(), "key")
+	require.NoError(t, err)
+	count, err = limiter.Count(context.Background(), "key")
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func TestMemoryLimiterWindowExpired(t *testing.T) {
+	limiter := NewMemoryLimiter(-time.Second)
+
+	_, err := limiter.Add(context.Background(), "key")
+	require.NoError(t, err)
+
+	// a new window starts once the previous one is over
+	count, err := limiter.Count(context.Background(), "key")
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	count, err = limiter.Add(context.Background(), "key")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
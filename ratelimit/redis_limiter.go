@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "rate_limit:"
+
+// RedisLimiter shares attempts between server instances through Redis.
+// When Redis is unavailable the fallback limiter is used, so limits keep working per instance.
+type RedisLimiter struct {
+	client   *redis.Client
+	fallback Limiter
+	window   time.Duration
+}
+
+// NewRedisLimiter creates a new RedisLimiter
+func NewRedisLimiter(client *redis.Client, fallback Limiter, window time.Duration) *RedisLimiter {
+	return &RedisLimiter{
+		client:   client,
+		fallback: fallback,
+		window:   window,
+	}
+}
+
+// Count returns the number of attempts recorded for key in the current window
+func (limiter *RedisLimiter) Count(ctx context.Context, key string) (int64, error) {
+	count, err := limiter.client.Get(ctx, keyPrefix+key).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return limiter.fallback.Count(ctx, key)
+	}
+
+	// attempts recorded while Redis was unavailable are only known to the fallback
+	fallbackCount, err := limiter.fallback.Count(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	return max(count, fallbackCount), nil
+}
+
+// Add records an attempt for key and returns the number of attempts in the current window
+func (limiter *RedisLimiter) Add(ctx context.Context, key string) (int64, error) {
+	pipe := limiter.client.TxPipeline()
+	incr := pipe.Incr(ctx, keyPrefix+key)
+	pipe.ExpireNX(ctx, keyPrefix+key, limiter.window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return limiter.fallback.Add(ctx, key)
+	}
+
+	return incr.Val(), nil
+}
+
+// Reset forgets the attempts recorded for key
+func (limiter *RedisLimiter) Reset(ctx context.Context, key string) error {
+	// a failure here only keeps the attempts until the window ends
+	limiter.client.Del(ctx, keyPrefix+key)
+	return limiter.fallback.Reset(ctx, key)
+}
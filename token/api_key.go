@@ -0,0 +1,69 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sangketkit01/simple-bank/util"
+)
+
+var ErrInvalidAPIKey = errors.New("invalid api key")
+var ErrExpiredAPIKey = errors.New("api key has expired")
+
+// APIKeyState is what the key store knows about an API key
+type APIKeyState struct {
+	ID       int64
+	Username string
+	// Role is the current role of the owner, a key never has more rights than its owner
+	Role      string
+	Scopes    []string
+	Revoked   bool
+	ExpiredAt time.Time
+}
+
+// APIKeyLookup returns the state of the API key with the hashed key in the key store,
+// an unknown key is reported as revoked
+type APIKeyLookup func(ctx context.Context, hashedKey string) (APIKeyState, error)
+
+// APIKeyVerifier authenticates requests made with an API key instead of an access token
+type APIKeyVerifier struct {
+	lookup APIKeyLookup
+}
+
+// NewAPIKeyVerifier creates a new APIKeyVerifier checking keys against the key store
+func NewAPIKeyVerifier(lookup APIKeyLookup) *APIKeyVerifier {
+	return &APIKeyVerifier{lookup: lookup}
+}
+
+// VerifyAPIKey checks the API key and returns a payload acting as its owner, limited to the scopes of the key.
+// It returns ErrInvalidAPIKey or ErrExpiredAPIKey for a key that must be rejected,
+// any other error means the key store could not be checked.
+func (verifier *APIKeyVerifier) VerifyAPIKey(ctx context.Context, key string) (*Payload, error) {
+	if !util.IsAPIKey(key) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	state, err := verifier.lookup(ctx, util.HashAPIKey(key))
+	if err != nil {
+		return nil, fmt.Errorf("cannot check api key: %w", err)
+	}
+
+	if state.Revoked {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if time.Now().After(state.ExpiredAt) {
+		return nil, ErrExpiredAPIKey
+	}
+
+	payload := &Payload{
+		Username:  state.Username,
+		Role:      state.Role,
+		ExpiredAt: state.ExpiredAt,
+		APIKeyID:  state.ID,
+		Scopes:    state.Scopes,
+	}
+	return payload, nil
+}
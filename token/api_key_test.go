@@ -0,0 +1,67 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyVerifier(t *testing.T) {
+	key, err := util.GenerateAPIKey()
+	require.NoError(t, err)
+
+	state := APIKeyState{
+		ID:        util.RandomInt(1, 1000),
+		Username:  util.RandomOwner(),
+		Role:      util.DepositorRole,
+		Scopes:    []string{util.AccountsReadScope},
+		ExpiredAt: time.Now().Add(time.Hour),
+	}
+
+	var lookedUp string
+	verifier := NewAPIKeyVerifier(func(ctx context.Context, hashedKey string) (APIKeyState, error) {
+		lookedUp = hashedKey
+		return state, nil
+	})
+
+	payload, err := verifier.VerifyAPIKey(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, util.HashAPIKey(key), lookedUp)
+	require.Equal(t, state.ID, payload.APIKeyID)
+	require.Equal(t, state.Username, payload.Username)
+	require.Equal(t, state.Role, payload.Role)
+	require.True(t, payload.AllowsScope(util.AccountsReadScope))
+	require.False(t, payload.AllowsScope(util.TransfersWriteScope))
+	require.False(t, payload.AllowsScope(""))
+
+	_, err = verifier.VerifyAPIKey(context.Background(), "not-an-api-key")
+	require.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	state.ExpiredAt = time.Now().Add(-time.Minute)
+	_, err = verifier.VerifyAPIKey(context.Background(), key)
+	require.ErrorIs(t, err, ErrExpiredAPIKey)
+
+	state.Revoked = true
+	_, err = verifier.VerifyAPIKey(context.Background(), key)
+	require.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	failing := NewAPIKeyVerifier(func(ctx context.Context, hashedKey string) (APIKeyState, error) {
+		return APIKeyState{}, errors.New("connection refused")
+	})
+	_, err = failing.VerifyAPIKey(context.Background(), key)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrInvalidAPIKey)
+}
+
+func TestTokenPayloadAllowsEveryScope(t *testing.T) {
+	payload, err := NewPayload(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	require.True(t, payload.AllowsScope(util.TransfersWriteScope))
+	require.True(t, payload.AllowsScope(""))
+}
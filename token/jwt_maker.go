@@ -5,62 +5,127 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/sangketkit01/simple-bank/util"
 )
 
 const minSecretKeySize = 32
 
+// jwtSigningMethod is the only algorithm the JWTMaker signs and accepts,
+// so a token can never pick its own verification method through the alg header
+var jwtSigningMethod = jwt.SigningMethodHS256
+
 // JWTMaker is a JSON Web Token maker
 type JWTMaker struct {
-	secretKey string
+	secretKey []byte
+	issuer    string
+	audience  string
+	leeway    time.Duration
+}
+
+// jwtClaims are the claims of a JWT: the registered ones plus the user and session of the payload
+type jwtClaims struct {
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	SessionID uuid.UUID `json:"session_id"`
+	TokenType TokenType `json:"token_type"`
+	jwt.RegisteredClaims
 }
 
-// NewJWTMaker creates a new JWTMaker
-func NewJWTMaker(secretKey string) (Maker, error) {
-	if len(secretKey) < minSecretKeySize {
-		return nil, fmt.Errorf("invalid key size: %d, must be at least %d characters",len(secretKey), minSecretKeySize)
+// NewJWTMaker creates a new JWTMaker from the token settings of the config.
+// Tokens carry the configured issuer and audience and are rejected when they don't match;
+// the leeway tolerates clock skew between the servers when checking exp, nbf and iat.
+func NewJWTMaker(config util.Config) (Maker, error) {
+	if len(config.TokenSymmetricKey) < minSecretKeySize {
+		return nil, fmt.Errorf("invalid key size: %d, must be at least %d characters", len(config.TokenSymmetricKey), minSecretKeySize)
+	}
+
+	if config.TokenLeeway < 0 {
+		return nil, fmt.Errorf("invalid token leeway: %s, must not be negative", config.TokenLeeway)
 	}
 
-	return &JWTMaker{secretKey: secretKey}, nil
+	maker := &JWTMaker{
+		secretKey: []byte(config.TokenSymmetricKey),
+		issuer:    config.TokenIssuer,
+		audience:  config.TokenAudience,
+		leeway:    config.TokenLeeway,
+	}
+	return maker, nil
 }
 
-// CreateToken creates a new token for a specific username and duration
-func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error){
-	payload, err := NewPayload(username, duration)
-	if err != nil{
-		return "", payload,err
+// CreateToken creates a new token of the given type for a specific username, role, session and duration
+func (maker *JWTMaker) CreateToken(username string, role string, sessionID uuid.UUID, duration time.Duration, tokenType TokenType) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, sessionID, duration, tokenType)
+	if err != nil {
+		return "", payload, err
+	}
+
+	claims := jwtClaims{
+		Username:  payload.Username,
+		Role:      payload.Role,
+		SessionID: payload.SessionID,
+		TokenType: payload.Type,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        payload.ID.String(),
+			Issuer:    maker.issuer,
+			Subject:   payload.Username,
+			IssuedAt:  jwt.NewNumericDate(payload.IssuedAt),
+			NotBefore: jwt.NewNumericDate(payload.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(payload.ExpiredAt),
+		},
+	}
+	if maker.audience != "" {
+		claims.Audience = jwt.ClaimStrings{maker.audience}
 	}
 
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
-	token, err := jwtToken.SignedString([]byte(maker.secretKey))
+	jwtToken := jwt.NewWithClaims(jwtSigningMethod, claims)
+	token, err := jwtToken.SignedString(maker.secretKey)
 	return token, payload, err
 }
 
-// VerifyToken checks if the token is valid or not
-func (maker *JWTMaker) VerifyToken(token string) (*Payload, error){
-	keyFunc := func(token *jwt.Token) (interface{}, error){
-		_, ok := token.Method.(*jwt.SigningMethodHMAC)
-		if !ok{
-			return nil, ErrInvalidToken
-		}
+// VerifyToken checks if the token is a valid token of the given type
+func (maker *JWTMaker) VerifyToken(token string, tokenType TokenType) (*Payload, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		return maker.secretKey, nil
+	}
 
-		return []byte(maker.secretKey), nil
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwtSigningMethod.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(maker.leeway),
+	}
+	if maker.issuer != "" {
+		options = append(options, jwt.WithIssuer(maker.issuer))
+	}
+	if maker.audience != "" {
+		options = append(options, jwt.WithAudience(maker.audience))
 	}
-	jwtToken, err := jwt.ParseWithClaims(token, &Payload{} , keyFunc)
 
+	claims := &jwtClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, keyFunc, options...)
 	if err != nil {
-		verr, ok := err.(*jwt.ValidationError)
-		if ok && errors.Is(verr.Inner, ErrExpiredToken){
+		if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, ErrExpiredToken
 		}
 
 		return nil, ErrInvalidToken
 	}
 
-	payload, ok := jwtToken.Claims.(*Payload)
-	if !ok{
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil || claims.IssuedAt == nil || claims.TokenType != tokenType {
 		return nil, ErrInvalidToken
 	}
 
+	payload := &Payload{
+		ID:        tokenID,
+		Type:      claims.TokenType,
+		Username:  claims.Username,
+		Role:      claims.Role,
+		SessionID: claims.SessionID,
+		IssuedAt:  claims.IssuedAt.Time,
+		ExpiredAt: claims.ExpiresAt.Time,
+	}
 	return payload, nil
-}
\ No newline at end of file
+}
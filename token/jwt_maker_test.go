@@ -4,62 +4,235 @@ import (
 	"testing"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/sangketkit01/simple-bank/util"
 	"github.com/stretchr/testify/require"
 )
 
+func newJWTTestConfig() util.Config {
+	return util.Config{
+		TokenType:         "jwt",
+		TokenSymmetricKey: util.RandomString(32),
+		TokenIssuer:       "simple-bank",
+		TokenAudience:     "simple-bank",
+	}
+}
+
+func signJWTClaims(t *testing.T, config util.Config, method jwt.SigningMethod, claims jwtClaims) string {
+	token, err := jwt.NewWithClaims(method, claims).SignedString([]byte(config.TokenSymmetricKey))
+	require.NoError(t, err)
+	return token
+}
+
+func newJWTClaims(config util.Config, issuedAt time.Time, duration time.Duration) jwtClaims {
+	return jwtClaims{
+		Username:  util.RandomOwner(),
+		Role:      util.DepositorRole,
+		SessionID: uuid.New(),
+		TokenType: TokenTypeAccessToken,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    config.TokenIssuer,
+			Audience:  jwt.ClaimStrings{config.TokenAudience},
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			NotBefore: jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(duration)),
+		},
+	}
+}
+
 func TestJWTMaker(t *testing.T) {
-	maker, err := NewJWTMaker(util.RandomString(32))
+	maker, err := NewJWTMaker(newJWTTestConfig())
 	require.NoError(t, err)
 
 	username := util.RandomOwner()
+	role := util.DepositorRole
+	sessionID := uuid.New()
 	duration := time.Minute
 
 	issuedAt := time.Now()
 	expiredAt := time.Now().Add(duration)
 
-	token, payload,err := maker.CreateToken(username, duration)
+	token, payload, err := maker.CreateToken(username, role, sessionID, duration, TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 
-	payload, err = maker.VerifyToken(token)
+	payload, err = maker.VerifyToken(token, TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
 	require.NotZero(t, payload.ID)
 	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
+	require.Equal(t, sessionID, payload.SessionID)
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
 	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
 }
 
+func TestNewJWTMakerInvalidConfig(t *testing.T) {
+	config := newJWTTestConfig()
+	config.TokenSymmetricKey = util.RandomString(31)
+	_, err := NewJWTMaker(config)
+	require.Error(t, err)
+
+	config = newJWTTestConfig()
+	config.TokenLeeway = -time.Second
+	_, err = NewJWTMaker(config)
+	require.Error(t, err)
+}
+
 func TestExpiredJWTToken(t *testing.T) {
-	maker, err := NewJWTMaker(util.RandomString(32))
+	maker, err := NewJWTMaker(newJWTTestConfig())
 	require.NoError(t, err)
 
-	token, payload,err := maker.CreateToken(util.RandomOwner(), -time.Minute)
+	token, payload, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), -time.Minute, TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 
-	payload, err = maker.VerifyToken(token)
+	payload, err = maker.VerifyToken(token, TokenTypeAccessToken)
 	require.Error(t, err)
 	require.EqualError(t, err, ErrExpiredToken.Error())
 	require.Nil(t, payload)
 }
 
-func TestInvalidTokenAlgNone(t *testing.T) {
-	payload, err := NewPayload(util.RandomOwner(), time.Minute)
+func TestJWTTokenLeeway(t *testing.T) {
+	config := newJWTTestConfig()
+	config.TokenLeeway = time.Minute
+	maker, err := NewJWTMaker(config)
 	require.NoError(t, err)
 
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
+	// expired within the leeway
+	token := signJWTClaims(t, config, jwt.SigningMethodHS256, newJWTClaims(config, time.Now().Add(-2*time.Minute), time.Minute+30*time.Second))
+	_, err = maker.VerifyToken(token, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	// issued by a server whose clock is slightly ahead
+	token = signJWTClaims(t, config, jwt.SigningMethodHS256, newJWTClaims(config, time.Now().Add(30*time.Second), time.Minute))
+	_, err = maker.VerifyToken(token, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	// expired beyond the leeway
+	token = signJWTClaims(t, config, jwt.SigningMethodHS256, newJWTClaims(config, time.Now().Add(-5*time.Minute), time.Minute))
+	_, err = maker.VerifyToken(token, TokenTypeAccessToken)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+}
+
+func TestJWTTokenNotYetValid(t *testing.T) {
+	config := newJWTTestConfig()
+	maker, err := NewJWTMaker(config)
+	require.NoError(t, err)
+
+	claims := newJWTClaims(config, time.Now(), time.Hour)
+	claims.NotBefore = jwt.NewNumericDate(time.Now().Add(10 * time.Minute))
+
+	payload, err := maker.VerifyToken(signJWTClaims(t, config, jwt.SigningMethodHS256, claims), TokenTypeAccessToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestJWTTokenWrongIssuerOrAudience(t *testing.T) {
+	config := newJWTTestConfig()
+	maker, err := NewJWTMaker(config)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name   string
+		modify func(claims *jwtClaims)
+	}{
+		{
+			name: "WrongIssuer",
+			modify: func(claims *jwtClaims) {
+				claims.Issuer = "other-issuer"
+			},
+		},
+		{
+			name: "MissingIssuer",
+			modify: func(claims *jwtClaims) {
+				claims.Issuer = ""
+			},
+		},
+		{
+			name: "WrongAudience",
+			modify: func(claims *jwtClaims) {
+				claims.Audience = jwt.ClaimStrings{"other-audience"}
+			},
+		},
+		{
+			name: "MissingAudience",
+			modify: func(claims *jwtClaims) {
+				claims.Audience = nil
+			},
+		},
+		{
+			name: "MissingExpiration",
+			modify: func(claims *jwtClaims) {
+				claims.ExpiresAt = nil
+			},
+		},
+		{
+			name: "InvalidTokenID",
+			modify: func(claims *jwtClaims) {
+				claims.ID = "not-a-uuid"
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			claims := newJWTClaims(config, time.Now(), time.Minute)
+			tc.modify(&claims)
+
+			payload, err := maker.VerifyToken(signJWTClaims(t, config, jwt.SigningMethodHS256, claims), TokenTypeAccessToken)
+			require.EqualError(t, err, ErrInvalidToken.Error())
+			require.Nil(t, payload)
+		})
+	}
+}
+
+func TestInvalidTokenAlgNone(t *testing.T) {
+	config := newJWTTestConfig()
+	claims := newJWTClaims(config, time.Now(), time.Minute)
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
 	token, err := jwtToken.SignedString(jwt.UnsafeAllowNoneSignatureType)
 	require.NoError(t, err)
 
-	maker, err := NewJWTMaker(util.RandomString(32))
+	maker, err := NewJWTMaker(config)
 	require.NoError(t, err)
 
-	payload, err = maker.VerifyToken(token)
+	payload, err := maker.VerifyToken(token, TokenTypeAccessToken)
 	require.Error(t, err)
 	require.EqualError(t, err, ErrInvalidToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestInvalidTokenOtherHMACAlgorithm(t *testing.T) {
+	config := newJWTTestConfig()
+	maker, err := NewJWTMaker(config)
+	require.NoError(t, err)
+
+	// same key, but HS512 is not the pinned algorithm
+	token := signJWTClaims(t, config, jwt.SigningMethodHS512, newJWTClaims(config, time.Now(), time.Minute))
+	payload, err := maker.VerifyToken(token, TokenTypeAccessToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestNewMakerTokenType(t *testing.T) {
+	config := newJWTTestConfig()
+	maker, err := NewMaker(config)
+	require.NoError(t, err)
+	require.IsType(t, &JWTMaker{}, maker)
+
+	config.TokenType = "paseto"
+	maker, err = NewMaker(config)
+	require.NoError(t, err)
+	require.IsType(t, &PasetoMaker{}, maker)
+
+	config.TokenType = "unknown"
+	_, err = NewMaker(config)
+	require.Error(t, err)
+}
@@ -0,0 +1,117 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PublicKey is a token verification key and the id tokens refer to it by
+type PublicKey struct {
+	KeyID string
+	Key   ed25519.PublicKey
+}
+
+// KeySet holds the Ed25519 keys of the asymmetric token maker by key id.
+// Keys with a private part can sign tokens, keys with only a public part are kept
+// to verify the tokens they signed until those tokens expire.
+type KeySet struct {
+	publicKeys  map[string]ed25519.PublicKey
+	privateKeys map[string]ed25519.PrivateKey
+}
+
+// NewKeySet creates an empty KeySet
+func NewKeySet() *KeySet {
+	return &KeySet{
+		publicKeys:  make(map[string]ed25519.PublicKey),
+		privateKeys: make(map[string]ed25519.PrivateKey),
+	}
+}
+
+// LoadKeySet reads every <kid>.pem file of dir, each holding a PKCS #8 private key
+// or a PKIX public key in PEM form, as generated by `openssl genpkey -algorithm ed25519`
+func LoadKeySet(dir string) (*KeySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	keySet := NewKeySet()
+	for _, file := range files {
+		keyID := strings.TrimSuffix(filepath.Base(file), ".pem")
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read key %s: %w", keyID, err)
+		}
+
+		err = keySet.addPEM(keyID, data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load key %s: %w", keyID, err)
+		}
+	}
+
+	return keySet, nil
+}
+
+// AddPrivateKey adds a key that can sign and verify tokens
+func (keySet *KeySet) AddPrivateKey(keyID string, key ed25519.PrivateKey) {
+	keySet.privateKeys[keyID] = key
+	keySet.publicKeys[keyID] = key.Public().(ed25519.PublicKey)
+}
+
+// AddPublicKey adds a key that can only verify tokens
+func (keySet *KeySet) AddPublicKey(keyID string, key ed25519.PublicKey) {
+	keySet.publicKeys[keyID] = key
+}
+
+// PublicKeys returns the verification keys ordered by key id
+func (keySet *KeySet) PublicKeys() []PublicKey {
+	keys := make([]PublicKey, 0, len(keySet.publicKeys))
+	for keyID, key := range keySet.publicKeys {
+		keys = append(keys, PublicKey{KeyID: keyID, Key: key})
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].KeyID < keys[j].KeyID
+	})
+	return keys
+}
+
+func (keySet *KeySet) addPEM(keyID string, data []byte) error {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return fmt.Errorf("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return err
+		}
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return fmt.Errorf("not an Ed25519 private key")
+		}
+		keySet.AddPrivateKey(keyID, privateKey)
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return err
+		}
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("not an Ed25519 public key")
+		}
+		keySet.AddPublicKey(keyID, publicKey)
+	default:
+		return fmt.Errorf("unsupported PEM block type %s", block.Type)
+	}
+
+	return nil
+}
@@ -1,12 +1,60 @@
 package token
 
-import "time"
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sangketkit01/simple-bank/util"
+)
 
 // Maker is an interface for managing tokens
 type Maker interface{
-	// CreateToken creates a new token for a specific username and duration
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
+	// CreateToken creates a new token of the given type for a specific username, role, session and duration
+	CreateToken(username string, role string, sessionID uuid.UUID, duration time.Duration, tokenType TokenType) (string, *Payload, error)
+
+	// VerifyToken checks if the token is a valid token of the given type
+	VerifyToken(token string, tokenType TokenType) (*Payload, error)
+}
+// NewMaker creates the token maker selected by the config:
+// the JWT maker for TOKEN_TYPE=jwt, otherwise the asymmetric PASETO v4.public maker when signing keys are configured
+// and the symmetric PASETO maker when they aren't
+func NewMaker(config util.Config) (Maker, error) {
+	switch config.TokenType {
+	case "", "paseto":
+	case "jwt":
+		return NewJWTMaker(config)
+	default:
+		return nil, fmt.Errorf("unsupported token type: %s", config.TokenType)
+	}
+
+	if config.TokenKeyDir == "" && config.TokenPrivateKey == "" {
+		return NewPasetoMaker(config.TokenSymmetricKey)
+	}
+
+	keySet := NewKeySet()
+	if config.TokenKeyDir != "" {
+		var err error
+		keySet, err = LoadKeySet(config.TokenKeyDir)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load token keys: %w", err)
+		}
+	}
+
+	if config.TokenPrivateKey != "" {
+		seed, err := base64.StdEncoding.DecodeString(config.TokenPrivateKey)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("token private key must be a base64 encoded %d bytes Ed25519 seed", ed25519.SeedSize)
+		}
+		keySet.AddPrivateKey(config.TokenKeyID, ed25519.NewKeyFromSeed(seed))
+	}
+
+	return NewPasetoPublicMaker(keySet, config.TokenKeyID)
+}
 
-	// VerifyToken checks if the token is valid or not
-	VerifyToken(token string) (*Payload, error)
-}
\ No newline at end of file
+// PublicKeyProvider is implemented by makers whose tokens can be verified with public keys
+type PublicKeyProvider interface {
+	PublicKeys() []PublicKey
+}
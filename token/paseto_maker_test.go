@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sangketkit01/simple-bank/util"
 	"github.com/stretchr/testify/require"
 )
@@ -13,21 +14,25 @@ func TestPasetoMaker(t *testing.T) {
 	require.NoError(t, err)
 
 	username := util.RandomOwner()
+	role := util.DepositorRole
+	sessionID := uuid.New()
 	duration := time.Minute
 
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	token,payload , err := maker.CreateToken(username, duration)
+	token,payload , err := maker.CreateToken(username, role, sessionID, duration, TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 
-	payload, err = maker.VerifyToken(token)
+	payload, err = maker.VerifyToken(token, TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
 	require.NotZero(t, payload.ID)
 	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
+	require.Equal(t, sessionID, payload.SessionID)
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
 	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
 }
@@ -36,12 +41,40 @@ func TestExpiredPasetoToken(t *testing.T) {
 	maker, err := NewPasetoMaker(util.RandomString(32))
 	require.NoError(t, err)
 
-	token, payload, err := maker.CreateToken(util.RandomOwner(), -time.Minute)
+	token, payload, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), -time.Minute, TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 
-	payload, err = maker.VerifyToken(token)
+	payload, err = maker.VerifyToken(token, TokenTypeAccessToken)
 	require.Error(t, err)
 	require.EqualError(t, err, ErrExpiredToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestWrongTokenType(t *testing.T) {
+	pasetoMaker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+	jwtMaker, err := NewJWTMaker(newJWTTestConfig())
+	require.NoError(t, err)
+
+	makers := map[string]Maker{
+		"Paseto":       pasetoMaker,
+		"PasetoPublic": newTestPublicMaker(t, "key-1", newTestKey(t)),
+		"JWT":          jwtMaker,
+	}
+
+	for name, maker := range makers {
+		t.Run(name, func(t *testing.T) {
+			refreshToken, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute, TokenTypeRefreshToken)
+			require.NoError(t, err)
+
+			payload, err := maker.VerifyToken(refreshToken, TokenTypeAccessToken)
+			require.EqualError(t, err, ErrInvalidToken.Error())
+			require.Nil(t, payload)
+
+			payload, err = maker.VerifyToken(refreshToken, TokenTypeRefreshToken)
+			require.NoError(t, err)
+			require.Equal(t, TokenTypeRefreshToken, payload.Type)
+		})
+	}
+}
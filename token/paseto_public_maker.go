@@ -0,0 +1,231 @@
+package token
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const pasetoV4PublicHeader = "v4.public."
+
+var ErrCannotSign = errors.New("token maker has no signing key")
+
+// pasetoFooter names the key a token was signed with
+type pasetoFooter struct {
+	KeyID string `json:"kid"`
+}
+
+// pasetoClaims are the claims of a v4.public token: the registered claims of the PASETO spec
+// plus the user and session of the payload
+type pasetoClaims struct {
+	TokenID    uuid.UUID `json:"jti"`
+	Subject    string    `json:"sub"`
+	IssuedAt   time.Time `json:"iat"`
+	NotBefore  time.Time `json:"nbf"`
+	Expiration time.Time `json:"exp"`
+	TokenType  TokenType `json:"token_type"`
+	Role       string    `json:"role"`
+	SessionID  uuid.UUID `json:"session_id"`
+}
+
+// PasetoPublicMaker signs PASETO v4.public tokens with an Ed25519 key
+// and verifies them against every key of its key set, so services that only
+// verify tokens need the public keys alone and keys can be rotated without signing everyone out
+type PasetoPublicMaker struct {
+	keySet       *KeySet
+	currentKeyID string
+	privateKey   ed25519.PrivateKey
+}
+
+// NewPasetoPublicMaker creates a PasetoPublicMaker signing with the private key currentKeyID of the key set.
+// Without a currentKeyID the maker only verifies tokens, which is all a service holding the public keys alone can do.
+func NewPasetoPublicMaker(keySet *KeySet, currentKeyID string) (*PasetoPublicMaker, error) {
+	maker := &PasetoPublicMaker{
+		keySet:       keySet,
+		currentKeyID: currentKeyID,
+	}
+
+	if currentKeyID != "" {
+		privateKey, ok := keySet.privateKeys[currentKeyID]
+		if !ok {
+			return nil, fmt.Errorf("no private key with id %q", currentKeyID)
+		}
+		maker.privateKey = privateKey
+	}
+
+	return maker, nil
+}
+
+// CreateToken creates a new token of the given type for a specific username, role, session and duration
+func (maker *PasetoPublicMaker) CreateToken(username string, role string, sessionID uuid.UUID, duration time.Duration, tokenType TokenType) (string, *Payload, error) {
+	if maker.privateKey == nil {
+		return "", nil, ErrCannotSign
+	}
+
+	payload, err := NewPayload(username, role, sessionID, duration, tokenType)
+	if err != nil {
+		return "", payload, err
+	}
+
+	message, err := json.Marshal(pasetoClaims{
+		TokenID:    payload.ID,
+		Subject:    payload.Username,
+		IssuedAt:   payload.IssuedAt,
+		NotBefore:  payload.IssuedAt,
+		Expiration: payload.ExpiredAt,
+		TokenType:  payload.Type,
+		Role:       payload.Role,
+		SessionID:  payload.SessionID,
+	})
+	if err != nil {
+		return "", payload, err
+	}
+
+	footer, err := json.Marshal(pasetoFooter{KeyID: maker.currentKeyID})
+	if err != nil {
+		return "", payload, err
+	}
+
+	token := signPasetoV4Public(maker.privateKey, message, footer, nil)
+	return token, payload, nil
+}
+
+// VerifyToken checks if the token is a valid token of the given type
+func (maker *PasetoPublicMaker) VerifyToken(token string, tokenType TokenType) (*Payload, error) {
+	// the footer is read before the signature is checked only to pick the key
+	_, footer, err := splitPasetoV4Public(token)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var keyFooter pasetoFooter
+	if err := json.Unmarshal(footer, &keyFooter); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	publicKey, ok := maker.keySet.publicKeys[keyFooter.KeyID]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	message, err := verifyPasetoV4Public(publicKey, token, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims pasetoClaims
+	if err := json.Unmarshal(message, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	// every token of the maker carries the time claims, one missing them was not made here
+	if claims.IssuedAt.IsZero() || claims.Expiration.IsZero() || time.Now().Before(claims.NotBefore) {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{
+		ID:        claims.TokenID,
+		Type:      claims.TokenType,
+		Username:  claims.Subject,
+		Role:      claims.Role,
+		SessionID: claims.SessionID,
+		IssuedAt:  claims.IssuedAt,
+		ExpiredAt: claims.Expiration,
+	}
+	err = payload.Valid(tokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// PublicKeys returns the keys the tokens of the maker can be verified with
+func (maker *PasetoPublicMaker) PublicKeys() []PublicKey {
+	return maker.keySet.PublicKeys()
+}
+
+// signPasetoV4Public builds a v4.public token as specified by https://github.com/paseto-standard/paseto-spec.
+// The implicit assertion is signed but not part of the token, the verifier has to supply the same one.
+func signPasetoV4Public(privateKey ed25519.PrivateKey, message []byte, footer []byte, implicit []byte) string {
+	signature := ed25519.Sign(privateKey, preAuthEncode([]byte(pasetoV4PublicHeader), message, footer, implicit))
+
+	body := append(append([]byte{}, message...), signature...)
+	token := pasetoV4PublicHeader + base64.RawURLEncoding.EncodeToString(body)
+	if len(footer) > 0 {
+		token += "." + base64.RawURLEncoding.EncodeToString(footer)
+	}
+	return token
+}
+
+// verifyPasetoV4Public checks the signature of a v4.public token under the implicit assertion and returns its message
+func verifyPasetoV4Public(publicKey ed25519.PublicKey, token string, implicit []byte) ([]byte, error) {
+	body, footer, err := splitPasetoV4Public(token)
+	if err != nil {
+		return nil, err
+	}
+
+	message := body[:len(body)-ed25519.SignatureSize]
+	signature := body[len(body)-ed25519.SignatureSize:]
+	if !ed25519.Verify(publicKey, preAuthEncode([]byte(pasetoV4PublicHeader), message, footer, implicit), signature) {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+
+	return message, nil
+}
+
+// splitPasetoV4Public decodes the signed body and the footer of a v4.public token
+func splitPasetoV4Public(token string) (body []byte, footer []byte, err error) {
+	if !strings.HasPrefix(token, pasetoV4PublicHeader) {
+		return nil, nil, fmt.Errorf("not a v4.public token")
+	}
+
+	parts := strings.Split(strings.TrimPrefix(token, pasetoV4PublicHeader), ".")
+	if len(parts) > 2 {
+		return nil, nil, fmt.Errorf("malformed token")
+	}
+
+	body, err = base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(body) < ed25519.SignatureSize {
+		return nil, nil, fmt.Errorf("token is too short")
+	}
+
+	if len(parts) == 2 {
+		footer, err = base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return body, footer, nil
+}
+
+// preAuthEncode is the PAE function of the PASETO spec, it encodes the pieces so they cannot be confused with each other
+func preAuthEncode(pieces ...[]byte) []byte {
+	var buffer bytes.Buffer
+
+	writeLength := func(n int) {
+		var length [8]byte
+		binary.LittleEndian.PutUint64(length[:], uint64(n)&^(1<<63))
+		buffer.Write(length[:])
+	}
+
+	writeLength(len(pieces))
+	for _, piece := range pieces {
+		writeLength(len(piece))
+		buffer.Write(piece)
+	}
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,300 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKey(t *testing.T) ed25519.PrivateKey {
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	return privateKey
+}
+
+func newTestPublicMaker(t *testing.T, keyID string, privateKey ed25519.PrivateKey) *PasetoPublicMaker {
+	keySet := NewKeySet()
+	keySet.AddPrivateKey(keyID, privateKey)
+
+	maker, err := NewPasetoPublicMaker(keySet, keyID)
+	require.NoError(t, err)
+	return maker
+}
+
+func TestPasetoPublicMaker(t *testing.T) {
+	maker := newTestPublicMaker(t, "key-1", newTestKey(t))
+
+	username := util.RandomOwner()
+	role := util.DepositorRole
+	sessionID := uuid.New()
+	duration := time.Minute
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, _, err := maker.CreateToken(username, role, sessionID, duration, TokenTypeAccessToken)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, pasetoV4PublicHeader))
+
+	payload, err := maker.VerifyToken(token, TokenTypeAccessToken)
+	require.NoError(t, err)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
+	require.Equal(t, sessionID, payload.SessionID)
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+}
+
+func TestExpiredPasetoPublicToken(t *testing.T) {
+	maker := newTestPublicMaker(t, "key-1", newTestKey(t))
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), -time.Minute, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token, TokenTypeAccessToken)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoPublicTokenTampered(t *testing.T) {
+	maker := newTestPublicMaker(t, "key-1", newTestKey(t))
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	// a token signed by a different key under the same id
+	forger := newTestPublicMaker(t, "key-1", newTestKey(t))
+	forged, _, err := forger.CreateToken(util.RandomOwner(), util.AdminRole, uuid.New(), time.Minute, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	// a token naming a key the maker does not know
+	unknown := newTestPublicMaker(t, "key-2", newTestKey(t))
+	unknownKeyToken, _, err := unknown.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	body := strings.Split(token, ".")[2]
+	for _, invalid := range []string{
+		forged,
+		unknownKeyToken,
+		strings.Replace(token, pasetoV4PublicHeader, "v2.public.", 1),
+		strings.Replace(token, body, body[:len(body)-4]+"AAAA", 1),
+		"v4.public.",
+	} {
+		_, err := maker.VerifyToken(invalid, TokenTypeAccessToken)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	}
+}
+
+func TestPasetoPublicMakerKeyRotation(t *testing.T) {
+	oldKey := newTestKey(t)
+	oldMaker := newTestPublicMaker(t, "key-1", oldKey)
+
+	oldToken, _, err := oldMaker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	// the retired key stays in the set with its public part only
+	keySet := NewKeySet()
+	keySet.AddPublicKey("key-1", oldKey.Public().(ed25519.PublicKey))
+	keySet.AddPrivateKey("key-2", newTestKey(t))
+
+	newMaker, err := NewPasetoPublicMaker(keySet, "key-2")
+	require.NoError(t, err)
+
+	_, err = newMaker.VerifyToken(oldToken, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	newToken, _, err := newMaker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute, TokenTypeAccessToken)
+	require.NoError(t, err)
+	_, err = newMaker.VerifyToken(newToken, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	_, err = NewPasetoPublicMaker(keySet, "key-1")
+	require.Error(t, err)
+
+	// a verifier holding public keys only cannot mint tokens
+	verifier, err := NewPasetoPublicMaker(keySet, "")
+	require.NoError(t, err)
+	_, err = verifier.VerifyToken(newToken, TokenTypeAccessToken)
+	require.NoError(t, err)
+	_, _, err = verifier.CreateToken(util.RandomOwner(), util.AdminRole, uuid.New(), time.Minute, TokenTypeAccessToken)
+	require.ErrorIs(t, err, ErrCannotSign)
+}
+
+// TestPasetoV4PublicVectors checks the encoding against the v4.public test vectors of the PASETO spec,
+// https://github.com/paseto-standard/test-vectors
+func TestPasetoV4PublicVectors(t *testing.T) {
+	secretKey, err := hex.DecodeString("b4cbfb43df4ce210727d953e4a713307fa19bb7d9f85041438d9e11b942a3774" +
+		"1eb9dbbbbc047c03fd70604e0071f0987e16b28b757225c11f00415d0e20b1a2")
+	require.NoError(t, err)
+	privateKey := ed25519.PrivateKey(secretKey)
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+
+	message := []byte(`{"data":"this is a signed message","exp":"2022-01-01T00:00:00+00:00"}`)
+	footer := []byte(`{"kid":"zVhMiPBP9fRf2snEcT7gFTioeA9COcNy9DfgL1W60haN"}`)
+
+	testCases := []struct {
+		name     string
+		footer   []byte
+		implicit []byte
+		token    string
+	}{
+		{
+			name: "4-S-1",
+			token: "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9" +
+				"bg_XBBzds8lTZShVlwwKSgeKpLT3yukTw6JUz3W4h_ExsQV-P0V54zemZDcAxFaSeef1QlXEFtkqxT1ciiQEDA",
+		},
+		{
+			name:   "4-S-2",
+			footer: footer,
+			token: "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9" +
+				"v3Jt8mx_TdM2ceTGoqwrh4yDFn0XsHvvV_D0DtwQxVrJEBMl0F2caAdgnpKlt4p7xBnx1HcO-SPo8FPp214HDw" +
+				".eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9",
+		},
+		{
+			name:     "4-S-3",
+			footer:   footer,
+			implicit: []byte(`{"test-vector":"4-S-3"}`),
+			token: "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9" +
+				"NPWciuD3d0o5eXJXG5pJy-DiVEoyPYWs1YSTwWHNJq6DZD3je5gf-0M4JR9ipdUSJbIovzmBECeaWmaqcaP0DQ" +
+				".eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			token := signPasetoV4Public(privateKey, message, tc.footer, tc.implicit)
+			require.Equal(t, tc.token, token)
+
+			verified, err := verifyPasetoV4Public(publicKey, token, tc.implicit)
+			require.NoError(t, err)
+			require.Equal(t, message, verified)
+
+			// the signature covers the implicit assertion, so any other one is rejected
+			_, err = verifyPasetoV4Public(publicKey, token, []byte(`{"test-vector":"other"}`))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestPasetoPublicTokenClaims(t *testing.T) {
+	privateKey := newTestKey(t)
+	maker := newTestPublicMaker(t, "key-1", privateKey)
+
+	token, payload, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	// the times are the registered claims of the spec, written as RFC 3339 strings
+	body, _, err := splitPasetoV4Public(token)
+	require.NoError(t, err)
+	var claims map[string]interface{}
+	require.NoError(t, json.Unmarshal(body[:len(body)-ed25519.SignatureSize], &claims))
+	for _, name := range []string{"iat", "nbf", "exp"} {
+		value, ok := claims[name].(string)
+		require.True(t, ok, name)
+		_, err := time.Parse(time.RFC3339, value)
+		require.NoError(t, err, name)
+	}
+	require.Equal(t, payload.Username, claims["sub"])
+	require.Equal(t, payload.ID.String(), claims["jti"])
+
+	signClaims := func(claims pasetoClaims) string {
+		message, err := json.Marshal(claims)
+		require.NoError(t, err)
+		return signPasetoV4Public(privateKey, message, []byte(`{"kid":"key-1"}`), nil)
+	}
+	newClaims := func() pasetoClaims {
+		return pasetoClaims{
+			TokenID:    uuid.New(),
+			Subject:    util.RandomOwner(),
+			IssuedAt:   time.Now(),
+			NotBefore:  time.Now(),
+			Expiration: time.Now().Add(time.Minute),
+			TokenType:  TokenTypeAccessToken,
+			Role:       util.DepositorRole,
+			SessionID:  uuid.New(),
+		}
+	}
+
+	_, err = maker.VerifyToken(signClaims(newClaims()), TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	notYetValid := newClaims()
+	notYetValid.NotBefore = time.Now().Add(10 * time.Minute)
+	missingExpiration := newClaims()
+	missingExpiration.Expiration = time.Time{}
+	missingIssuedAt := newClaims()
+	missingIssuedAt.IssuedAt = time.Time{}
+
+	for _, claims := range []pasetoClaims{notYetValid, missingExpiration, missingIssuedAt} {
+		_, err := maker.VerifyToken(signClaims(claims), TokenTypeAccessToken)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	}
+}
+
+func TestLoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+
+	privateKey := newTestKey(t)
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "key-2.pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600)
+	require.NoError(t, err)
+
+	retiredKey := newTestKey(t)
+	publicDER, err := x509.MarshalPKIXPublicKey(retiredKey.Public())
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "key-1.pem"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0600)
+	require.NoError(t, err)
+
+	keySet, err := LoadKeySet(dir)
+	require.NoError(t, err)
+
+	publicKeys := keySet.PublicKeys()
+	require.Len(t, publicKeys, 2)
+	require.Equal(t, "key-1", publicKeys[0].KeyID)
+	require.Equal(t, retiredKey.Public(), publicKeys[0].Key)
+	require.Equal(t, "key-2", publicKeys[1].KeyID)
+
+	maker, err := NewMaker(util.Config{TokenKeyDir: dir, TokenKeyID: "key-2"})
+	require.NoError(t, err)
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute, TokenTypeAccessToken)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(token, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, "broken.pem"), []byte("not a key"), 0600)
+	require.NoError(t, err)
+	_, err = LoadKeySet(dir)
+	require.Error(t, err)
+}
+
+func TestNewMaker(t *testing.T) {
+	maker, err := NewMaker(util.Config{TokenSymmetricKey: util.RandomString(32)})
+	require.NoError(t, err)
+	require.IsType(t, &PasetoMaker{}, maker)
+
+	seed := newTestKey(t).Seed()
+	maker, err = NewMaker(util.Config{
+		TokenKeyID:      "key-1",
+		TokenPrivateKey: base64.StdEncoding.EncodeToString(seed),
+	})
+	require.NoError(t, err)
+	require.IsType(t, &PasetoPublicMaker{}, maker)
+
+	_, err = NewMaker(util.Config{TokenKeyID: "key-1", TokenPrivateKey: "c2hvcnQ="})
+	require.Error(t, err)
+}
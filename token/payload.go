@@ -5,19 +5,34 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sangketkit01/simple-bank/util"
 )
 
 var ErrExpiredToken = errors.New("token has expired")
 var ErrInvalidToken = errors.New("invalid token")
 
+// TokenType tells access tokens from refresh tokens, so a refresh token cannot be used as a bearer credential
+type TokenType string
+
+const (
+	TokenTypeAccessToken TokenType = "access"
+	TokenTypeRefreshToken TokenType = "refresh"
+)
+
 type Payload struct {
 	ID uuid.UUID `json:"id"`
+	Type TokenType `json:"token_type"`
 	Username string    `json:"username"`
+	Role string `json:"role"`
+	SessionID uuid.UUID `json:"session_id"`
 	IssuedAt time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
+	// APIKeyID and Scopes are only set when the request was authenticated with an API key
+	APIKeyID int64 `json:"-"`
+	Scopes []string `json:"-"`
 }
 
-func NewPayload(username string, duration time.Duration) (*Payload, error){
+func NewPayload(username string, role string, sessionID uuid.UUID, duration time.Duration, tokenType TokenType) (*Payload, error){
 	tokenID, err := uuid.NewRandom()
 	if err != nil{
 		return nil, err
@@ -25,7 +40,10 @@ func NewPayload(username string, duration time.Duration) (*Payload, error){
 
 	payload := &Payload{
 		ID: tokenID,
+		Type: tokenType,
 		Username: username,
+		Role: role,
+		SessionID: sessionID,
 		IssuedAt: time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
@@ -33,10 +51,24 @@ func NewPayload(username string, duration time.Duration) (*Payload, error){
 }
 
 // Valid checks if the token payload is valid or not
-func (payload *Payload) Valid() error{
+func (payload *Payload) Valid(tokenType TokenType) error{
+	if payload.Type != tokenType{
+		return ErrInvalidToken
+	}
+
 	if time.Now().After(payload.ExpiredAt){
 		return ErrExpiredToken
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// AllowsScope reports whether the payload may be used for scope:
+// tokens act with every right of their role, API keys only with the scopes granted to them
+func (payload *Payload) AllowsScope(scope string) bool{
+	if payload.APIKeyID == 0{
+		return true
+	}
+
+	return util.HasScope(scope, payload.Scopes)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/aead/chacha20poly1305"
+	"github.com/google/uuid"
 	"github.com/o1egl/paseto"
 )
 
@@ -27,9 +28,9 @@ func NewPasetoMaker(symmetricKey string) (Maker, error){
 	return maker, nil
 }
 
-// CreateToken creates a new token for a specific username and duration
-func(maker *PasetoMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error){
-	payload, err := NewPayload(username, duration)
+// CreateToken creates a new token of the given type for a specific username, role, session and duration
+func(maker *PasetoMaker) CreateToken(username string, role string, sessionID uuid.UUID, duration time.Duration, tokenType TokenType) (string, *Payload, error){
+	payload, err := NewPayload(username, role, sessionID, duration, tokenType)
 	if err != nil{
 		return "", payload, err
 	}
@@ -38,8 +39,8 @@ func(maker *PasetoMaker) CreateToken(username string, duration time.Duration) (s
 	return token, payload, err
 }
 
-	// VerifyToken checks if the token is valid or not
-func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error){
+	// VerifyToken checks if the token is a valid token of the given type
+func (maker *PasetoMaker) VerifyToken(token string, tokenType TokenType) (*Payload, error){
 	payload := &Payload{}
 
 	err := maker.paseto.Decrypt(token, maker.symmetricKey, payload, nil)
@@ -47,7 +48,7 @@ func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error){
 		return nil , ErrInvalidToken
 	}
 
-	err = payload.Valid()
+	err = payload.Valid(tokenType)
 	if err != nil{
 		return nil, err
 	}
@@ -0,0 +1,62 @@
+package token
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const revokedSessionKeyPrefix = "revoked_session:"
+
+// RedisRevocationChecker shares revoked sessions between server instances through Redis.
+// Sessions it does not know about are checked by the next checker.
+type RedisRevocationChecker struct {
+	client          *redis.Client
+	next            RevocationChecker
+	revokedDuration time.Duration
+}
+
+// NewRedisRevocationChecker creates a new RedisRevocationChecker
+func NewRedisRevocationChecker(client *redis.Client, next RevocationChecker, revokedDuration time.Duration) *RedisRevocationChecker {
+	return &RedisRevocationChecker{
+		client:          client,
+		next:            next,
+		revokedDuration: revokedDuration,
+	}
+}
+
+// IsRevoked reports whether the session of the token payload has been revoked
+func (checker *RedisRevocationChecker) IsRevoked(ctx context.Context, payload *Payload) (bool, error) {
+	if payload.SessionID == uuid.Nil {
+		return true, nil
+	}
+
+	n, err := checker.client.Exists(ctx, revokedSessionKey(payload.SessionID)).Result()
+	if err == nil && n > 0 {
+		return true, nil
+	}
+
+	// the session store stays the source of truth when Redis is unavailable
+	return checker.next.IsRevoked(ctx, payload)
+}
+
+// Revoke marks the sessions as revoked right away, after they were blocked in the database
+func (checker *RedisRevocationChecker) Revoke(ctx context.Context, sessionIDs ...uuid.UUID) error {
+	if len(sessionIDs) > 0 {
+		pipe := checker.client.Pipeline()
+		for _, sessionID := range sessionIDs {
+			pipe.Set(ctx, revokedSessionKey(sessionID), 1, checker.revokedDuration)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	return checker.next.Revoke(ctx, sessionIDs...)
+}
+
+func revokedSessionKey(sessionID uuid.UUID) string {
+	return revokedSessionKeyPrefix + sessionID.String()
+}
@@ -0,0 +1,38 @@
+package token
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// RefreshTokenReuse describes a rotated refresh token that was presented again
+type RefreshTokenReuse struct {
+	Username          string
+	SessionID         uuid.UUID
+	FamilyID          uuid.UUID
+	BlockedSessionIDs []uuid.UUID
+	ClientIP          string
+	UserAgent         string
+}
+
+// RevokeReusedFamily revokes the sessions blocked because of a refresh token reuse and records the security event:
+// the token has probably been stolen, so every session of its family is signed out
+func RevokeReusedFamily(ctx context.Context, checker RevocationChecker, reuse RefreshTokenReuse) error {
+	err := checker.Revoke(ctx, reuse.BlockedSessionIDs...)
+	if err != nil {
+		return err
+	}
+
+	log.Warn().
+		Str("event", "refresh_token_reuse").
+		Str("username", reuse.Username).
+		Str("session_id", reuse.SessionID.String()).
+		Str("family_id", reuse.FamilyID.String()).
+		Int("blocked_sessions", len(reuse.BlockedSessionIDs)).
+		Str("client_ip", reuse.ClientIP).
+		Str("user_agent", reuse.UserAgent).
+		Msg("refresh token reuse detected, session family blocked")
+	return nil
+}
@@ -0,0 +1,140 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sangketkit01/simple-bank/util"
+)
+
+var ErrRevokedToken = errors.New("token has been revoked")
+
+const maxRevocationEntries = 10000
+
+// RevocationChecker tells whether the session behind a token has been revoked,
+// so a blocked session's access tokens stop working before they expire.
+// Tokens issued before their user last changed the password count as revoked too.
+type RevocationChecker interface {
+	// IsRevoked reports whether the session of the token payload has been revoked
+	// or the token was issued before the password change of its user
+	IsRevoked(ctx context.Context, payload *Payload) (bool, error)
+
+	// Revoke marks the sessions as revoked right away, after they were blocked in the database
+	Revoke(ctx context.Context, sessionIDs ...uuid.UUID) error
+}
+
+// NewRevocationChecker creates the revocation checker selected by the config:
+// the session store with an in-process cache, optionally shared between instances through Redis
+func NewRevocationChecker(config util.Config, lookup SessionLookup) RevocationChecker {
+	checker := NewCachedRevocationChecker(lookup, config.RevocationCacheDuration, config.AccessTokenDuration)
+	if config.RevocationStore == "redis" {
+		client := redis.NewClient(&redis.Options{Addr: config.RedisAddress})
+		return NewRedisRevocationChecker(client, checker, config.AccessTokenDuration)
+	}
+
+	return checker
+}
+
+// SessionState is what the session store knows about the session of a token
+type SessionState struct {
+	// Blocked rejects every token of the session
+	Blocked bool
+	// PasswordChangedAt rejects the tokens issued before the owner of the session changed the password
+	PasswordChangedAt time.Time
+}
+
+// revokes reports whether the state rejects the token payload.
+// The password change time is truncated to the second since JWT timestamps have no sub-second precision,
+// otherwise a token issued right after the change could look older than it.
+func (state SessionState) revokes(payload *Payload) bool {
+	return state.Blocked || payload.IssuedAt.Before(state.PasswordChangedAt.Truncate(time.Second))
+}
+
+// SessionLookup returns the state of a session in the session store
+type SessionLookup func(ctx context.Context, sessionID uuid.UUID) (SessionState, error)
+
+type revocationEntry struct {
+	state     SessionState
+	expiredAt time.Time
+}
+
+// CachedRevocationChecker looks sessions up in the session store and caches the answers in process.
+// A revoked session stays cached for revokedDuration, which should be at least the access token duration.
+type CachedRevocationChecker struct {
+	lookup          SessionLookup
+	cacheDuration   time.Duration
+	revokedDuration time.Duration
+
+	mutex   sync.Mutex
+	entries map[uuid.UUID]revocationEntry
+}
+
+// NewCachedRevocationChecker creates a new CachedRevocationChecker
+func NewCachedRevocationChecker(lookup SessionLookup, cacheDuration time.Duration, revokedDuration time.Duration) *CachedRevocationChecker {
+	return &CachedRevocationChecker{
+		lookup:          lookup,
+		cacheDuration:   cacheDuration,
+		revokedDuration: revokedDuration,
+		entries:         make(map[uuid.UUID]revocationEntry),
+	}
+}
+
+// IsRevoked reports whether the session of the token payload has been revoked
+func (checker *CachedRevocationChecker) IsRevoked(ctx context.Context, payload *Payload) (bool, error) {
+	// tokens that are not bound to a session cannot be revoked, so they are not accepted
+	if payload.SessionID == uuid.Nil {
+		return true, nil
+	}
+
+	now := time.Now()
+
+	checker.mutex.Lock()
+	entry, ok := checker.entries[payload.SessionID]
+	checker.mutex.Unlock()
+	if ok && now.Before(entry.expiredAt) {
+		return entry.state.revokes(payload), nil
+	}
+
+	state, err := checker.lookup(ctx, payload.SessionID)
+	if err != nil {
+		return false, err
+	}
+
+	duration := checker.cacheDuration
+	if state.Blocked {
+		duration = checker.revokedDuration
+	}
+	checker.set(payload.SessionID, revocationEntry{state: state, expiredAt: now.Add(duration)})
+
+	return state.revokes(payload), nil
+}
+
+// Revoke marks the sessions as revoked right away, after they were blocked in the database
+func (checker *CachedRevocationChecker) Revoke(ctx context.Context, sessionIDs ...uuid.UUID) error {
+	expiredAt := time.Now().Add(checker.revokedDuration)
+	for _, sessionID := range sessionIDs {
+		checker.set(sessionID, revocationEntry{state: SessionState{Blocked: true}, expiredAt: expiredAt})
+	}
+	return nil
+}
+
+func (checker *CachedRevocationChecker) set(sessionID uuid.UUID, entry revocationEntry) {
+	checker.mutex.Lock()
+	defer checker.mutex.Unlock()
+
+	// drop expired entries once the cache grows, so it never keeps every session ever seen
+	if len(checker.entries) >= maxRevocationEntries {
+		now := time.Now()
+		for id, e := range checker.entries {
+			if !now.Before(e.expiredAt) {
+				delete(checker.entries, id)
+			}
+		}
+	}
+
+	checker.entries[sessionID] = entry
+}
@@ -0,0 +1,116 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sangketkit01/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedRevocationChecker(t *testing.T) {
+	blocked := map[uuid.UUID]bool{}
+	lookups := 0
+	lookup := func(ctx context.Context, sessionID uuid.UUID) (SessionState, error) {
+		lookups++
+		return SessionState{Blocked: blocked[sessionID]}, nil
+	}
+
+	checker := NewCachedRevocationChecker(lookup, time.Minute, time.Minute)
+
+	payload, err := NewPayload(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	revoked, err := checker.IsRevoked(context.Background(), payload)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	// the answer is cached, so a block in the store alone is not seen yet
+	blocked[payload.SessionID] = true
+	revoked, err = checker.IsRevoked(context.Background(), payload)
+	require.NoError(t, err)
+	require.False(t, revoked)
+	require.Equal(t, 1, lookups)
+
+	// revoking takes effect right away
+	err = checker.Revoke(context.Background(), payload.SessionID)
+	require.NoError(t, err)
+	revoked, err = checker.IsRevoked(context.Background(), payload)
+	require.NoError(t, err)
+	require.True(t, revoked)
+	require.Equal(t, 1, lookups)
+}
+
+func TestCachedRevocationCheckerExpiredCache(t *testing.T) {
+	lookup := func(ctx context.Context, sessionID uuid.UUID) (SessionState, error) {
+		return SessionState{Blocked: true}, nil
+	}
+
+	checker := NewCachedRevocationChecker(lookup, -time.Second, time.Minute)
+
+	payload, err := NewPayload(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	revoked, err := checker.IsRevoked(context.Background(), payload)
+	require.NoError(t, err)
+	require.True(t, revoked)
+}
+
+func TestCachedRevocationCheckerNoSession(t *testing.T) {
+	lookup := func(ctx context.Context, sessionID uuid.UUID) (SessionState, error) {
+		return SessionState{}, nil
+	}
+
+	checker := NewCachedRevocationChecker(lookup, time.Minute, time.Minute)
+
+	payload, err := NewPayload(util.RandomOwner(), util.DepositorRole, uuid.Nil, time.Minute, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	revoked, err := checker.IsRevoked(context.Background(), payload)
+	require.NoError(t, err)
+	require.True(t, revoked)
+}
+
+func TestCachedRevocationCheckerLookupError(t *testing.T) {
+	lookupErr := errors.New("connection refused")
+	lookup := func(ctx context.Context, sessionID uuid.UUID) (SessionState, error) {
+		return SessionState{}, lookupErr
+	}
+
+	checker := NewCachedRevocationChecker(lookup, time.Minute, time.Minute)
+
+	payload, err := NewPayload(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	_, err = checker.IsRevoked(context.Background(), payload)
+	require.ErrorIs(t, err, lookupErr)
+}
+
+func TestCachedRevocationCheckerPasswordChanged(t *testing.T) {
+	sessionID := uuid.New()
+
+	oldPayload, err := NewPayload(util.RandomOwner(), util.DepositorRole, sessionID, time.Minute, TokenTypeAccessToken)
+	require.NoError(t, err)
+
+	passwordChangedAt := oldPayload.IssuedAt.Add(time.Second)
+	lookup := func(ctx context.Context, sessionID uuid.UUID) (SessionState, error) {
+		return SessionState{PasswordChangedAt: passwordChangedAt}, nil
+	}
+
+	checker := NewCachedRevocationChecker(lookup, time.Minute, time.Minute)
+
+	// a token issued before the password change is rejected even though its session is not blocked
+	revoked, err := checker.IsRevoked(context.Background(), oldPayload)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	// the cached state still accepts tokens issued after the change
+	newPayload := *oldPayload
+	newPayload.IssuedAt = passwordChangedAt.Add(time.Second)
+	revoked, err = checker.IsRevoked(context.Background(), &newPayload)
+	require.NoError(t, err)
+	require.False(t, revoked)
+}
@@ -0,0 +1,44 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	// APIKeyPrefix marks the secrets that are API keys, so they are easy to spot in configs and leaks
+	APIKeyPrefix = "sbk_"
+	apiKeySize   = 32
+)
+
+// GenerateAPIKey returns a new random API key, shown to its owner once and only stored hashed
+func GenerateAPIKey() (string, error) {
+	buf := make([]byte, apiKeySize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("cannot generate api key: %w", err)
+	}
+
+	return APIKeyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// IsAPIKey reports whether key has the format of a generated API key
+func IsAPIKey(key string) bool {
+	encoded, ok := strings.CutPrefix(key, APIKeyPrefix)
+	if !ok {
+		return false
+	}
+
+	buf, err := base64.RawURLEncoding.DecodeString(encoded)
+	return err == nil && len(buf) == apiKeySize
+}
+
+// HashAPIKey returns the value stored for an API key.
+// The keys are random, so a fast hash is enough and lets them be looked up directly.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key1, err := GenerateAPIKey()
+	require.NoError(t, err)
+	require.True(t, IsAPIKey(key1))
+
+	key2, err := GenerateAPIKey()
+	require.NoError(t, err)
+	require.NotEqual(t, key1, key2)
+
+	require.Len(t, HashAPIKey(key1), 64)
+	require.Equal(t, HashAPIKey(key1), HashAPIKey(key1))
+	require.NotEqual(t, HashAPIKey(key1), HashAPIKey(key2))
+
+	require.False(t, IsAPIKey(""))
+	require.False(t, IsAPIKey(key1[len(APIKeyPrefix):]))
+	require.False(t, IsAPIKey(APIKeyPrefix+"short"))
+}
+
+func TestHasScope(t *testing.T) {
+	scopes := []string{AccountsReadScope, TransfersWriteScope}
+
+	require.True(t, HasScope(AccountsReadScope, scopes))
+	require.True(t, HasScope(TransfersWriteScope, scopes))
+	require.False(t, HasScope(AccountsWriteScope, scopes))
+	require.False(t, HasScope(TransfersReadScope, nil))
+
+	require.True(t, IsSupportedScope(TransfersReadScope))
+	require.False(t, IsSupportedScope("accounts:*"))
+}
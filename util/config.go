@@ -16,9 +16,38 @@ type Config struct {
 	RedisAddress         string        `mapstructure:"REDIS_ADDRESS"`
 	HttpServerAddress    string        `mapstructure:"HTTP_SERVER_ADDRESS"`
 	GrpcServerAddress    string        `mapstructure:"GRPC_SERVER_ADDRESS"`
+	TokenType string `mapstructure:"TOKEN_TYPE"`
 	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	TokenIssuer string `mapstructure:"TOKEN_ISSUER"`
+	TokenAudience string `mapstructure:"TOKEN_AUDIENCE"`
+	TokenLeeway time.Duration `mapstructure:"TOKEN_LEEWAY"`
+	TokenKeyDir string `mapstructure:"TOKEN_KEY_DIR"`
+	TokenKeyID string `mapstructure:"TOKEN_KEY_ID"`
+	TokenPrivateKey string `mapstructure:"TOKEN_PRIVATE_KEY"`
 	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	IdempotencyKeyDuration time.Duration `mapstructure:"IDEMPOTENCY_KEY_DURATION"`
+	RevocationStore string `mapstructure:"REVOCATION_STORE"`
+	RevocationCacheDuration time.Duration `mapstructure:"REVOCATION_CACHE_DURATION"`
+	MFAChallengeDuration time.Duration `mapstructure:"MFA_CHALLENGE_DURATION"`
+	RateLimitStore string `mapstructure:"RATE_LIMIT_STORE"`
+	LoginAttemptWindow time.Duration `mapstructure:"LOGIN_ATTEMPT_WINDOW"`
+	LoginUsernameAttemptLimit int `mapstructure:"LOGIN_USERNAME_ATTEMPT_LIMIT"`
+	LoginIPAttemptLimit int `mapstructure:"LOGIN_IP_ATTEMPT_LIMIT"`
+	LoginLockoutThreshold int32 `mapstructure:"LOGIN_LOCKOUT_THRESHOLD"`
+	LoginLockoutDuration time.Duration `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+	LoginMaxLockoutDuration time.Duration `mapstructure:"LOGIN_MAX_LOCKOUT_DURATION"`
+	VerifyEmailResendWindow time.Duration `mapstructure:"VERIFY_EMAIL_RESEND_WINDOW"`
+	VerifyEmailResendLimit int `mapstructure:"VERIFY_EMAIL_RESEND_LIMIT"`
+	PasswordResetRequestWindow time.Duration `mapstructure:"PASSWORD_RESET_REQUEST_WINDOW"`
+	PasswordResetEmailLimit int `mapstructure:"PASSWORD_RESET_EMAIL_LIMIT"`
+	PasswordResetIPLimit int `mapstructure:"PASSWORD_RESET_IP_LIMIT"`
+	TransferQuoteDuration time.Duration `mapstructure:"TRANSFER_QUOTE_DURATION"`
+	CurrencyRefreshInterval time.Duration `mapstructure:"CURRENCY_REFRESH_INTERVAL"`
+	ScheduledTransferDispatchInterval time.Duration `mapstructure:"SCHEDULED_TRANSFER_DISPATCH_INTERVAL"`
+	HoldDuration time.Duration `mapstructure:"HOLD_DURATION"`
+	HoldExpiryInterval time.Duration `mapstructure:"HOLD_EXPIRY_INTERVAL"`
+	AppBaseURL string `mapstructure:"APP_BASE_URL"`
 	EmailSenderName string `mapstructure:"EMAIL_SENDER_NAME"`
 	EmailSenderAddress string `mapstructure:"EMAIL_SENDER_ADDRESS"`
 	EmailSenderPassword string `mapstructure:"EMAIL_SENDER_PASSWORD"`
@@ -1,16 +1,114 @@
 package util
 
-const(
+import (
+	"fmt"
+	"sync"
+)
+
+const (
 	USD = "USD"
 	EUR = "EUR"
 	CAD = "CAD"
 )
 
-func IsSupportedCurrency(currency string) bool{
-	switch(currency){
-	case USD, EUR, CAD :
-		return true
+// AmountExponent is the number of decimal places of every stored amount:
+// balances, entries and transfers count hundredths of their currency unit
+const AmountExponent = 2
+
+// Currency is an entry of the currency registry
+type Currency struct {
+	// Code is the ISO 4217 currency code
+	Code string
+	// MinorUnit is the number of decimal places of the currency, at most AmountExponent
+	MinorUnit int32
+	// Enabled currencies can be used by new accounts and transfers
+	Enabled bool
+}
+
+// amountStep is the smallest amount the currency can express in hundredths
+func (currency Currency) amountStep() int64 {
+	step := int64(1)
+	for i := currency.MinorUnit; i < AmountExponent; i++ {
+		step *= 10
+	}
+	return step
+}
+
+// defaultCurrencies are served until the registry is loaded from the currencies table
+var defaultCurrencies = []Currency{
+	{Code: USD, MinorUnit: 2, Enabled: true},
+	{Code: EUR, MinorUnit: 2, Enabled: true},
+	{Code: CAD, MinorUnit: 2, Enabled: true},
+}
+
+var currencyRegistry = struct {
+	mutex      sync.RWMutex
+	currencies map[string]Currency
+}{
+	currencies: currencyMap(defaultCurrencies),
+}
+
+func currencyMap(currencies []Currency) map[string]Currency {
+	result := make(map[string]Currency, len(currencies))
+	for _, currency := range currencies {
+		result[currency.Code] = currency
+	}
+	return result
+}
+
+// SetCurrencies replaces the currency registry, after the currencies table has been (re)loaded
+func SetCurrencies(currencies []Currency) error {
+	for _, currency := range currencies {
+		if currency.MinorUnit < 0 || currency.MinorUnit > AmountExponent {
+			return fmt.Errorf("currency %s has %d decimal places, at most %d are supported", currency.Code, currency.MinorUnit, AmountExponent)
+		}
+	}
+
+	registry := currencyMap(currencies)
+
+	currencyRegistry.mutex.Lock()
+	defer currencyRegistry.mutex.Unlock()
+	currencyRegistry.currencies = registry
+	return nil
+}
+
+// LookupCurrency returns the registry entry of the currency code
+func LookupCurrency(code string) (Currency, bool) {
+	currencyRegistry.mutex.RLock()
+	defer currencyRegistry.mutex.RUnlock()
+
+	currency, ok := currencyRegistry.currencies[code]
+	return currency, ok
+}
+
+// IsSupportedCurrency reports whether the currency is known and enabled
+func IsSupportedCurrency(code string) bool {
+	currency, ok := LookupCurrency(code)
+	return ok && currency.Enabled
+}
+
+// CheckAmountPrecision returns an error when the amount has more decimal places than the currency allows,
+// e.g. 1050 is 10.50, which is fine in USD but not in JPY
+func CheckAmountPrecision(amount int64, code string) error {
+	currency, ok := LookupCurrency(code)
+	if !ok {
+		return fmt.Errorf("unknown currency %s", code)
+	}
+
+	if amount%currency.amountStep() != 0 {
+		return fmt.Errorf("%s amounts must not have more than %d decimal places", code, currency.MinorUnit)
+	}
+
+	return nil
+}
+
+// RoundAmount rounds the amount down to the precision of the currency
+func RoundAmount(amount int64, code string) (int64, error) {
+	currency, ok := LookupCurrency(code)
+	if !ok {
+		return 0, fmt.Errorf("unknown currency %s", code)
 	}
 
-	return false
-}
\ No newline at end of file
+	step := currency.amountStep()
+	return amount - amount%step, nil
+}
@@ -0,0 +1,51 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setTestCurrencies(t *testing.T, currencies []Currency) {
+	require.NoError(t, SetCurrencies(currencies))
+	t.Cleanup(func() {
+		require.NoError(t, SetCurrencies(defaultCurrencies))
+	})
+}
+
+func TestSetCurrencies(t *testing.T) {
+	setTestCurrencies(t, []Currency{
+		{Code: USD, MinorUnit: 2, Enabled: true},
+		{Code: "JPY", MinorUnit: 0, Enabled: true},
+		{Code: CAD, MinorUnit: 2, Enabled: false},
+	})
+
+	require.True(t, IsSupportedCurrency(USD))
+	require.True(t, IsSupportedCurrency("JPY"))
+	require.False(t, IsSupportedCurrency(CAD))
+	require.False(t, IsSupportedCurrency(EUR))
+
+	err := SetCurrencies([]Currency{{Code: "KWD", MinorUnit: 3, Enabled: true}})
+	require.Error(t, err)
+	require.True(t, IsSupportedCurrency("JPY"))
+}
+
+func TestCheckAmountPrecision(t *testing.T) {
+	setTestCurrencies(t, []Currency{
+		{Code: USD, MinorUnit: 2, Enabled: true},
+		{Code: "JPY", MinorUnit: 0, Enabled: true},
+	})
+
+	require.NoError(t, CheckAmountPrecision(1050, USD))
+	require.NoError(t, CheckAmountPrecision(1000, "JPY"))
+	require.Error(t, CheckAmountPrecision(1050, "JPY"))
+	require.Error(t, CheckAmountPrecision(1000, EUR))
+
+	rounded, err := RoundAmount(1099, "JPY")
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), rounded)
+
+	rounded, err = RoundAmount(1099, USD)
+	require.NoError(t, err)
+	require.Equal(t, int64(1099), rounded)
+}
@@ -0,0 +1,52 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorSigner(t *testing.T) {
+	signer, err := NewCursorSigner(RandomString(32))
+	require.NoError(t, err)
+
+	cursor := Cursor{
+		ID:        RandomInt(1, 1000),
+		CreatedAt: time.Now(),
+	}
+
+	token := signer.Encode("accounts", cursor)
+	require.NotEmpty(t, token)
+
+	decoded, err := signer.Decode("accounts", token)
+	require.NoError(t, err)
+	require.Equal(t, cursor.ID, decoded.ID)
+	require.WithinDuration(t, cursor.CreatedAt, decoded.CreatedAt, time.Microsecond)
+}
+
+func TestCursorSignerInvalidToken(t *testing.T) {
+	signer, err := NewCursorSigner(RandomString(32))
+	require.NoError(t, err)
+
+	token := signer.Encode("accounts", Cursor{ID: 1, CreatedAt: time.Now()})
+
+	_, err = signer.Decode("entries", token)
+	require.ErrorIs(t, err, ErrInvalidCursor)
+
+	tampered := []byte(token)
+	tampered[0] ^= 1
+	_, err = signer.Decode("accounts", string(tampered))
+	require.ErrorIs(t, err, ErrInvalidCursor)
+
+	_, err = signer.Decode("accounts", "not a token")
+	require.ErrorIs(t, err, ErrInvalidCursor)
+
+	otherSigner, err := NewCursorSigner(RandomString(32))
+	require.NoError(t, err)
+	_, err = otherSigner.Decode("accounts", token)
+	require.ErrorIs(t, err, ErrInvalidCursor)
+
+	_, err = NewCursorSigner(RandomString(10))
+	require.Error(t, err)
+}
@@ -0,0 +1,23 @@
+package util
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// ConvertAmount converts an amount with an exchange rate given as a decimal string.
+// The result is rounded down, so a conversion never credits more than the rate allows.
+func ConvertAmount(amount int64, rate string) (int64, error) {
+	exchangeRate, ok := new(big.Rat).SetString(rate)
+	if !ok || exchangeRate.Sign() <= 0 {
+		return 0, fmt.Errorf("invalid exchange rate %q", rate)
+	}
+
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), exchangeRate)
+	result := new(big.Int).Quo(converted.Num(), converted.Denom())
+	if !result.IsInt64() {
+		return 0, fmt.Errorf("converted amount overflows")
+	}
+
+	return result.Int64(), nil
+}
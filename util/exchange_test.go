@@ -0,0 +1,44 @@
+package util
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertAmount(t *testing.T) {
+	testCases := []struct {
+		name     string
+		amount   int64
+		rate     string
+		expected int64
+	}{
+		{name: "SameValue", amount: 1000, rate: "1", expected: 1000},
+		{name: "Multiply", amount: 1000, rate: "1.0850000000", expected: 1085},
+		{name: "RoundDown", amount: 999, rate: "0.9216000000", expected: 920},
+		{name: "TooSmall", amount: 1, rate: "0.5", expected: 0},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			converted, err := ConvertAmount(tc.amount, tc.rate)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, converted)
+		})
+	}
+
+	_, err := ConvertAmount(100, "0")
+	require.Error(t, err)
+
+	_, err = ConvertAmount(100, "-1.5")
+	require.Error(t, err)
+
+	_, err = ConvertAmount(100, "abc")
+	require.Error(t, err)
+
+	_, err = ConvertAmount(math.MaxInt64, "2")
+	require.Error(t, err)
+}
@@ -0,0 +1,9 @@
+package util
+
+// Statuses of an account hold
+const (
+	HoldActive   = "active"
+	HoldCaptured = "captured"
+	HoldReleased = "released"
+	HoldExpired  = "expired"
+)
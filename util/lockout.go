@@ -0,0 +1,22 @@
+package util
+
+import "time"
+
+// LockoutDuration returns how long a user is locked out after failedCount failed logins in a row.
+// Reaching threshold locks the user for duration, and every further failure doubles it up to maxDuration.
+// A threshold of zero or less disables the lockout.
+func LockoutDuration(failedCount int32, threshold int32, duration time.Duration, maxDuration time.Duration) time.Duration {
+	if threshold <= 0 || failedCount < threshold {
+		return 0
+	}
+
+	lockout := duration
+	for i := threshold; i < failedCount && lockout < maxDuration; i++ {
+		lockout *= 2
+	}
+
+	if lockout > maxDuration {
+		return maxDuration
+	}
+	return lockout
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	testCases := []struct {
+		name        string
+		failedCount int32
+		threshold   int32
+		expected    time.Duration
+	}{
+		{name: "BelowThreshold", failedCount: 4, threshold: 5, expected: 0},
+		{name: "AtThreshold", failedCount: 5, threshold: 5, expected: time.Minute},
+		{name: "Doubles", failedCount: 7, threshold: 5, expected: 4 * time.Minute},
+		{name: "Capped", failedCount: 1000, threshold: 5, expected: time.Hour},
+		{name: "Disabled", failedCount: 1000, threshold: 0, expected: 0},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			lockout := LockoutDuration(tc.failedCount, tc.threshold, time.Minute, time.Hour)
+			require.Equal(t, tc.expected, lockout)
+		})
+	}
+}
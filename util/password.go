@@ -6,6 +6,10 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// DummyHashedPassword is checked instead of a real hash when there is no password to check,
+// it has the cost of HashPassword so every failed login takes about as long
+const DummyHashedPassword = "$2a$10$7dEzjH.9VWC4Hw7M3FwU7eh/eNpbUnHhTMmQpLMGAkeuEe4iZeDxy"
+
 func HashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password),bcrypt.DefaultCost)
 	if err != nil{
@@ -0,0 +1,37 @@
+package util
+
+const(
+	DepositorRole = "depositor"
+	BankerRole = "banker"
+	AdminRole = "admin"
+)
+
+func IsSupportedRole(role string) bool{
+	switch(role){
+	case DepositorRole, BankerRole, AdminRole :
+		return true
+	}
+
+	return false
+}
+
+// HasRole reports whether role is one of the accessible roles
+func HasRole(role string, accessibleRoles []string) bool{
+	for _, accessibleRole := range accessibleRoles{
+		if role == accessibleRole{
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanReadAnyAccount reports whether role may read accounts owned by other users
+func CanReadAnyAccount(role string) bool{
+	return role == BankerRole
+}
+
+// CanManageUsers reports whether role may update other users and change their role
+func CanManageUsers(role string) bool{
+	return role == AdminRole
+}
@@ -0,0 +1,180 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Statuses of a scheduled transfer
+const (
+	ScheduleActive    = "active"
+	SchedulePaused    = "paused"
+	ScheduleSuspended = "suspended"
+	ScheduleCompleted = "completed"
+	ScheduleCancelled = "cancelled"
+)
+
+// Statuses of a run of a scheduled transfer
+const (
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+)
+
+// Frequencies supported by schedules
+const (
+	DailyFrequency   = "DAILY"
+	WeeklyFrequency  = "WEEKLY"
+	MonthlyFrequency = "MONTHLY"
+)
+
+const maxScheduleInterval = 365
+
+// Schedule is the subset of an iCalendar RRULE that scheduled transfers support:
+// FREQ=DAILY|WEEKLY|MONTHLY with optional INTERVAL, and COUNT or UNTIL,
+// e.g. "FREQ=MONTHLY;INTERVAL=1;COUNT=12"
+type Schedule struct {
+	Frequency string
+	Interval  int
+	// Count limits the number of occurrences, zero means no limit
+	Count int
+	// Until is the last time an occurrence may happen, zero means no limit
+	Until time.Time
+}
+
+// ParseSchedule parses a schedule rule, with or without the "RRULE:" prefix
+func ParseSchedule(rule string) (Schedule, error) {
+	schedule := Schedule{Interval: 1}
+
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return schedule, fmt.Errorf("schedule must not be empty")
+	}
+
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(rule, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return schedule, fmt.Errorf("invalid schedule part %q", part)
+		}
+
+		name = strings.ToUpper(name)
+		if seen[name] {
+			return schedule, fmt.Errorf("schedule part %s is repeated", name)
+		}
+		seen[name] = true
+
+		switch name {
+		case "FREQ":
+			value = strings.ToUpper(value)
+			switch value {
+			case DailyFrequency, WeeklyFrequency, MonthlyFrequency:
+				schedule.Frequency = value
+			default:
+				return schedule, fmt.Errorf("unsupported frequency %s", value)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 || interval > maxScheduleInterval {
+				return schedule, fmt.Errorf("interval must be between 1 and %d", maxScheduleInterval)
+			}
+			schedule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return schedule, fmt.Errorf("count must be a positive integer")
+			}
+			schedule.Count = count
+		case "UNTIL":
+			until, err := parseScheduleTime(value)
+			if err != nil {
+				return schedule, err
+			}
+			schedule.Until = until
+		default:
+			return schedule, fmt.Errorf("unsupported schedule part %s", name)
+		}
+	}
+
+	if schedule.Frequency == "" {
+		return schedule, fmt.Errorf("schedule must have a FREQ")
+	}
+	if schedule.Count > 0 && !schedule.Until.IsZero() {
+		return schedule, fmt.Errorf("schedule must not have both COUNT and UNTIL")
+	}
+
+	return schedule, nil
+}
+
+// parseScheduleTime parses an UNTIL value, a UTC date-time like 20250131T090000Z or a date like 20250131
+func parseScheduleTime(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// a date includes the whole day
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("until must be a UTC date-time like 20250131T090000Z or a date like 20250131")
+}
+
+// Occurrence returns the n-th occurrence of the schedule starting at start, counting from 0.
+// It reports false when the schedule ends before it.
+// Monthly occurrences keep the day of month of start, clamped to the last day of shorter months.
+func (schedule Schedule) Occurrence(start time.Time, n int) (time.Time, bool) {
+	if n < 0 || (schedule.Count > 0 && n >= schedule.Count) {
+		return time.Time{}, false
+	}
+
+	start = start.UTC()
+	steps := n * schedule.Interval
+
+	var occurrence time.Time
+	switch schedule.Frequency {
+	case DailyFrequency:
+		occurrence = start.AddDate(0, 0, steps)
+	case WeeklyFrequency:
+		occurrence = start.AddDate(0, 0, 7*steps)
+	case MonthlyFrequency:
+		occurrence = addMonths(start, steps)
+	default:
+		return time.Time{}, false
+	}
+
+	if !schedule.Until.IsZero() && occurrence.After(schedule.Until) {
+		return time.Time{}, false
+	}
+
+	return occurrence, true
+}
+
+// NextOccurrence returns the first occurrence, from the n-th on, that is not before after, along with its index.
+// It reports false when the schedule ends before it.
+func (schedule Schedule) NextOccurrence(start time.Time, n int, after time.Time) (time.Time, int, bool) {
+	for ; ; n++ {
+		occurrence, ok := schedule.Occurrence(start, n)
+		if !ok {
+			return time.Time{}, n, false
+		}
+		if !occurrence.Before(after) {
+			return occurrence, n, true
+		}
+	}
+}
+
+// addMonths adds months to t, keeping its day of month unless the target month is shorter
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+
+	lastDay := first.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return first.AddDate(0, 0, day-1)
+}
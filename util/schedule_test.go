@@ -0,0 +1,83 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	schedule, err := ParseSchedule("RRULE:FREQ=MONTHLY;INTERVAL=2;COUNT=6")
+	require.NoError(t, err)
+	require.Equal(t, Schedule{Frequency: MonthlyFrequency, Interval: 2, Count: 6}, schedule)
+
+	schedule, err = ParseSchedule("freq=weekly;until=20250131")
+	require.NoError(t, err)
+	require.Equal(t, WeeklyFrequency, schedule.Frequency)
+	require.Equal(t, 1, schedule.Interval)
+	require.Equal(t, time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC), schedule.Until)
+
+	invalidRules := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250131",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ",
+	}
+	for _, rule := range invalidRules {
+		_, err := ParseSchedule(rule)
+		require.Error(t, err, rule)
+	}
+}
+
+func TestScheduleOccurrence(t *testing.T) {
+	start := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	schedule, err := ParseSchedule("FREQ=MONTHLY;COUNT=3")
+	require.NoError(t, err)
+
+	expected := []time.Time{
+		start,
+		time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 31, 9, 0, 0, 0, time.UTC),
+	}
+	for n, occurrence := range expected {
+		actual, ok := schedule.Occurrence(start, n)
+		require.True(t, ok)
+		require.Equal(t, occurrence, actual)
+	}
+
+	_, ok := schedule.Occurrence(start, 3)
+	require.False(t, ok)
+
+	schedule, err = ParseSchedule("FREQ=WEEKLY;INTERVAL=2;UNTIL=20250301T000000Z")
+	require.NoError(t, err)
+
+	occurrence, ok := schedule.Occurrence(start, 2)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC), occurrence)
+
+	_, ok = schedule.Occurrence(start, 3)
+	require.False(t, ok)
+}
+
+func TestScheduleNextOccurrence(t *testing.T) {
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	schedule, err := ParseSchedule("FREQ=DAILY;COUNT=10")
+	require.NoError(t, err)
+
+	occurrence, n, ok := schedule.NextOccurrence(start, 0, time.Date(2025, 1, 3, 10, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	require.Equal(t, 3, n)
+	require.Equal(t, time.Date(2025, 1, 4, 9, 0, 0, 0, time.UTC), occurrence)
+
+	_, _, ok = schedule.NextOccurrence(start, 0, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+	require.False(t, ok)
+}
@@ -0,0 +1,30 @@
+package util
+
+// Scopes limit what an API key may do on behalf of its owner
+const (
+	AccountsReadScope   = "accounts:read"
+	AccountsWriteScope  = "accounts:write"
+	TransfersReadScope  = "transfers:read"
+	TransfersWriteScope = "transfers:write"
+)
+
+// IsSupportedScope reports whether scope can be granted to an API key
+func IsSupportedScope(scope string) bool {
+	switch scope {
+	case AccountsReadScope, AccountsWriteScope, TransfersReadScope, TransfersWriteScope:
+		return true
+	}
+
+	return false
+}
+
+// HasScope reports whether scope is one of the granted scopes
+func HasScope(scope string, grantedScopes []string) bool {
+	for _, grantedScope := range grantedScopes {
+		if scope == grantedScope {
+			return true
+		}
+	}
+
+	return false
+}
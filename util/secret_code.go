@@ -0,0 +1,19 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+const secretCodeSize = 24
+
+// GenerateSecretCode returns a random 32 character code for links mailed to a user, such as a password reset
+func GenerateSecretCode() (string, error) {
+	buf := make([]byte, secretCodeSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("cannot generate secret code: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
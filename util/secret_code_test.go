@@ -0,0 +1,17 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSecretCode(t *testing.T) {
+	code1, err := GenerateSecretCode()
+	require.NoError(t, err)
+	require.Len(t, code1, 32)
+
+	code2, err := GenerateSecretCode()
+	require.NoError(t, err)
+	require.NotEqual(t, code1, code2)
+}
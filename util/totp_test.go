@@ -0,0 +1,90 @@
+package util
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// SHA1 test vectors of RFC 6238 appendix B, truncated to six digits
+func TestTOTPCodeRFC6238(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	testCases := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+		{unix: 20000000000, code: "353130"},
+	}
+
+	for _, tc := range testCases {
+		code, err := TOTPCode(secret, time.Unix(tc.unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, tc.code, code)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+
+	now := time.Now()
+	code, err := TOTPCode(secret, now)
+	require.NoError(t, err)
+
+	step, ok := ValidateTOTP(secret, code, now)
+	require.True(t, ok)
+	require.Equal(t, TOTPStep(now), step)
+
+	// a code from the previous step is still accepted to allow for clock drift
+	step, ok = ValidateTOTP(secret, code, now.Add(TOTPPeriod))
+	require.True(t, ok)
+	require.Equal(t, TOTPStep(now), step)
+
+	_, ok = ValidateTOTP(secret, code, now.Add(3*TOTPPeriod))
+	require.False(t, ok)
+
+	_, ok = ValidateTOTP(secret, "12345", now)
+	require.False(t, ok)
+
+	_, ok = ValidateTOTP("not base32!", code, now)
+	require.False(t, ok)
+}
+
+func TestTOTPURI(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+
+	uri := TOTPURI("Simple Bank", "alice", secret)
+	require.True(t, strings.HasPrefix(uri, "otpauth://totp/Simple%20Bank:alice?"))
+	require.Contains(t, uri, "secret="+secret)
+	require.Contains(t, uri, "digits=6")
+	require.Contains(t, uri, "period=30")
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(RecoveryCodeCount)
+	require.NoError(t, err)
+	require.Len(t, codes, RecoveryCodeCount)
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		require.Len(t, code, 11)
+		require.Equal(t, byte('-'), code[5])
+		require.False(t, seen[code])
+		seen[code] = true
+	}
+
+	code := codes[0]
+	hashed := HashRecoveryCode(code)
+	require.Equal(t, hashed, HashRecoveryCode(strings.ToUpper(strings.ReplaceAll(code, "-", ""))))
+	require.NotEqual(t, hashed, HashRecoveryCode(codes[1]))
+}
@@ -4,11 +4,15 @@ import (
 	"fmt"
 	"net/mail"
 	"regexp"
+	"strings"
+
+	"github.com/sangketkit01/simple-bank/util"
 )
 
 var (
 	isValidUsername = regexp.MustCompile(`^[a-zA-z0-9_]+$`).MatchString
 	isValidFullName = regexp.MustCompile(`^[a-zA-z0-9\s]+$`).MatchString
+	isValidTOTPCode = regexp.MustCompile(`^[0-9]+$`).MatchString
 )
 
 func ValidateString(value string, minLength int, maxLength int) error {
@@ -70,4 +74,122 @@ func ValidateEmailID(value int64) error{
 
 func ValidateSecretCode(value string) error{
 	return ValidateString(value, 32, 128)
-}
\ No newline at end of file
+}
+
+func ValidateID(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be a positive integer")
+	}
+
+	return nil
+}
+
+func ValidateCurrency(value string) error {
+	if !util.IsSupportedCurrency(value) {
+		return fmt.Errorf("unsupported currency")
+	}
+
+	return nil
+}
+
+func ValidateRole(value string) error {
+	if !util.IsSupportedRole(value) {
+		return fmt.Errorf("unsupported role")
+	}
+
+	return nil
+}
+
+func ValidatePageSize(value int32) error {
+	if value < 5 || value > 10 {
+		return fmt.Errorf("must be between 5 and 10")
+	}
+
+	return nil
+}
+
+func ValidateAmount(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be greater than 0")
+	}
+
+	return nil
+}
+
+// ValidateCurrencyAmount checks that the amount is positive and fits the precision of the currency
+func ValidateCurrencyAmount(value int64, currency string) error {
+	if err := ValidateAmount(value); err != nil {
+		return err
+	}
+
+	return util.CheckAmountPrecision(value, currency)
+}
+
+func ValidateIdempotencyKey(value string) error {
+	return ValidateString(value, 1, 255)
+}
+
+func ValidateTOTPCode(value string) error {
+	if len(value) != util.TOTPDigits || !isValidTOTPCode(value) {
+		return fmt.Errorf("must contain %d digits", util.TOTPDigits)
+	}
+
+	return nil
+}
+
+func ValidateRecoveryCode(value string) error {
+	return ValidateString(value, 10, 11)
+}
+
+func ValidateAPIKeyName(value string) error {
+	return ValidateString(value, 1, 100)
+}
+
+func ValidateScopes(values []string) error {
+	if len(values) == 0 {
+		return fmt.Errorf("must contain at least one scope")
+	}
+
+	for _, value := range values {
+		if !util.IsSupportedScope(value) {
+			return fmt.Errorf("unsupported scope %s", value)
+		}
+	}
+
+	return nil
+}
+
+func ValidateAPIKeyValidDays(value int32) error {
+	if value < 1 || value > 365 {
+		return fmt.Errorf("must be between 1 and 365")
+	}
+
+	return nil
+}
+
+func ValidateSchedule(value string) error {
+	if err := ValidateString(value, 1, 200); err != nil {
+		return err
+	}
+
+	_, err := util.ParseSchedule(value)
+	return err
+}
+
+// ValidateScheduledTransferStatus checks a status requested by the owner, the others are set by the server
+func ValidateScheduledTransferStatus(value string) error {
+	if value != util.ScheduleActive && value != util.SchedulePaused {
+		return fmt.Errorf("must be %s or %s", util.ScheduleActive, util.SchedulePaused)
+	}
+
+	return nil
+}
+
+// ValidateReversalReason checks the reason a transfer is reversed, it is kept on the compensating transfer
+func ValidateReversalReason(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("must not be blank")
+	}
+
+	return ValidateString(value, 1, 500)
+}
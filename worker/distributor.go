@@ -8,6 +8,8 @@ import (
 
 type TaskDistributor interface {
 	DistributeTaskSendVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail, opts ...asynq.Option) error
+	DistributeTaskSendPasswordResetEmail(ctx context.Context, payload *PayloadSendPasswordResetEmail, opts ...asynq.Option) error
+	DistributeTaskExecuteScheduledTransfer(ctx context.Context, payload *PayloadExecuteScheduledTransfer, opts ...asynq.Option) error
 }
 
 type RedisTaskDistributor struct {
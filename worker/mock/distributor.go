@@ -42,6 +42,44 @@ func (m *MockTaskDistributor) EXPECT() *MockTaskDistributorMockRecorder {
 	return m.recorder
 }
 
+// DistributeTaskExecuteScheduledTransfer mocks base method.
+func (m *MockTaskDistributor) DistributeTaskExecuteScheduledTransfer(ctx context.Context, payload *worker.PayloadExecuteScheduledTransfer, opts ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, payload}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DistributeTaskExecuteScheduledTransfer", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DistributeTaskExecuteScheduledTransfer indicates an expected call of DistributeTaskExecuteScheduledTransfer.
+func (mr *MockTaskDistributorMockRecorder) DistributeTaskExecuteScheduledTransfer(ctx, payload any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, payload}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskExecuteScheduledTransfer", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskExecuteScheduledTransfer), varargs...)
+}
+
+// DistributeTaskSendPasswordResetEmail mocks base method.
+func (m *MockTaskDistributor) DistributeTaskSendPasswordResetEmail(ctx context.Context, payload *worker.PayloadSendPasswordResetEmail, opts ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, payload}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DistributeTaskSendPasswordResetEmail", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DistributeTaskSendPasswordResetEmail indicates an expected call of DistributeTaskSendPasswordResetEmail.
+func (mr *MockTaskDistributorMockRecorder) DistributeTaskSendPasswordResetEmail(ctx, payload any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, payload}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskSendPasswordResetEmail", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskSendPasswordResetEmail), varargs...)
+}
+
 // DistributeTaskSendVerifyEmail mocks base method.
 func (m *MockTaskDistributor) DistributeTaskSendVerifyEmail(ctx context.Context, payload *worker.PayloadSendVerifyEmail, opts ...asynq.Option) error {
 	m.ctrl.T.Helper()
@@ -17,15 +17,22 @@ const (
 type TaskProcessor interface {
 	Start() error
 	ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendPasswordResetEmail(ctx context.Context, task *asynq.Task) error
+	ProcessTaskDispatchScheduledTransfers(ctx context.Context, task *asynq.Task) error
+	ProcessTaskExecuteScheduledTransfer(ctx context.Context, task *asynq.Task) error
+	ProcessTaskExpireHolds(ctx context.Context, task *asynq.Task) error
 }
 
 type RedisTaskProcessor struct {
-	server *asynq.Server
-	store  db.Store
-	mailer mail.EmailSender
+	server      *asynq.Server
+	store       db.Store
+	mailer      mail.EmailSender
+	distributor TaskDistributor
+	// appBaseURL is where the links in emails point to
+	appBaseURL string
 }
 
-func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, mailer mail.EmailSender) TaskProcessor {
+func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, mailer mail.EmailSender, distributor TaskDistributor, appBaseURL string) TaskProcessor {
 	server := asynq.NewServer(redisOpt, asynq.Config{
 		Queues: map[string]int{
 			QueueCtitical: 10,
@@ -40,9 +47,11 @@ func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, mailer
 	})
 
 	return &RedisTaskProcessor{
-		server: server,
-		store:  store,
-		mailer: mailer,
+		server:      server,
+		store:       store,
+		mailer:      mailer,
+		distributor: distributor,
+		appBaseURL:  appBaseURL,
 	}
 }
 
@@ -50,5 +59,9 @@ func (processor *RedisTaskProcessor) Start() error {
 	mux := asynq.NewServeMux()
 
 	mux.HandleFunc(TaskSendVerifyEmail, processor.ProcessTaskSendVerifyEmail)
+	mux.HandleFunc(TaskSendPasswordResetEmail, processor.ProcessTaskSendPasswordResetEmail)
+	mux.HandleFunc(TaskDispatchScheduledTransfers, processor.ProcessTaskDispatchScheduledTransfers)
+	mux.HandleFunc(TaskExecuteScheduledTransfer, processor.ProcessTaskExecuteScheduledTransfer)
+	mux.HandleFunc(TaskExpireHolds, processor.ProcessTaskExpireHolds)
 	return processor.server.Start(mux)
 }
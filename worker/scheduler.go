@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// NewTaskScheduler creates the scheduler that periodically enqueues the dispatch of due scheduled transfers
+// and the expiry of stale holds
+func NewTaskScheduler(redisOpt asynq.RedisClientOpt, dispatchInterval time.Duration, holdExpiryInterval time.Duration) (*asynq.Scheduler, error) {
+	scheduler := asynq.NewScheduler(redisOpt, &asynq.SchedulerOpts{
+		Logger: NewLogger(),
+	})
+
+	// a periodic task that is still queued makes the next one redundant
+	periodicTasks := []struct {
+		taskType string
+		interval time.Duration
+	}{
+		{taskType: TaskDispatchScheduledTransfers, interval: dispatchInterval},
+		{taskType: TaskExpireHolds, interval: holdExpiryInterval},
+	}
+
+	for _, periodicTask := range periodicTasks {
+		_, err := scheduler.Register(
+			fmt.Sprintf("@every %s", periodicTask.interval),
+			asynq.NewTask(periodicTask.taskType, nil),
+			asynq.Queue(QueueCtitical),
+			asynq.MaxRetry(0),
+			asynq.Unique(periodicTask.interval),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to register task %s: %w", periodicTask.taskType, err)
+		}
+	}
+
+	return scheduler, nil
+}
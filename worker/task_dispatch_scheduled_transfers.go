@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	TaskDispatchScheduledTransfers = "task:dispatch_scheduled_transfers"
+)
+
+// dispatchScheduledTransfersBatch is how many due scheduled transfers are enqueued per dispatch,
+// the rest are picked up by the next one
+const dispatchScheduledTransfersBatch = 500
+
+// ProcessTaskDispatchScheduledTransfers enqueues a task:execute_scheduled_transfer for every due scheduled transfer.
+// It is enqueued periodically by the scheduler; occurrences already waiting in the queue are not enqueued twice.
+func (processor *RedisTaskProcessor) ProcessTaskDispatchScheduledTransfers(ctx context.Context, task *asynq.Task) error {
+	scheduledTransfers, err := processor.store.ListDueScheduledTransfers(ctx, dispatchScheduledTransfersBatch)
+	if err != nil {
+		return fmt.Errorf("failed to list due scheduled transfers: %w", err)
+	}
+
+	for _, scheduledTransfer := range scheduledTransfers {
+		payload := &PayloadExecuteScheduledTransfer{
+			ScheduledTransferID: scheduledTransfer.ID,
+			ScheduledAt:         scheduledTransfer.NextRunAt,
+		}
+
+		opts := []asynq.Option{
+			asynq.MaxRetry(10),
+			asynq.Queue(QueueCtitical),
+		}
+		err := processor.distributor.DistributeTaskExecuteScheduledTransfer(ctx, payload, opts...)
+		if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+			return fmt.Errorf("failed to distribute task to execute scheduled transfer: %w", err)
+		}
+	}
+
+	log.Info().Str("type", task.Type()).Int("due", len(scheduledTransfers)).Msg("processed task")
+	return nil
+}
@@ -0,0 +1,152 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/util"
+)
+
+const (
+	TaskExecuteScheduledTransfer = "task:execute_scheduled_transfer"
+)
+
+// maxScheduledTransferFailures is how many runs in a row may fail for insufficient funds
+// before the scheduled transfer is suspended
+const maxScheduledTransferFailures = 3
+
+// PayloadExecuteScheduledTransfer identifies one occurrence of a scheduled transfer
+type PayloadExecuteScheduledTransfer struct {
+	ScheduledTransferID int64     `json:"scheduled_transfer_id"`
+	ScheduledAt         time.Time `json:"scheduled_at"`
+}
+
+// scheduledTransferKey identifies the occurrence, it is the id of its task
+func scheduledTransferKey(payload *PayloadExecuteScheduledTransfer) string {
+	return fmt.Sprintf("scheduled_transfer:%d:%d", payload.ScheduledTransferID, payload.ScheduledAt.UnixMicro())
+}
+
+// DistributeTaskExecuteScheduledTransfer enqueues the occurrence once,
+// it returns an error wrapping asynq.ErrTaskIDConflict when the occurrence is already enqueued
+func (distributor *RedisTaskDistributor) DistributeTaskExecuteScheduledTransfer(ctx context.Context, payload *PayloadExecuteScheduledTransfer, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	opts = append(opts, asynq.TaskID(scheduledTransferKey(payload)))
+	task := asynq.NewTask(TaskExecuteScheduledTransfer, jsonPayload, opts...)
+	taskInfo, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", taskInfo.Queue).Int("max_retry", taskInfo.MaxRetry).Msg("enqueued task")
+
+	return nil
+}
+
+// ProcessTaskExecuteScheduledTransfer makes the transfer of one occurrence and records it in the history of the schedule.
+// Both happen in one transaction that only goes through while the occurrence is due, so a retried task never moves the money twice.
+func (processor *RedisTaskProcessor) ProcessTaskExecuteScheduledTransfer(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadExecuteScheduledTransfer
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	scheduledTransfer, err := processor.store.GetScheduledTransfer(ctx, payload.ScheduledTransferID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("scheduled transfer doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get scheduled transfer: %w", err)
+	}
+
+	if scheduledTransfer.Status != util.ScheduleActive || !scheduledTransfer.NextRunAt.Equal(payload.ScheduledAt) {
+		log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+			Str("status", scheduledTransfer.Status).Msg("scheduled transfer is no longer due")
+		return nil
+	}
+
+	arg := db.RecordScheduledTransferRunTxParams{
+		ScheduledTransferID: scheduledTransfer.ID,
+		ScheduledAt:         scheduledTransfer.NextRunAt,
+		MaxFailures:         maxScheduledTransferFailures,
+	}
+
+	reason, err := processor.checkScheduledTransferAccounts(ctx, scheduledTransfer)
+	if err != nil {
+		return err
+	}
+
+	if reason != "" {
+		// the accounts won't fix themselves, so there is no point in trying again next time
+		arg.Error = reason
+		arg.Suspend = true
+	} else {
+		arg.Transfer = &db.TransferParams{
+			FromAccountID: scheduledTransfer.FromAccountID,
+			ToAccountID:   scheduledTransfer.ToAccountID,
+			Amount:        scheduledTransfer.Amount,
+		}
+	}
+
+	result, err := processor.store.RecordScheduledTransferRunTx(ctx, arg)
+	if errors.Is(err, db.ErrInsufficientFunds) {
+		// nothing was moved, the failure is recorded on its own
+		arg.Transfer = nil
+		arg.Error = err.Error()
+		arg.InsufficientFunds = true
+		result, err = processor.store.RecordScheduledTransferRunTx(ctx, arg)
+	}
+	if err != nil {
+		if errors.Is(err, db.ErrScheduledRunStale) {
+			// the run was recorded by another task, or the schedule changed, before any money was moved
+			log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+				Msg("scheduled transfer is no longer due")
+			return nil
+		}
+		return fmt.Errorf("failed to run scheduled transfer: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("run_status", result.Run.Status).Str("status", result.ScheduledTransfer.Status).Msg("processed task")
+
+	return nil
+}
+
+// checkScheduledTransferAccounts returns why the accounts can't take the transfer anymore, or an empty string
+func (processor *RedisTaskProcessor) checkScheduledTransferAccounts(ctx context.Context, scheduledTransfer db.ScheduledTransfer) (string, error) {
+	fromAccount, err := processor.store.GetAccount(ctx, scheduledTransfer.FromAccountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "from account doesn't exist", nil
+		}
+		return "", fmt.Errorf("failed to get from account: %w", err)
+	}
+	if fromAccount.Owner != scheduledTransfer.Owner {
+		return "from account doesn't belong to the owner of the scheduled transfer", nil
+	}
+
+	toAccount, err := processor.store.GetAccount(ctx, scheduledTransfer.ToAccountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "to account doesn't exist", nil
+		}
+		return "", fmt.Errorf("failed to get to account: %w", err)
+	}
+
+	if fromAccount.Currency != scheduledTransfer.Currency || toAccount.Currency != scheduledTransfer.Currency {
+		return fmt.Sprintf("accounts don't hold %s anymore", scheduledTransfer.Currency), nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+)
+
+const (
+	TaskExpireHolds = "task:expire_holds"
+)
+
+// expireHoldsBatch is how many expired holds are released per run, the rest are picked up by the next one
+const expireHoldsBatch = 500
+
+// ProcessTaskExpireHolds releases the active holds that are past their expiry time, marking them as expired.
+// It is enqueued periodically by the scheduler.
+func (processor *RedisTaskProcessor) ProcessTaskExpireHolds(ctx context.Context, task *asynq.Task) error {
+	holds, err := processor.store.ListExpiredAccountHolds(ctx, expireHoldsBatch)
+	if err != nil {
+		return fmt.Errorf("failed to list expired holds: %w", err)
+	}
+
+	for _, hold := range holds {
+		_, err := processor.store.ReleaseHoldTx(ctx, db.ReleaseHoldTxParams{
+			HoldID: hold.ID,
+			Expire: true,
+		})
+		// the hold was captured or released meanwhile
+		if errors.Is(err, db.ErrHoldNotActive) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to expire hold [%d]: %w", hold.ID, err)
+		}
+	}
+
+	log.Info().Str("type", task.Type()).Int("expired", len(holds)).Msg("processed task")
+	return nil
+}
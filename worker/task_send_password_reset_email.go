@@ -0,0 +1,95 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+	db "github.com/sangketkit01/simple-bank/db/sqlc"
+	"github.com/sangketkit01/simple-bank/util"
+)
+
+const (
+	TaskSendPasswordResetEmail = "task:send_password_reset_email"
+)
+
+// PayloadSendPasswordResetEmail carries the address the reset was requested for.
+// The user is looked up by the processor, so the caller learns nothing about whether the address is registered.
+type PayloadSendPasswordResetEmail struct {
+	Email string `json:"email"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskSendPasswordResetEmail(ctx context.Context, payload *PayloadSendPasswordResetEmail, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskSendPasswordResetEmail, jsonPayload, opts...)
+	taskInfo, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", taskInfo.Queue).Int("max_retry", taskInfo.MaxRetry).Msg("enqueued task")
+
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendPasswordResetEmail(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendPasswordResetEmail
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := processor.store.GetUserByEmail(ctx, payload.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Info().Str("type", task.Type()).Msg("no user with requested email, skip password reset")
+			return nil
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	secretCode, err := util.GenerateSecretCode()
+	if err != nil {
+		return fmt.Errorf("failed to generate secret code: %w", err)
+	}
+
+	// a retry after a failed email replaces the reset, the unsent link never works
+	passwordReset, err := processor.store.CreatePasswordResetTx(ctx, db.CreatePasswordResetParams{
+		Username:   user.Username,
+		Email:      user.Email,
+		SecretCode: secretCode,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create password reset: %w", err)
+	}
+
+	// the page behind this link asks for the new password and submits it to ResetPassword
+	resetUrl := fmt.Sprintf("%s/reset_password?reset_id=%d&secret_code=%s", processor.appBaseURL, passwordReset.ID, passwordReset.SecretCode)
+
+	subject := "Reset your Simple Bank password"
+	content := fmt.Sprintf(`
+		Hello %s <br/>,
+		We received a request to reset your password. <br/>
+		Please <a href="%s">click here</a> to choose a new one, the link expires in 15 minutes. <br/>
+		If you did not request a password reset, you can ignore this email. <br/>
+	`, user.FullName, resetUrl)
+
+	to := []string{user.Email}
+	err = processor.mailer.SendEmail(subject, content, to, nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("email", user.Email).Msg("processed task")
+
+	return nil
+}